@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"lynk/agent/internal/mib"
+	"lynk/agent/internal/snmp"
+)
+
+// diagOptions are the flags the walk and get subcommands share
+type diagOptions struct {
+	community *string
+	format    *string
+	names     *bool
+	mibs      *string
+}
+
+// diagFlags creates the flag set of a diagnostic subcommand
+func diagFlags(name, usage string) (*flag.FlagSet, diagOptions) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	opts := diagOptions{
+		community: flags.String("community", "public", "SNMP community string"),
+		format:    flags.String("format", "text", "output format, text or json"),
+		names:     flags.Bool("names", true, "show MIB names and enumeration labels"),
+		mibs:      flags.String("mibs", "", "comma separated MIB files naming OIDs beyond the built-in ones"),
+	}
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: agent "+usage)
+		flags.PrintDefaults()
+	}
+	return flags, opts
+}
+
+// diagOutput is the JSON document the walk and get subcommands print
+type diagOutput struct {
+	Host      string           `json:"host"`
+	Responses []*snmp.Response `json:"responses"`
+	Elapsed   time.Duration    `json:"elapsed"` // Nanoseconds, all requests together
+}
+
+// runWalk implements `agent walk <host> [oid]`, printing the subtree under
+// oid as the printer returns it. The OID may be given by name, e.g. prtAlertTable.
+func runWalk(args []string) int {
+	flags, opts := diagFlags("walk", "walk [-community c] [-format text|json] [-names=false] [-mibs files] <host> [oid|name]")
+	flags.Parse(args)
+	if flags.NArg() < 1 || flags.NArg() > 2 || !validFormat(*opts.format) {
+		flags.Usage()
+		return exitFailure
+	}
+	if err := loadMIBs(*opts.mibs); err != nil {
+		log.Printf("Error loading MIBs: %v", err)
+		return exitFailure
+	}
+	host, root := flags.Arg(0), flags.Arg(1)
+	if root != "" {
+		var err error
+		if root, err = mib.Resolve(root); err != nil {
+			log.Printf("Error: %v", err)
+			return exitFailure
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	output := &diagOutput{Host: host}
+	start := time.Now()
+	err := snmp.NewClient(*opts.community).Walk(ctx, host, root, func(response *snmp.Response) error {
+		if !*opts.names {
+			clearNames(response)
+		}
+		output.Responses = append(output.Responses, response)
+		if *opts.format == "text" {
+			writeResponse(os.Stdout, response) // Print as the walk goes, it can take a while
+		}
+		return nil
+	})
+	output.Elapsed = time.Since(start)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		if len(output.Responses) == 0 {
+			return exitFailure
+		}
+	}
+
+	if err := writeDiag(os.Stdout, output, *opts.format); err != nil {
+		log.Printf("Error writing output: %v", err)
+		return exitFailure
+	}
+	if err != nil {
+		return exitFailure
+	}
+	return exitOK
+}
+
+// runGet implements `agent get <host> <oid...>`, reading the OIDs in one
+// request. OIDs may be given by name, e.g. sysName.0.
+func runGet(args []string) int {
+	flags, opts := diagFlags("get", "get [-community c] [-format text|json] [-names=false] [-mibs files] <host> <oid|name...>")
+	flags.Parse(args)
+	if flags.NArg() < 2 || !validFormat(*opts.format) {
+		flags.Usage()
+		return exitFailure
+	}
+	if err := loadMIBs(*opts.mibs); err != nil {
+		log.Printf("Error loading MIBs: %v", err)
+		return exitFailure
+	}
+	host := flags.Arg(0)
+	oids := make([]string, 0, flags.NArg()-1)
+	for _, name := range flags.Args()[1:] {
+		oid, err := mib.Resolve(name)
+		if err != nil {
+			log.Printf("Error: %v", err)
+			return exitFailure
+		}
+		oids = append(oids, oid)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	response, err := snmp.NewClient(*opts.community).Get(ctx, host, oids)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return exitFailure
+	}
+	if !*opts.names {
+		clearNames(response)
+	}
+
+	output := &diagOutput{Host: host, Responses: []*snmp.Response{response}, Elapsed: response.Elapsed}
+	if *opts.format == "text" {
+		writeResponse(os.Stdout, response)
+	}
+	if err := writeDiag(os.Stdout, output, *opts.format); err != nil {
+		log.Printf("Error writing output: %v", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// validFormat reports whether format is one the diagnostic subcommands print
+func validFormat(format string) bool {
+	return format == "text" || format == "json"
+}
+
+// clearNames drops the MIB names and labels of a response's varbinds
+func clearNames(response *snmp.Response) {
+	for i := range response.Varbinds {
+		response.Varbinds[i].Name = ""
+		response.Varbinds[i].Label = ""
+	}
+}
+
+// writeResponse prints the varbinds of a request followed by its timing
+func writeResponse(w io.Writer, response *snmp.Response) {
+	for _, varbind := range response.Varbinds {
+		fmt.Fprintln(w, varbind)
+	}
+	fmt.Fprintf(w, "# request %d: %d varbinds in %s\n", response.Request, len(response.Varbinds), response.Elapsed.Round(time.Microsecond))
+}
+
+// writeDiag finishes the output: the whole document as JSON, or a summary
+// line after the requests already printed as text
+func writeDiag(w io.Writer, output *diagOutput, format string) error {
+	if format == "json" {
+		if output.Responses == nil {
+			output.Responses = []*snmp.Response{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	}
+
+	count := 0
+	for _, response := range output.Responses {
+		count += len(response.Varbinds)
+	}
+	_, err := fmt.Fprintf(w, "# %s: %d requests, %d varbinds in %s\n", output.Host, len(output.Responses), count, output.Elapsed.Round(time.Microsecond))
+	return err
+}
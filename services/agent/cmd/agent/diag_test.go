@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+func diagResponses() []*snmp.Response {
+	return []*snmp.Response{
+		{Request: 1, Elapsed: 1500 * time.Microsecond, Varbinds: []snmp.Varbind{
+			{OID: "1.3.6.1.2.1.1.5.0", Name: "sysName.0", Type: "OctetString", Value: "BRN3C2AF4"},
+			{OID: "1.3.6.1.2.1.25.3.5.1.1.1", Name: "hrPrinterStatus.1", Type: "Integer", Value: 3, Label: "idle"},
+		}},
+		{Request: 2, Elapsed: 800 * time.Microsecond, Varbinds: []snmp.Varbind{
+			{OID: "1.3.6.1.2.1.43.5.1.1.17.1", Name: "prtGeneralSerialNumber.1", Type: "NoSuchInstance"},
+		}},
+	}
+}
+
+func TestWriteDiagText(t *testing.T) {
+	var out bytes.Buffer
+	output := &diagOutput{Host: "10.0.0.5", Elapsed: 2300 * time.Microsecond}
+	for _, response := range diagResponses() {
+		writeResponse(&out, response)
+		output.Responses = append(output.Responses, response)
+	}
+	if err := writeDiag(&out, output, "text"); err != nil {
+		t.Fatalf("writeDiag: %v", err)
+	}
+
+	want := `1.3.6.1.2.1.1.5.0 (sysName.0) = OctetString: "BRN3C2AF4"
+1.3.6.1.2.1.25.3.5.1.1.1 (hrPrinterStatus.1) = Integer: idle(3)
+# request 1: 2 varbinds in 1.5ms
+1.3.6.1.2.1.43.5.1.1.17.1 (prtGeneralSerialNumber.1) = NoSuchInstance
+# request 2: 1 varbinds in 800µs
+# 10.0.0.5: 2 requests, 3 varbinds in 2.3ms
+`
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestWriteDiagWithoutNames(t *testing.T) {
+	response := diagResponses()[0]
+	clearNames(response)
+	var out bytes.Buffer
+	writeResponse(&out, response)
+
+	want := `1.3.6.1.2.1.1.5.0 = OctetString: "BRN3C2AF4"
+1.3.6.1.2.1.25.3.5.1.1.1 = Integer: 3
+# request 1: 2 varbinds in 1.5ms
+`
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestWriteDiagJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeDiag(&out, &diagOutput{Host: "10.0.0.5", Responses: diagResponses(), Elapsed: time.Millisecond}, "json"); err != nil {
+		t.Fatalf("writeDiag: %v", err)
+	}
+	var decoded struct {
+		Host      string `json:"host"`
+		Elapsed   int64  `json:"elapsed"`
+		Responses []struct {
+			Request  int `json:"request"`
+			Varbinds []map[string]interface{}
+		} `json:"responses"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("output isn't JSON: %v\n%s", err, out.String())
+	}
+	if decoded.Host != "10.0.0.5" || decoded.Elapsed != int64(time.Millisecond) || len(decoded.Responses) != 2 {
+		t.Fatalf("output = %s", out.String())
+	}
+	status := decoded.Responses[0].Varbinds[1]
+	if status["name"] != "hrPrinterStatus.1" || status["label"] != "idle" || status["value"] != float64(3) {
+		t.Errorf("varbind = %v", status)
+	}
+	if missing := decoded.Responses[1].Varbinds[0]; missing["value"] != nil || missing["type"] != "NoSuchInstance" {
+		t.Errorf("varbind without a value = %v", missing)
+	}
+
+	// A walk that returned nothing is an empty list, not null
+	out.Reset()
+	writeDiag(&out, &diagOutput{Host: "10.0.0.5"}, "json")
+	if !bytes.Contains(out.Bytes(), []byte(`"responses": []`)) {
+		t.Errorf("output without responses = %s", out.String())
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"lynk/agent/internal/config"
+	"lynk/agent/internal/inventory"
+	"lynk/agent/internal/store"
+)
+
+// runInventory implements `agent inventory`, exporting the fleet inventory
+// from a saved history, and `agent inventory diff old new`
+func runInventory(args []string) int {
+	if len(args) > 0 && args[0] == "diff" {
+		return runInventoryDiff(args[1:])
+	}
+
+	flags := flag.NewFlagSet("inventory", flag.ExitOnError)
+	configPath := flags.String("config", "", "JSON file with the name, site, department and tags of each printer")
+	statePath := flags.String("state", "", "history file written by the agent's -state flag (required)")
+	columns := flags.String("columns", strings.Join(inventory.DefaultColumns, ","),
+		"comma separated columns, available: "+strings.Join(inventory.Columns(), ", "))
+	format := flags.String("format", inventory.FormatCSV, "output format, csv, xlsx-csv or json")
+	output := flags.String("o", "", "write the inventory to this file instead of stdout")
+	flags.Parse(args)
+
+	if *statePath == "" {
+		fmt.Fprintln(os.Stderr, "inventory: -state is required")
+		flags.Usage()
+		return exitFailure
+	}
+
+	cfg := &config.Config{}
+	if *configPath != "" {
+		var err error
+		if cfg, err = config.Load(*configPath); err != nil {
+			log.Printf("Error loading config: %v", err)
+			return exitFailure
+		}
+	}
+
+	history := store.New(store.DefaultMaxSamples)
+	if err := history.Load(*statePath); err != nil {
+		log.Printf("Error loading state: %v", err)
+		return exitFailure
+	}
+
+	inv, err := inventory.Build(history.LatestAll(), cfg, inventory.ParseColumns(*columns))
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return exitFailure
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			log.Printf("Error creating inventory: %v", err)
+			return exitFailure
+		}
+		defer out.Close()
+	}
+	if err := inv.Write(out, *format); err != nil {
+		log.Printf("Error writing inventory: %v", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// runInventoryDiff compares two exported inventories. It exits with 2 when they differ.
+func runInventoryDiff(args []string) int {
+	flags := flag.NewFlagSet("inventory diff", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print the differences as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: agent inventory diff [-json] <old> <new>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitFailure
+	}
+
+	before, err := readInventory(flags.Arg(0))
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return exitFailure
+	}
+	after, err := readInventory(flags.Arg(1))
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return exitFailure
+	}
+
+	diffs := inventory.Diff(before, after)
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(diffs)
+	} else {
+		inventory.WriteDiff(os.Stdout, diffs)
+	}
+	if len(diffs) > 0 {
+		return 2
+	}
+	return exitOK
+}
+
+// readInventory loads an exported inventory file
+func readInventory(path string) (*inventory.Inventory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	inv, err := inventory.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return inv, nil
+}
@@ -1,39 +1,491 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
+	"lynk/agent/internal/alerts"
+	"lynk/agent/internal/audit"
+	"lynk/agent/internal/config"
+	"lynk/agent/internal/events"
+	"lynk/agent/internal/forecast"
+	"lynk/agent/internal/health"
+	"lynk/agent/internal/ipp"
+	"lynk/agent/internal/mib"
+	"lynk/agent/internal/pjl"
 	"lynk/agent/internal/scheduler"
+	"lynk/agent/internal/schema"
 	"lynk/agent/internal/snmp"
+	"lynk/agent/internal/store"
+	"lynk/agent/internal/web"
 )
 
+// Exit codes reported to the service manager
+const (
+	exitOK              = 0 // Clean shutdown
+	exitFailure         = 1 // Startup, server or storage failure
+	exitShutdownTimeout = 3 // Polls were still running when the grace period ran out
+)
+
+// collector polls printers over one protocol
+type collector interface {
+	Probe(ctx context.Context, host string) error
+	PollContext(ctx context.Context, host string) (*snmp.PrinterStatus, error)
+}
+
+// agent ties the polling components together
+type agent struct {
+	scheduler *scheduler.Scheduler
+	client    *snmp.Client
+	ipp       *ipp.Client
+	pjl       *pjl.Client
+	history   *store.Store
+	events    *events.Log
+	audit     *audit.Log
+	tracker   *health.Tracker
+	alerts    *alerts.Engine
+	window    time.Duration
+	config    *config.Config
+	explain   bool // Print where every field came from instead of the status
+	schema    int  // Printer JSON version the API serves by default
+}
+
 func main() {
-	// Your Brother printer
-	printers := []string{"192.168.50.250"}
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "report":
+			os.Exit(runReport(os.Args[2:]))
+		case "inventory":
+			os.Exit(runInventory(os.Args[2:]))
+		case "walk":
+			os.Exit(runWalk(os.Args[2:]))
+		case "get":
+			os.Exit(runGet(os.Args[2:]))
+		}
+	}
+	os.Exit(run())
+}
+
+// run starts the agent and returns its exit code once it has shut down
+func run() int {
+	community := flag.String("community", "public", "SNMP community string")
+	ippPath := flag.String("ipp-path", "/ipp/print", "resource path of the printers polled over IPP")
+	listen := flag.String("listen", "", "serve the web dashboard on this address (e.g. :8080) and keep polling")
+	interval := flag.Duration("interval", 5*time.Minute, "polling interval when serving the dashboard")
+	configPath := flag.String("config", "", "JSON file listing the printers to poll and their site, department and tags")
+	statePath := flag.String("state", "", "file to load the poll history from and flush it to on shutdown")
+	auditPath := flag.String("audit-log", "", "file to append device change events to (default: keep them in memory)")
+	grace := flag.Duration("grace", 30*time.Second, "how long in-flight polls may run after a shutdown signal")
+	pollTimeout := flag.Duration("poll-timeout", 2*time.Minute, "deadline for polling a single printer")
+	failureThreshold := flag.Int("failure-threshold", 3, "consecutive failed polls before a printer is treated as offline")
+	maxBackoff := flag.Duration("max-backoff", time.Hour, "longest delay between probes of an offline printer")
+	rulesPath := flag.String("alert-rules", "", "JSON file of alert rules (default: warn when a supply runs out within 7 days)")
+	window := flag.Duration("forecast-window", forecast.DefaultWindow, "history used to forecast supply depletion")
+	explain := flag.Bool("explain", false, "show which source and OID every field was taken from, and conflicting values")
+	mibs := flag.String("mibs", "", "comma separated MIB files naming OIDs beyond the built-in SNMPv2, HOST-RESOURCES and Printer MIBs")
+	schemaVersion := flag.String("schema", strconv.Itoa(schema.Current), "printer JSON version the API serves by default: 2, or 1 for the flat pre-v2 shape")
+	flag.Parse()
+
+	cfg, err := loadConfig(*configPath, flag.Args())
+	if err != nil {
+		log.Printf("Error loading config: %v", err)
+		return exitFailure
+	}
+	version, err := schema.ParseVersion(*schemaVersion, schema.Current)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return exitFailure
+	}
+	if err := loadMIBs(*mibs); err != nil {
+		log.Printf("Error loading MIBs: %v", err)
+		return exitFailure
+	}
+
+	// Stop scheduling new polls on SIGINT or SIGTERM. Polls run on their own
+	// context, which is only cancelled once the grace period runs out, so the
+	// ones in flight can still finish and be recorded.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	polls, cancelPolls := context.WithCancel(context.Background())
+	defer cancelPolls()
+
+	rules := append([]alerts.Rule(nil), alerts.DefaultRules...)
+	if *rulesPath != "" {
+		loaded, err := alerts.LoadRules(*rulesPath)
+		if err != nil {
+			log.Printf("Error loading alert rules: %v", err)
+			return exitFailure
+		}
+		rules = loaded
+	} else {
+		for i := range rules {
+			if err := rules[i].Compile(); err != nil {
+				log.Printf("Error compiling alert rules: %v", err)
+				return exitFailure
+			}
+		}
+	}
+
+	a := &agent{
+		// Create SNMP client
+		client: snmp.NewClient(*community),
+		ipp:    ipp.NewClient(*ippPath),
+		pjl:    pjl.NewClient(),
+		// Create scheduler with 5 worker goroutines
+		scheduler: scheduler.New(5),
+		history:   store.New(store.DefaultMaxSamples),
+		events:    events.NewLog(events.DefaultMaxEvents),
+		// Offline printers are first probed again one interval later
+		tracker: health.NewTracker(*failureThreshold, *interval, *maxBackoff),
+		alerts:  alerts.NewEngine(rules),
+		window:  *window,
+		config:  cfg,
+		explain: *explain,
+		schema:  version,
+	}
+	a.scheduler.SetJobTimeout(*pollTimeout)
+
+	if *statePath != "" {
+		if err := a.history.Load(*statePath); err != nil {
+			log.Printf("Error loading state: %v", err)
+			return exitFailure
+		}
+	}
+
+	a.audit = audit.NewLog()
+	if *auditPath != "" {
+		if a.audit, err = audit.OpenLog(*auditPath); err != nil {
+			log.Printf("Error opening audit log: %v", err)
+			return exitFailure
+		}
+	}
+
+	code := exitOK
+	if *listen == "" {
+		fmt.Println("Starting printer monitoring...")
+		fmt.Println(strings.Repeat("=", 50))
+
+		a.pollAll(ctx, polls, true)
+		fmt.Println("Monitoring complete!")
+	} else {
+		code = a.serve(ctx, polls, *listen, *interval)
+	}
+
+	// Give in-flight polls the grace period to finish, then flush everything
+	if err := a.shutdown(*grace, cancelPolls); err != nil {
+		log.Printf("Shutdown: polls still running after %s, abandoning them", *grace)
+		code = exitShutdownTimeout
+	}
+
+	stats := a.scheduler.Stats()
+	log.Printf("Polls: %d submitted, %d completed, %d failed, %d timed out, %d panicked, %d still running",
+		stats.Submitted, stats.Completed, stats.Failed, stats.TimedOut, stats.Panicked, stats.Abandoned)
+
+	if *statePath != "" {
+		if err := a.history.Save(*statePath); err != nil {
+			log.Printf("Error flushing state: %v", err)
+			code = exitFailure
+		}
+	}
+	if err := a.audit.Close(); err != nil {
+		log.Printf("Error flushing audit log: %v", err)
+		code = exitFailure
+	}
+	os.Stdout.Sync()
+	return code
+}
+
+// loadConfig reads the config file, falling back to the hosts given on the command line
+func loadConfig(path string, hosts []string) (*config.Config, error) {
+	if path != "" {
+		cfg, err := config.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		// Extra hosts on the command line are polled too
+		for _, host := range hosts {
+			if !contains(cfg.Hosts(), host) {
+				cfg.Targets = append(cfg.Targets, config.Target{Host: host})
+			}
+		}
+		return cfg, nil
+	}
+
+	if len(hosts) == 0 {
+		// Your Brother printer
+		hosts = []string{"192.168.50.250"}
+	}
+	return config.FromHosts(hosts), nil
+}
+
+// loadMIBs adds the MIB files of a comma separated list to the registry
+// used to name OIDs, in order, so a file can import from the ones before it
+func loadMIBs(list string) error {
+	for _, path := range strings.Split(list, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		if err := mib.LoadFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contains reports whether list contains value
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
 
-	// Create SNMP client
-	client := snmp.NewClient("public")
+// serve polls the printers every interval and serves the dashboard until ctx
+// is cancelled. The polls run on the polls context, see pollAll.
+func (a *agent) serve(ctx, polls context.Context, listen string, interval time.Duration) int {
+	server := &http.Server{
+		Addr: listen,
+		Handler: web.New(web.Options{
+			Store:  a.history,
+			Events: a.events,
+			Audit:  a.audit,
+			Health: a.tracker,
+			Alerts: a.alerts,
+			Window: a.window,
+			Config: a.config,
+			Schema: a.schema,
+			// Printers that missed two polls in a row are shown as offline
+			StaleAfter: 2*interval + time.Minute,
+		}),
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Serving dashboard on %s", listen)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	code := exitOK
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-	// Create scheduler with 5 worker goroutines
-	s := scheduler.New(5)
+poll:
+	for {
+		a.pollAll(ctx, polls, false)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Shutdown signal received, stopping polling")
+			break poll
+		case err := <-serverErr:
+			log.Printf("Dashboard server failed: %v", err)
+			code = exitFailure
+			break poll
+		case <-ticker.C:
+		}
+	}
 
-	fmt.Println("Starting printer monitoring...")
-	fmt.Println(strings.Repeat("=", 50))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error stopping dashboard server: %v", err)
+	}
+	return code
+}
 
-	for _, host := range printers {
+// pollAll polls every printer that is due once and records the results. The
+// polls run on the polls context. Once ctx is cancelled, polls that have not
+// started are skipped and pollAll returns without waiting for the ones in
+// flight, which record their results when they finish unless polls is
+// cancelled first.
+func (a *agent) pollAll(ctx, polls context.Context, verbose bool) {
+	results := scheduler.NewStream[*snmp.PrinterStatus](polls, a.scheduler)
+	for _, host := range a.config.Hosts() {
 		h := host
-		s.Submit(func() {
-			result, err := client.Poll(h)
-			if err != nil {
-				log.Printf("Error polling %s: %v", h, err)
-				return
+		if !a.tracker.Allow(h, time.Now()) {
+			continue // Offline printer still backing off
+		}
+		results.Submit(h, func(pollCtx context.Context) (*snmp.PrinterStatus, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err // Shutting down, don't start new polls
 			}
-			fmt.Println(result.String())
+			return a.poll(pollCtx, h)
 		})
 	}
+	results.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			go func() {
+				for range results.Results() {
+				}
+			}()
+			return
+		case result, ok := <-results.Results():
+			if !ok {
+				return
+			}
+			if result.Err != nil {
+				if !errors.Is(result.Err, context.Canceled) {
+					log.Printf("Error polling %s: %v", result.Name, result.Err)
+				}
+				continue
+			}
+			if verbose && a.explain {
+				fmt.Println(result.Value.Explain())
+			} else if verbose {
+				fmt.Println(result.Value.String())
+				fmt.Printf("   Poll Duration: %s\n", result.Duration.Round(time.Millisecond))
+			}
+		}
+	}
+}
+
+// shutdown waits up to grace for the polls in flight to finish and record
+// their results, then cancels the ones still running
+func (a *agent) shutdown(grace time.Duration, cancelPolls context.CancelFunc) error {
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	err := a.scheduler.Shutdown(ctx)
+	if err != nil {
+		cancelPolls()
+	}
+	return err
+}
+
+// poll polls a single printer and updates its health. Offline printers get a
+// cheap probe first so they don't cost a full poll's worth of timeouts.
+func (a *agent) poll(ctx context.Context, host string) (*snmp.PrinterStatus, error) {
+	target := a.config.Target(host)
+	c := a.collector(target.Protocol)
+	wasOffline := a.tracker.State(host) == health.StateOpen
+	if wasOffline {
+		err := c.Probe(ctx, host)
+		if err != nil && target.Fallback != "" {
+			err = a.collector(target.Fallback).Probe(ctx, host)
+		}
+		if err != nil {
+			a.recordFailure(host, err)
+			return nil, err
+		}
+	}
+
+	status, err := c.PollContext(ctx, host)
+	if err != nil && target.Fallback != "" && ctx.Err() == nil {
+		// Old printers often answer a simpler protocol when SNMP is broken
+		var fallbackErr error
+		if status, fallbackErr = a.collector(target.Fallback).PollContext(ctx, host); fallbackErr == nil {
+			log.Printf("Polled %s over %s after: %v", host, target.Fallback, err)
+			err = nil
+		}
+	}
+	if err != nil {
+		a.recordFailure(host, err)
+		return nil, err
+	}
+
+	status = a.merge(ctx, target, status)
+
+	if wasOffline {
+		log.Printf("Printer %s is reachable again", host)
+	}
+	a.tracker.RecordSuccess(host, time.Now())
+	a.record(status)
+	a.evaluateAlerts(status)
+	return status, nil
+}
+
+// merge polls the target's merge protocols and combines their fields with
+// the status, which takes precedence unless DefaultPrecedence says otherwise.
+// Protocols that fail are left out.
+func (a *agent) merge(ctx context.Context, target config.Target, status *snmp.PrinterStatus) *snmp.PrinterStatus {
+	results := []snmp.Result{{Collector: status.Protocol, Protocol: status.Protocol, Status: status}}
+	for _, protocol := range target.Merge {
+		if protocol == status.Protocol {
+			continue
+		}
+		extra, err := a.collector(protocol).PollContext(ctx, target.Host)
+		if err != nil {
+			log.Printf("Error polling %s over %s for merging: %v", target.Host, protocol, err)
+			continue
+		}
+		results = append(results, snmp.Result{Collector: protocol, Protocol: protocol, Status: extra})
+	}
+	return snmp.Merge(snmp.DefaultPrecedence, results...)
+}
+
+// collector returns the collector for a protocol, SNMP by default
+func (a *agent) collector(protocol string) collector {
+	switch protocol {
+	case config.ProtocolIPP:
+		return a.ipp
+	case config.ProtocolPJL:
+		return a.pjl
+	}
+	return a.client
+}
 
-	s.Wait()
-	fmt.Println("Monitoring complete!")
-}
\ No newline at end of file
+// record detects reboots, counter events and inventory changes against the
+// previous poll, then stores the poll with its monotonic page count, which
+// continues from the last poll that read the page counter
+func (a *agent) record(status *snmp.PrinterStatus) {
+	previous, _ := a.history.Latest(status.Host)
+	detected := events.Detect(previous, status)
+	changes := audit.Diff(previous, status)
+	counted, _ := a.history.LatestWhere(status.Host, events.PagesReported)
+	status.MonotonicPages = events.MonotonicPages(counted, status)
+
+	for _, event := range detected {
+		log.Printf("Event %s on %s: %s", event.Type, event.Host, event.Detail)
+	}
+	a.events.Add(detected...)
+
+	for _, change := range changes {
+		log.Printf("Change on %s: %s", change.Host, change)
+	}
+	if err := a.audit.Add(changes...); err != nil {
+		log.Printf("Error writing audit log: %v", err)
+	}
+	a.history.Add(status)
+}
+
+// evaluateAlerts runs the alert rules against a fresh poll and its supply forecasts
+func (a *agent) evaluateAlerts(status *snmp.PrinterStatus) {
+	forecasts := forecast.Supplies(a.history.History(status.Host), a.window)
+
+	subjects := []alerts.Subject{alerts.PrinterSubject(status)}
+	subjects = append(subjects, alerts.SupplySubjects(forecasts)...)
+	subjects = append(subjects, alerts.OutputSubjects(status)...)
+	subjects = append(subjects, alerts.CoverSubjects(status)...)
+	subjects = append(subjects, alerts.StorageSubjects(status)...)
+	subjects = append(subjects, alerts.DeviceSubjects(status)...)
+	for _, alert := range a.alerts.Evaluate(status.Host, subjects, time.Now()) {
+		log.Printf("Alert [%s] %s: %s", alert.Severity, alert.Host, alert.Message)
+	}
+}
+
+// recordFailure counts a failed poll, logging when the printer goes offline
+func (a *agent) recordFailure(host string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return // Shutting down, not the printer's fault
+	}
+	if a.tracker.RecordFailure(host, err, time.Now()) {
+		target, _ := a.tracker.Get(host)
+		log.Printf("Printer %s is offline since %s, backing off", host, target.OfflineSince.Format("2006-01-02 15:04:05"))
+	}
+}
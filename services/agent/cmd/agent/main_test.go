@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"lynk/agent/internal/alerts"
+	"lynk/agent/internal/audit"
+	"lynk/agent/internal/config"
+	"lynk/agent/internal/events"
+	"lynk/agent/internal/health"
+	"lynk/agent/internal/ipp"
+	"lynk/agent/internal/pjl"
+	"lynk/agent/internal/scheduler"
+	"lynk/agent/internal/snmp"
+	"lynk/agent/internal/store"
+)
+
+// pjlStandIn starts a local TCP server that answers PJL INFO queries like an
+// old printer on port 9100. Anything else, such as an IPP request, gets the
+// connection closed.
+func pjlStandIn(t *testing.T) string {
+	t.Helper()
+	host, _ := slowPJLStandIn(t, 0)
+	return host
+}
+
+// slowPJLStandIn is a pjlStandIn that waits delay before each reply. The
+// returned channel is closed once the first query arrives.
+func slowPJLStandIn(t *testing.T, delay time.Duration) (string, <-chan struct{}) {
+	t.Helper()
+	started := make(chan struct{})
+	var once sync.Once
+	replies := map[string]string{
+		pjl.InfoID:        `"HP LaserJet 4250"`,
+		pjl.InfoStatus:    "CODE=10001\r\nDISPLAY=\"READY\"\r\nONLINE=TRUE",
+		pjl.InfoPageCount: "PAGECOUNT=184022",
+		pjl.InfoConfig:    "DUPLEX",
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					line = strings.TrimSpace(strings.TrimPrefix(line, "\x1b%-12345X"))
+					if line == "@PJL" {
+						continue
+					}
+					reply, ok := replies[line]
+					if !ok {
+						return
+					}
+					once.Do(func() { close(started) })
+					time.Sleep(delay)
+					conn.Write([]byte(line + "\r\n" + reply + "\r\n\f"))
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String(), started
+}
+
+// testAgent returns an agent polling a single target
+func testAgent(target config.Target) *agent {
+	return &agent{
+		client:  snmp.NewClient("public"),
+		ipp:     ipp.NewClient(""),
+		pjl:     pjl.NewClient(),
+		history: store.New(store.DefaultMaxSamples),
+		events:  events.NewLog(events.DefaultMaxEvents),
+		audit:   audit.NewLog(),
+		tracker: health.NewTracker(1, time.Minute, time.Hour),
+		alerts:  alerts.NewEngine(nil),
+		window:  time.Hour,
+		config:  &config.Config{Targets: []config.Target{target}},
+	}
+}
+
+func TestPollFallback(t *testing.T) {
+	host := pjlStandIn(t)
+	a := testAgent(config.Target{Host: host, Protocol: config.ProtocolIPP, Fallback: config.ProtocolPJL})
+
+	status, err := a.poll(context.Background(), host)
+	if err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if status.Protocol != config.ProtocolPJL || status.Model != "HP LaserJet 4250" || status.TotalPages != 184022 {
+		t.Errorf("protocol, model, pages = %s, %q, %d", status.Protocol, status.Model, status.TotalPages)
+	}
+	if state := a.tracker.State(host); state == health.StateOpen {
+		t.Errorf("tracker state = %s after a fallback poll", state)
+	}
+	if latest, ok := a.history.Latest(host); !ok || latest.Protocol != config.ProtocolPJL {
+		t.Errorf("history = %+v, %t", latest, ok)
+	}
+}
+
+func TestPollWithoutFallback(t *testing.T) {
+	host := pjlStandIn(t)
+	a := testAgent(config.Target{Host: host, Protocol: config.ProtocolIPP})
+
+	if _, err := a.poll(context.Background(), host); err == nil {
+		t.Fatal("IPP poll of a PJL-only printer succeeded")
+	}
+	if state := a.tracker.State(host); state != health.StateOpen {
+		t.Errorf("tracker state = %s, want %s", state, health.StateOpen)
+	}
+
+	// Once offline, the fallback's probe brings the printer back
+	a.config.Targets[0].Fallback = config.ProtocolPJL
+	if _, err := a.poll(context.Background(), host); err != nil {
+		t.Fatalf("poll with a fallback: %v", err)
+	}
+	if state := a.tracker.State(host); state == health.StateOpen {
+		t.Errorf("tracker state = %s after recovering", state)
+	}
+}
+
+func TestShutdownDuringPoll(t *testing.T) {
+	host, started := slowPJLStandIn(t, 100*time.Millisecond)
+	a := testAgent(config.Target{Host: host, Protocol: config.ProtocolPJL})
+	// Nothing listens there, its poll is still queued behind the slow one
+	queued := "127.0.0.1:1"
+	a.config.Targets = append(a.config.Targets, config.Target{Host: queued, Protocol: config.ProtocolPJL})
+	a.scheduler = scheduler.New(1)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	polls, cancelPolls := context.WithCancel(context.Background())
+	defer cancelPolls()
+
+	returned := make(chan struct{})
+	go func() {
+		defer close(returned)
+		a.pollAll(ctx, polls, false)
+	}()
+
+	<-started
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := process.Signal(os.Interrupt); err != nil {
+		t.Skipf("can't signal the test process: %v", err)
+	}
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("pollAll still waiting for the poll in flight after the signal")
+	}
+
+	if err := a.shutdown(5*time.Second, cancelPolls); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if latest, ok := a.history.Latest(host); !ok || latest.TotalPages != 184022 {
+		t.Errorf("poll in flight during the signal wasn't recorded: %+v", latest)
+	}
+	if _, ok := a.history.Latest(queued); ok {
+		t.Error("a poll was started after the signal")
+	}
+	if _, ok := a.tracker.Get(queued); ok {
+		t.Error("the skipped poll counted as a failure")
+	}
+}
+
+func TestShutdownGraceExpired(t *testing.T) {
+	host, started := slowPJLStandIn(t, time.Second)
+	a := testAgent(config.Target{Host: host, Protocol: config.ProtocolPJL})
+	a.scheduler = scheduler.New(1)
+
+	ctx, stop := context.WithCancel(context.Background())
+	polls, cancelPolls := context.WithCancel(context.Background())
+	defer cancelPolls()
+	go a.pollAll(ctx, polls, false)
+	<-started
+	stop()
+
+	if err := a.shutdown(50*time.Millisecond, cancelPolls); err == nil {
+		t.Error("shutdown didn't report the poll still running")
+	}
+	if polls.Err() == nil {
+		t.Error("polls weren't cancelled when the grace period ran out")
+	}
+}
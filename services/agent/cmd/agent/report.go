@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"lynk/agent/internal/config"
+	"lynk/agent/internal/report"
+	"lynk/agent/internal/store"
+)
+
+// runReport implements `agent report`, exporting page usage from a saved history
+func runReport(args []string) int {
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	configPath := flags.String("config", "", "JSON file with the site, department and tags of each printer")
+	statePath := flags.String("state", "", "history file written by the agent's -state flag (required)")
+	month := flags.String("month", time.Now().Format("2006-01"), "month to report on, YYYY-MM")
+	by := flags.String("by", report.ByDepartment, "group pages by device, site, department or tag")
+	format := flags.String("format", report.FormatCSV, "output format, csv or json")
+	output := flags.String("o", "", "write the report to this file instead of stdout")
+	flags.Parse(args)
+
+	if *statePath == "" {
+		fmt.Fprintln(os.Stderr, "report: -state is required")
+		flags.Usage()
+		return exitFailure
+	}
+
+	cfg := &config.Config{}
+	if *configPath != "" {
+		var err error
+		if cfg, err = config.Load(*configPath); err != nil {
+			log.Printf("Error loading config: %v", err)
+			return exitFailure
+		}
+	}
+
+	history := store.New(store.DefaultMaxSamples)
+	if err := history.Load(*statePath); err != nil {
+		log.Printf("Error loading state: %v", err)
+		return exitFailure
+	}
+
+	period, err := report.ParseMonth(*month)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return exitFailure
+	}
+	usage, err := report.Usage(history, cfg, period, *by)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return exitFailure
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			log.Printf("Error creating report: %v", err)
+			return exitFailure
+		}
+		defer out.Close()
+	}
+	if err := usage.Write(out, *format); err != nil {
+		log.Printf("Error writing report: %v", err)
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,102 @@
+package alerts
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Engine evaluates rules after every poll and keeps the alerts that are firing
+type Engine struct {
+	rules  []Rule
+	mu     sync.Mutex
+	active map[string]map[string]Alert // host -> key -> alert
+}
+
+// NewEngine creates an engine for compiled rules
+func NewEngine(rules []Rule) *Engine {
+	return &Engine{
+		rules:  rules,
+		active: make(map[string]map[string]Alert),
+	}
+}
+
+// Evaluate replaces the alerts of host with the rules that match its current
+// subjects. It returns the alerts that started firing with this evaluation.
+func (e *Engine) Evaluate(host string, subjects []Subject, now time.Time) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	previous := e.active[host]
+	current := make(map[string]Alert)
+	var fired []Alert
+
+	for _, subject := range subjects {
+		for i := range e.rules {
+			rule := &e.rules[i]
+			if !rule.Matches(subject) {
+				continue
+			}
+
+			key := alertKey(rule, subject)
+			alert, ok := previous[key]
+			if !ok {
+				alert = Alert{
+					Rule:     rule.Name,
+					Severity: rule.Severity,
+					Host:     host,
+					Subject:  subject.Name,
+					ID:       subject.ID,
+					Message:  fmt.Sprintf("%s: %s (%s)", subject.Name, rule.Condition, rule.Name),
+					Since:    now,
+				}
+				fired = append(fired, alert)
+			}
+			current[key] = alert
+		}
+	}
+
+	e.active[host] = current
+	return fired
+}
+
+// alertKey identifies an alert within its host. Subjects are told apart by
+// their scope and ID rather than their name, since two trays or cartridges
+// may well share a description.
+func alertKey(rule *Rule, subject Subject) string {
+	id := subject.ID
+	if id == "" {
+		id = subject.Name
+	}
+	return rule.Name + "/" + subject.Scope + "/" + id
+}
+
+// Active returns every firing alert, optionally limited to one host
+func (e *Engine) Active(host string) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	alerts := []Alert{}
+	for h, byKey := range e.active {
+		if host != "" && h != host {
+			continue
+		}
+		for _, alert := range byKey {
+			alerts = append(alerts, alert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Host != alerts[j].Host {
+			return alerts[i].Host < alerts[j].Host
+		}
+		if alerts[i].Subject != alerts[j].Subject {
+			return alerts[i].Subject < alerts[j].Subject
+		}
+		if alerts[i].ID != alerts[j].ID {
+			return alerts[i].ID < alerts[j].ID
+		}
+		return alerts[i].Rule < alerts[j].Rule
+	})
+	return alerts
+}
@@ -0,0 +1,75 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+func compiled(t *testing.T, rules ...Rule) []Rule {
+	t.Helper()
+	for i := range rules {
+		if err := rules[i].Compile(); err != nil {
+			t.Fatalf("Compile: %v", err)
+		}
+	}
+	return rules
+}
+
+func TestEvaluateSubjectsWithTheSameName(t *testing.T) {
+	engine := NewEngine(compiled(t, Rule{Name: "output_full", Scope: ScopeOutput, Condition: "full == true", Severity: "critical"}))
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Two mailboxes with the same name, both full
+	status := &snmp.PrinterStatus{Host: "10.0.0.5", OutputBins: []snmp.OutputBin{
+		{Index: 2, Name: "Mailbox", Condition: "full"},
+		{Index: 3, Name: "Mailbox", Condition: "full"},
+	}}
+	if fired := engine.Evaluate(status.Host, OutputSubjects(status), start); len(fired) != 2 {
+		t.Fatalf("fired = %+v, want one alert per bin", fired)
+	}
+
+	// Bin 2 is emptied: bin 3's alert keeps firing since the start
+	status.OutputBins[0].Condition = "ok"
+	if fired := engine.Evaluate(status.Host, OutputSubjects(status), start.Add(time.Minute)); len(fired) != 0 {
+		t.Errorf("fired again = %+v", fired)
+	}
+	active := engine.Active(status.Host)
+	if len(active) != 1 || active[0].ID != "3" || !active[0].Since.Equal(start) {
+		t.Errorf("active = %+v, want bin 3 since %s", active, start)
+	}
+
+	// Another host's bins with the same names and indexes are separate alerts
+	other := &snmp.PrinterStatus{Host: "10.0.0.6", OutputBins: []snmp.OutputBin{{Index: 3, Name: "Mailbox", Condition: "full"}}}
+	if fired := engine.Evaluate(other.Host, OutputSubjects(other), start); len(fired) != 1 {
+		t.Errorf("other host fired = %+v", fired)
+	}
+	if active := engine.Active(""); len(active) != 2 || active[0].Host != "10.0.0.5" || active[1].Host != "10.0.0.6" {
+		t.Errorf("all active = %+v", active)
+	}
+}
+
+func TestEvaluateResolves(t *testing.T) {
+	engine := NewEngine(compiled(t, DefaultRules...))
+	now := time.Now()
+	status := &snmp.PrinterStatus{Host: "10.0.0.5", Covers: []snmp.Cover{{Index: 1, Description: "Front Cover", State: "open", Open: true}}}
+
+	fired := engine.Evaluate(status.Host, CoverSubjects(status), now)
+	if len(fired) != 1 || fired[0].Rule != "cover_open" || fired[0].Severity != "critical" || fired[0].Subject != "Front Cover" {
+		t.Fatalf("fired = %+v", fired)
+	}
+
+	status.Covers[0].Open = false
+	engine.Evaluate(status.Host, CoverSubjects(status), now.Add(time.Minute))
+	if active := engine.Active(status.Host); len(active) != 0 {
+		t.Errorf("closed cover still active: %+v", active)
+	}
+
+	// Reopening fires again with a new start
+	status.Covers[0].Open = true
+	fired = engine.Evaluate(status.Host, CoverSubjects(status), now.Add(2*time.Minute))
+	if len(fired) != 1 || !fired[0].Since.Equal(now.Add(2*time.Minute)) {
+		t.Errorf("reopened = %+v", fired)
+	}
+}
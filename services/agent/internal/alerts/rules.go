@@ -0,0 +1,188 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scopes a rule can be evaluated in
+const (
+	ScopePrinter = "printer" // Once per printer
+	ScopeSupply  = "supply"  // Once per marker supply
+	ScopeOutput  = "output"  // Once per output bin
+	ScopeCover   = "cover"   // Once per cover or door
+	ScopeStorage = "storage" // Once per memory or disk area
+	ScopeDevice  = "device"  // Once per hrDeviceTable entry
+)
+
+// Rule raises an alert whenever its condition holds, e.g. "days_remaining < 7"
+type Rule struct {
+	Name      string `json:"name"`
+	Scope     string `json:"scope"`
+	Condition string `json:"condition"`
+	Severity  string `json:"severity"` // warning or critical
+
+	clauses []clause
+}
+
+// clause is a single comparison of a rule's condition
+type clause struct {
+	field string
+	op    string
+	value string
+}
+
+// Subject is something a rule is evaluated against, e.g. one toner cartridge
+type Subject struct {
+	Host  string
+	Scope string
+	ID    string                 // Index or OID that tells apart subjects of the host with the same name, e.g. a supply's "1.1"
+	Name  string                 // Human readable name, e.g. "Black Toner"
+	Vars  map[string]interface{} // Field values, float64 or string
+}
+
+// Alert is a rule whose condition held for a subject
+type Alert struct {
+	Rule     string    `json:"rule"`
+	Severity string    `json:"severity"`
+	Host     string    `json:"host"`
+	Subject  string    `json:"subject"`
+	ID       string    `json:"subject_id,omitempty"` // Subject.ID
+	Message  string    `json:"message"`
+	Since    time.Time `json:"since"`
+}
+
+// DefaultRules are used when no rules file is given
+var DefaultRules = []Rule{
+	{Name: "supply_running_out", Scope: ScopeSupply, Condition: "days_remaining < 7", Severity: "warning"},
+	{Name: "supply_empty", Scope: ScopeSupply, Condition: "percent <= 0", Severity: "critical"},
+	{Name: "output_full", Scope: ScopeOutput, Condition: "full == true", Severity: "critical"},
+	{Name: "cover_open", Scope: ScopeCover, Condition: "open == true", Severity: "critical"},
+	{Name: "storage_full", Scope: ScopeStorage, Condition: "full == true", Severity: "critical"},
+	{Name: "storage_filling", Scope: ScopeStorage, Condition: `condition == "warning"`, Severity: "warning"},
+	{Name: "disk_down", Scope: ScopeDevice, Condition: `type == "disk_storage" and state == "down"`, Severity: "critical"},
+	{Name: "disk_warning", Scope: ScopeDevice, Condition: `type == "disk_storage" and state == "warning"`, Severity: "warning"},
+}
+
+var operators = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// Compile parses the rule's condition. Conditions are comparisons joined by
+// "and", where string values may be quoted: `type == "toner" and percent < 10`.
+func (r *Rule) Compile() error {
+	if r.Scope == "" {
+		r.Scope = ScopePrinter
+	}
+	if r.Severity == "" {
+		r.Severity = "warning"
+	}
+
+	r.clauses = nil
+	for _, part := range strings.Split(r.Condition, " and ") {
+		part = strings.TrimSpace(part)
+		c, err := parseClause(part)
+		if err != nil {
+			return fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		r.clauses = append(r.clauses, c)
+	}
+	return nil
+}
+
+// parseClause parses a comparison such as "days_remaining < 7"
+func parseClause(text string) (clause, error) {
+	for _, op := range operators {
+		if i := strings.Index(text, op); i > 0 {
+			c := clause{
+				field: strings.TrimSpace(text[:i]),
+				op:    op,
+				value: strings.Trim(strings.TrimSpace(text[i+len(op):]), `"'`),
+			}
+			if c.field == "" || c.value == "" {
+				break
+			}
+			return c, nil
+		}
+	}
+	return clause{}, fmt.Errorf("invalid condition %q", text)
+}
+
+// Matches reports whether the rule's condition holds for the subject. Fields
+// the subject doesn't have never match, so unknown values don't raise alerts.
+func (r *Rule) Matches(subject Subject) bool {
+	if r.Scope != subject.Scope || len(r.clauses) == 0 {
+		return false
+	}
+	for _, c := range r.clauses {
+		if !c.matches(subject.Vars[c.field]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches compares a subject's value against the clause
+func (c clause) matches(value interface{}) bool {
+	switch v := value.(type) {
+	case float64:
+		want, err := strconv.ParseFloat(c.value, 64)
+		if err != nil {
+			return false
+		}
+		switch c.op {
+		case "<":
+			return v < want
+		case "<=":
+			return v <= want
+		case ">":
+			return v > want
+		case ">=":
+			return v >= want
+		case "==":
+			return v == want
+		case "!=":
+			return v != want
+		}
+	case string:
+		switch c.op {
+		case "==":
+			return strings.EqualFold(v, c.value)
+		case "!=":
+			return !strings.EqualFold(v, c.value)
+		}
+	case bool:
+		want, err := strconv.ParseBool(c.value)
+		if err != nil {
+			return false
+		}
+		switch c.op {
+		case "==":
+			return v == want
+		case "!=":
+			return v != want
+		}
+	}
+	return false
+}
+
+// LoadRules reads a JSON array of rules from path and compiles them
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode rules %s: %w", path, err)
+	}
+	for i := range rules {
+		if err := rules[i].Compile(); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
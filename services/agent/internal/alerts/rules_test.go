@@ -0,0 +1,104 @@
+package alerts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRuleMatches(t *testing.T) {
+	subject := Subject{Scope: ScopeSupply, Vars: map[string]interface{}{
+		"type":           "toner",
+		"percent":        8.0,
+		"days_remaining": 3.5,
+		"full":           false,
+	}}
+
+	tests := []struct {
+		condition string
+		want      bool
+	}{
+		{"percent < 10", true},
+		{"percent <= 8", true},
+		{"percent > 8", false},
+		{"percent >= 8", true},
+		{"percent == 8", true},
+		{"percent != 8", false},
+		{`type == "toner"`, true},
+		{"type == 'TONER'", true}, // Strings compare case-insensitively
+		{"type != toner", false},
+		{"type < toner", false}, // No ordering of strings
+		{"full == false", true},
+		{"full != true", true},
+		{"full == maybe", false},
+		{"percent < ten", false},
+		{`type == "toner" and percent < 10`, true},
+		{`type == "toner" and days_remaining > 7`, false},
+		{"pages_remaining < 100", false}, // Unknown fields never match
+	}
+	for _, test := range tests {
+		rule := Rule{Name: "test", Scope: ScopeSupply, Condition: test.condition}
+		if err := rule.Compile(); err != nil {
+			t.Errorf("%q: %v", test.condition, err)
+			continue
+		}
+		if got := rule.Matches(subject); got != test.want {
+			t.Errorf("%q matches = %t, want %t", test.condition, got, test.want)
+		}
+	}
+
+	rule := Rule{Name: "test", Scope: ScopeOutput, Condition: "percent < 10"}
+	rule.Compile()
+	if rule.Matches(subject) {
+		t.Error("rule matched a subject of another scope")
+	}
+	if (&Rule{Scope: ScopeSupply}).Matches(subject) {
+		t.Error("uncompiled rule matched")
+	}
+}
+
+func TestRuleCompile(t *testing.T) {
+	rule := Rule{Name: "test", Condition: "status == stopped"}
+	if err := rule.Compile(); err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if rule.Scope != ScopePrinter || rule.Severity != "warning" {
+		t.Errorf("defaults = %q, %q", rule.Scope, rule.Severity)
+	}
+
+	for _, condition := range []string{"", "percent", "< 10", "percent <"} {
+		rule := Rule{Name: "bad", Condition: condition}
+		if err := rule.Compile(); err == nil {
+			t.Errorf("%q compiled", condition)
+		}
+	}
+
+	for i := range DefaultRules {
+		rule := DefaultRules[i]
+		if err := rule.Compile(); err != nil {
+			t.Errorf("default rule %s: %v", rule.Name, err)
+		}
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.json")
+	os.WriteFile(path, []byte(`[{"name": "low", "scope": "supply", "condition": "percent < 5", "severity": "critical"}]`), 0o644)
+
+	rules, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+	if len(rules) != 1 || !rules[0].Matches(Subject{Scope: ScopeSupply, Vars: map[string]interface{}{"percent": 1.0}}) {
+		t.Errorf("rules = %+v", rules)
+	}
+
+	os.WriteFile(path, []byte(`[{"name": "bad", "condition": "percent"}]`), 0o644)
+	if _, err := LoadRules(path); err == nil {
+		t.Error("invalid condition loaded")
+	}
+	if _, err := LoadRules(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("missing file loaded")
+	}
+}
@@ -0,0 +1,172 @@
+package alerts
+
+import (
+	"strconv"
+
+	"lynk/agent/internal/forecast"
+	"lynk/agent/internal/snmp"
+)
+
+// PrinterSubject exposes a printer's overall status to printer scoped rules
+func PrinterSubject(status *snmp.PrinterStatus) Subject {
+	vars := map[string]interface{}{
+		"status":        string(status.Status),
+		"device_state":  string(status.DeviceState),
+		"health":        string(status.Health),
+		"paper_status":  status.PaperStatus,
+		"total_pages":   float64(status.TotalPages),
+		"error_count":   float64(status.ErrorCount),
+		"active_alerts": float64(len(status.ActiveAlerts)),
+	}
+	if status.TonerLevel > 0 {
+		vars["toner_level"] = float64(status.TonerLevel)
+	}
+
+	return Subject{
+		Host:  status.Host,
+		Scope: ScopePrinter,
+		ID:    status.Host,
+		Name:  status.Host,
+		Vars:  vars,
+	}
+}
+
+// SupplySubjects exposes each supply's level and forecast to supply scoped rules
+func SupplySubjects(forecasts []forecast.Forecast) []Subject {
+	subjects := []Subject{}
+	for _, f := range forecasts {
+		vars := map[string]interface{}{
+			"type":        snmp.SupplyTypeName(f.Type),
+			"description": f.Description,
+		}
+		if f.Percent >= 0 {
+			vars["percent"] = float64(f.Percent)
+		}
+		if f.DaysRemaining != nil {
+			vars["days_remaining"] = *f.DaysRemaining
+			vars["percent_per_day"] = f.PercentPerDay
+		}
+		if f.PagesRemaining != nil {
+			vars["pages_remaining"] = float64(*f.PagesRemaining)
+		}
+
+		name := f.Description
+		if name == "" {
+			name = "supply " + f.SupplyIndex
+		}
+		subjects = append(subjects, Subject{
+			Host:  f.Host,
+			Scope: ScopeSupply,
+			ID:    f.SupplyIndex,
+			Name:  name,
+			Vars:  vars,
+		})
+	}
+	return subjects
+}
+
+// OutputSubjects exposes each output bin's status and fill level to output scoped rules
+func OutputSubjects(status *snmp.PrinterStatus) []Subject {
+	subjects := []Subject{}
+	for _, bin := range status.OutputBins {
+		vars := map[string]interface{}{
+			"name":           bin.Name,
+			"condition":      bin.Condition,
+			"availability":   bin.State.Availability,
+			"full":           bin.Condition == "full",
+			"critical_alert": bin.State.CriticalAlert,
+			"offline":        bin.State.OffLine,
+		}
+		if bin.PercentFull >= 0 {
+			vars["percent_full"] = float64(bin.PercentFull)
+		}
+		if bin.Remaining >= 0 {
+			vars["remaining"] = float64(bin.Remaining)
+		}
+		if bin.Capacity > 0 {
+			vars["capacity"] = float64(bin.Capacity)
+		}
+
+		subjects = append(subjects, Subject{
+			Host:  status.Host,
+			Scope: ScopeOutput,
+			ID:    strconv.Itoa(bin.Index),
+			Name:  bin.Name,
+			Vars:  vars,
+		})
+	}
+	return subjects
+}
+
+// CoverSubjects exposes each cover's state to cover scoped rules
+func CoverSubjects(status *snmp.PrinterStatus) []Subject {
+	subjects := []Subject{}
+	for _, cover := range status.Covers {
+		subjects = append(subjects, Subject{
+			Host:  status.Host,
+			Scope: ScopeCover,
+			ID:    strconv.Itoa(cover.Index),
+			Name:  cover.Description,
+			Vars: map[string]interface{}{
+				"description": cover.Description,
+				"state":       cover.State,
+				"open":        cover.Open,
+			},
+		})
+	}
+	return subjects
+}
+
+// StorageSubjects exposes each memory or disk area's fill level to storage scoped rules
+func StorageSubjects(status *snmp.PrinterStatus) []Subject {
+	subjects := []Subject{}
+	for _, storage := range status.Storage {
+		vars := map[string]interface{}{
+			"description":         storage.Description,
+			"type":                storage.Type,
+			"condition":           storage.Condition,
+			"disk":                storage.Disk(),
+			"full":                storage.Condition == "full",
+			"allocation_failures": float64(storage.AllocationFailures),
+		}
+		if storage.Percent >= 0 {
+			vars["percent"] = float64(storage.Percent)
+			vars["size_bytes"] = float64(storage.SizeBytes)
+			vars["used_bytes"] = float64(storage.UsedBytes)
+		}
+
+		subjects = append(subjects, Subject{
+			Host:  status.Host,
+			Scope: ScopeStorage,
+			ID:    strconv.Itoa(storage.Index),
+			Name:  storage.Description,
+			Vars:  vars,
+		})
+	}
+	return subjects
+}
+
+// DeviceSubjects exposes each device's state, error count and load to device scoped rules
+func DeviceSubjects(status *snmp.PrinterStatus) []Subject {
+	subjects := []Subject{}
+	for _, device := range status.Devices {
+		vars := map[string]interface{}{
+			"description": device.Description,
+			"type":        device.Type,
+			"state":       string(device.State),
+			"errors":      float64(device.Errors),
+		}
+		if device.Load != nil {
+			vars["load"] = float64(*device.Load)
+		}
+
+		subjects = append(subjects, Subject{
+			Host:  status.Host,
+			Scope: ScopeDevice,
+			ID:    strconv.Itoa(device.Index),
+			Name:  device.Description,
+			Vars:  vars,
+		})
+	}
+	return subjects
+}
@@ -0,0 +1,222 @@
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+// Kind identifies what changed on a device
+type Kind string
+
+// Kinds of change reported by Diff
+const (
+	IdentityChanged     Kind = "identity_changed"
+	FirmwareChanged     Kind = "firmware_changed"
+	TrayAdded           Kind = "tray_added"
+	TrayRemoved         Kind = "tray_removed"
+	TrayRenamed         Kind = "tray_renamed"
+	SupplyInstalled     Kind = "supply_installed"
+	SupplyRemoved       Kind = "supply_removed"
+	SupplyReplaced      Kind = "supply_replaced"
+	CapabilitiesChanged Kind = "capabilities_changed"
+)
+
+// replacementJump is the rise in percent that marks a fresh cartridge in the same slot
+const replacementJump = 10
+
+// Change is a single difference between two snapshots of a device
+type Change struct {
+	Host  string    `json:"host"`
+	Time  time.Time `json:"time"`
+	Kind  Kind      `json:"kind"`
+	Field string    `json:"field"`
+	Old   string    `json:"old,omitempty"`
+	New   string    `json:"new,omitempty"`
+}
+
+// String describes the change for logs
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s: %q -> %q", c.Kind, c.Field, c.Old, c.New)
+}
+
+// Diff compares two consecutive snapshots of the same host. Fields the
+// current snapshot could not collect are not reported as removed.
+func Diff(previous, current *snmp.PrinterStatus) []Change {
+	if previous == nil {
+		return nil
+	}
+
+	d := &differ{host: current.Host, time: current.LastSeen}
+
+	d.field(IdentityChanged, "model", previous.Model, current.Model)
+	d.field(IdentityChanged, "serial_number", previous.SerialNumber, current.SerialNumber)
+	d.field(IdentityChanged, "device_name", previous.DeviceName, current.DeviceName)
+	d.field(IdentityChanged, "printer_name", previous.PrinterName, current.PrinterName)
+	d.field(IdentityChanged, "system_description", previous.SystemDescription, current.SystemDescription)
+	d.field(IdentityChanged, "location", previous.Location, current.Location)
+	d.field(IdentityChanged, "contact", previous.Contact, current.Contact)
+	d.field(IdentityChanged, "object_id", previous.ObjectID, current.ObjectID)
+	d.field(IdentityChanged, "mac_address", previous.MACAddress, current.MACAddress)
+	d.field(IdentityChanged, "uuid", previous.UUID, current.UUID)
+	d.field(FirmwareChanged, "firmware_version", previous.FirmwareVersion, current.FirmwareVersion)
+	d.field(CapabilitiesChanged, "device_id", previous.DeviceID, current.DeviceID)
+	d.capabilities(previous.Capabilities, current.Capabilities)
+
+	d.trays(previous.PaperTrays, current.PaperTrays)
+	d.supplies(previous.Supplies, current.Supplies)
+	return d.changes
+}
+
+// differ collects the changes of one comparison
+type differ struct {
+	host    string
+	time    time.Time
+	changes []Change
+}
+
+// add records a change
+func (d *differ) add(kind Kind, field, before, after string) {
+	d.changes = append(d.changes, Change{Host: d.host, Time: d.time, Kind: kind, Field: field, Old: before, New: after})
+}
+
+// field records a changed scalar field. An empty value means the field
+// wasn't collected this time, not that it changed.
+func (d *differ) field(kind Kind, name, before, after string) {
+	if after == "" || before == after {
+		return
+	}
+	d.add(kind, name, before, after)
+}
+
+// capabilities records changes to duplex, media size and language support.
+// Tables that weren't collected in either snapshot are skipped.
+func (d *differ) capabilities(previous, current snmp.Capabilities) {
+	if len(previous.MediaPaths) > 0 && len(current.MediaPaths) > 0 {
+		d.field(CapabilitiesChanged, "capabilities.duplex", strconv.FormatBool(previous.Duplex), strconv.FormatBool(current.Duplex))
+		d.field(CapabilitiesChanged, "capabilities.max_media_size", sizeText(previous.MaxMediaSize), sizeText(current.MaxMediaSize))
+	}
+	if len(previous.Languages) > 0 && len(current.Languages) > 0 {
+		d.field(CapabilitiesChanged, "capabilities.languages", strings.Join(previous.Languages, ", "), strings.Join(current.Languages, ", "))
+	}
+}
+
+// sizeText formats an optional media size
+func sizeText(size *snmp.MediaSize) string {
+	if size == nil {
+		return ""
+	}
+	return size.String()
+}
+
+// trays records trays that appeared, disappeared or were renamed, keyed by
+// prtInputIndex. History saved before trays had an index is keyed by name.
+func (d *differ) trays(previous, current []snmp.PaperTray) {
+	if len(current) == 0 {
+		return // Tray table not collected this time
+	}
+
+	byIndex := indexed(previous) && indexed(current)
+	key := func(tray snmp.PaperTray) string {
+		if byIndex {
+			return strconv.Itoa(tray.Index)
+		}
+		return tray.Name
+	}
+	field := func(key string) string {
+		if byIndex {
+			return "paper_trays." + key
+		}
+		return "paper_trays"
+	}
+
+	old := make(map[string]snmp.PaperTray)
+	for _, tray := range previous {
+		old[key(tray)] = tray
+	}
+	now := make(map[string]bool)
+	for _, tray := range current {
+		k := key(tray)
+		now[k] = true
+
+		before, existed := old[k]
+		switch {
+		case !existed:
+			d.add(TrayAdded, field(k), "", tray.Name)
+		case before.Name != tray.Name:
+			d.add(TrayRenamed, field(k), before.Name, tray.Name)
+		}
+	}
+
+	removed := make(map[string]bool)
+	for k := range old {
+		if !now[k] {
+			removed[k] = true
+		}
+	}
+	for _, k := range sortedKeys(removed) {
+		d.add(TrayRemoved, field(k), old[k].Name, "")
+	}
+}
+
+// indexed reports whether every tray has its prtInputIndex
+func indexed(trays []snmp.PaperTray) bool {
+	for _, tray := range trays {
+		if tray.Index == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// supplies records installed, removed and refilled supplies, keyed by supply index
+func (d *differ) supplies(previous, current []snmp.Supply) {
+	if len(current) == 0 {
+		return // Supplies table not collected this time
+	}
+
+	old := make(map[string]snmp.Supply)
+	for _, supply := range previous {
+		old[supply.Index] = supply
+	}
+	now := make(map[string]bool)
+	for _, supply := range current {
+		now[supply.Index] = true
+		field := "supplies." + supply.Index
+
+		before, existed := old[supply.Index]
+		switch {
+		case !existed:
+			d.add(SupplyInstalled, field, "", supply.Description)
+		case before.Description != supply.Description:
+			// A different part in the same slot, e.g. a high-yield cartridge
+			d.add(SupplyInstalled, field, before.Description, supply.Description)
+		case before.Percent >= 0 && supply.Percent-before.Percent >= replacementJump:
+			d.add(SupplyReplaced, field, strconv.Itoa(before.Percent)+"%", strconv.Itoa(supply.Percent)+"%")
+		}
+	}
+
+	removed := make(map[string]bool)
+	for index := range old {
+		if !now[index] {
+			removed[index] = true
+		}
+	}
+	for _, index := range sortedKeys(removed) {
+		d.add(SupplyRemoved, "supplies."+index, old[index].Description, "")
+	}
+}
+
+// sortedKeys returns the keys of a set in order, so changes are reported deterministically
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
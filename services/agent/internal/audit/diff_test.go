@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+func snapshot() *snmp.PrinterStatus {
+	return &snmp.PrinterStatus{
+		Host:            "10.0.0.5",
+		Model:           "LaserJet 4250",
+		SerialNumber:    "E1",
+		Location:        "Lobby",
+		FirmwareVersion: "20240101",
+		Capabilities:    snmp.Capabilities{MediaPaths: []snmp.MediaPath{{}}, Duplex: true, Languages: []string{"PCL"}},
+		PaperTrays:      []snmp.PaperTray{{Index: 1, Name: "Tray 1"}, {Index: 2, Name: "Tray 2"}},
+		Supplies: []snmp.Supply{
+			{Index: "1.1", Description: "Black Toner", Percent: 40},
+			{Index: "1.2", Description: "Drum", Percent: 80},
+		},
+		LastSeen: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestDiff(t *testing.T) {
+	previous, current := snapshot(), snapshot()
+	current.Location = "Print room"
+	current.FirmwareVersion = "20260201"
+	current.Contact = "" // Not collected this time
+	current.Capabilities = snmp.Capabilities{MediaPaths: []snmp.MediaPath{{}}, Duplex: false, Languages: []string{"PCL", "PostScript"}}
+	current.PaperTrays = []snmp.PaperTray{{Index: 1, Name: "Tray 1"}, {Index: 3, Name: "Tray 3"}}
+	current.Supplies = []snmp.Supply{
+		{Index: "1.1", Description: "Black Toner", Percent: 100},
+		{Index: "1.3", Description: "Waste Toner", Percent: 10},
+	}
+
+	want := []Change{
+		{Kind: IdentityChanged, Field: "location", Old: "Lobby", New: "Print room"},
+		{Kind: FirmwareChanged, Field: "firmware_version", Old: "20240101", New: "20260201"},
+		{Kind: CapabilitiesChanged, Field: "capabilities.duplex", Old: "true", New: "false"},
+		{Kind: CapabilitiesChanged, Field: "capabilities.languages", Old: "PCL", New: "PCL, PostScript"},
+		{Kind: TrayAdded, Field: "paper_trays.3", New: "Tray 3"},
+		{Kind: TrayRemoved, Field: "paper_trays.2", Old: "Tray 2"},
+		{Kind: SupplyReplaced, Field: "supplies.1.1", Old: "40%", New: "100%"},
+		{Kind: SupplyInstalled, Field: "supplies.1.3", New: "Waste Toner"},
+		{Kind: SupplyRemoved, Field: "supplies.1.2", Old: "Drum"},
+	}
+	for i := range want {
+		want[i].Host, want[i].Time = current.Host, current.LastSeen
+	}
+	if got := Diff(previous, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestDiffUncollected(t *testing.T) {
+	// A poll that read nothing but the identity reports no removals
+	previous, current := snapshot(), snapshot()
+	current.Location, current.FirmwareVersion = "", ""
+	current.Capabilities = snmp.Capabilities{}
+	current.PaperTrays, current.Supplies = nil, nil
+
+	if got := Diff(previous, current); len(got) != 0 {
+		t.Errorf("Diff = %v", got)
+	}
+	if got := Diff(nil, current); got != nil {
+		t.Errorf("Diff of a first poll = %v", got)
+	}
+}
+
+func TestDiffSupplies(t *testing.T) {
+	tests := []struct {
+		name   string
+		before snmp.Supply
+		after  snmp.Supply
+		want   Kind
+	}{
+		{"used", snmp.Supply{Description: "Toner", Percent: 40}, snmp.Supply{Description: "Toner", Percent: 35}, ""},
+		{"level noise", snmp.Supply{Description: "Toner", Percent: 40}, snmp.Supply{Description: "Toner", Percent: 45}, ""},
+		{"replaced", snmp.Supply{Description: "Toner", Percent: 5}, snmp.Supply{Description: "Toner", Percent: 15}, SupplyReplaced},
+		{"level was unknown", snmp.Supply{Description: "Toner", Percent: -1}, snmp.Supply{Description: "Toner", Percent: 100}, ""},
+		{"different part", snmp.Supply{Description: "Toner", Percent: 40}, snmp.Supply{Description: "Toner XL", Percent: 40}, SupplyInstalled},
+	}
+	for _, test := range tests {
+		previous, current := snapshot(), snapshot()
+		test.before.Index, test.after.Index = "1.1", "1.1"
+		previous.Supplies, current.Supplies = []snmp.Supply{test.before}, []snmp.Supply{test.after}
+
+		changes := Diff(previous, current)
+		var got Kind
+		if len(changes) > 0 {
+			got = changes[0].Kind
+		}
+		if len(changes) > 1 || got != test.want {
+			t.Errorf("%s: changes = %v, want %q", test.name, changes, test.want)
+		}
+	}
+}
+
+func TestDiffTrays(t *testing.T) {
+	previous, current := snapshot(), snapshot()
+
+	// A tray renamed in the printer's settings is the same tray
+	current.PaperTrays = []snmp.PaperTray{{Index: 1, Name: "Tray 1"}, {Index: 2, Name: "Letterhead"}}
+	want := []Change{{Host: current.Host, Time: current.LastSeen, Kind: TrayRenamed, Field: "paper_trays.2", Old: "Tray 2", New: "Letterhead"}}
+	if got := Diff(previous, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff of a renamed tray = %v, want %v", got, want)
+	}
+
+	// Two trays with the same name are both tracked
+	current.PaperTrays = []snmp.PaperTray{{Index: 1, Name: "Tray 1"}, {Index: 2, Name: "Tray 2"}, {Index: 3, Name: "Tray 2"}}
+	want = []Change{{Host: current.Host, Time: current.LastSeen, Kind: TrayAdded, Field: "paper_trays.3", New: "Tray 2"}}
+	if got := Diff(previous, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff of a tray sharing a name = %v, want %v", got, want)
+	}
+
+	// History from before trays had an index is compared by name
+	previous.PaperTrays = []snmp.PaperTray{{Name: "Tray 1"}, {Name: "Tray 2"}}
+	current.PaperTrays = []snmp.PaperTray{{Index: 1, Name: "Tray 1"}, {Index: 2, Name: "Tray 2"}}
+	if got := Diff(previous, current); len(got) != 0 {
+		t.Errorf("Diff against unindexed trays = %v", got)
+	}
+}
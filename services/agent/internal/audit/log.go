@@ -0,0 +1,118 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Log is the audit log of device changes. When backed by a file every change
+// is appended to it as a JSON line, so the log survives restarts.
+type Log struct {
+	mu      sync.RWMutex
+	changes []Change
+	file    *os.File
+}
+
+// NewLog creates an in-memory audit log
+func NewLog() *Log {
+	return &Log{}
+}
+
+// OpenLog opens the audit log file at path, loading the changes already in it
+func OpenLog(path string) (*Log, error) {
+	l := NewLog()
+
+	existing, err := os.Open(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if err == nil {
+		scanner := bufio.NewScanner(existing)
+		for line := 1; scanner.Scan(); line++ {
+			var change Change
+			if err := json.Unmarshal(scanner.Bytes(), &change); err != nil {
+				existing.Close()
+				return nil, fmt.Errorf("audit log %s line %d: %w", path, line, err)
+			}
+			l.changes = append(l.changes, change)
+		}
+		existing.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+	}
+
+	l.file, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return l, nil
+}
+
+// Add records changes, appending them to the log file if there is one
+func (l *Log) Add(changes ...Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.changes = append(l.changes, changes...)
+	if l.file == nil {
+		return nil
+	}
+	for _, change := range changes {
+		line, err := json.Marshal(change)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit change: %w", err)
+		}
+		if _, err := l.file.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write audit log: %w", err)
+		}
+	}
+	return nil
+}
+
+// Query returns the changes matching host and kind made at or after since,
+// oldest first. Empty filters match everything.
+func (l *Log) Query(host string, kind Kind, since time.Time) []Change {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	matched := []Change{}
+	for _, change := range l.changes {
+		if host != "" && change.Host != host {
+			continue
+		}
+		if kind != "" && change.Kind != kind {
+			continue
+		}
+		if change.Time.Before(since) {
+			continue
+		}
+		matched = append(matched, change)
+	}
+	return matched
+}
+
+// Close flushes and closes the log file
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Sync()
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file = nil
+	return err
+}
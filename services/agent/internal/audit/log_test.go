@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLog(t *testing.T) {
+	at := func(minute int) time.Time { return time.Date(2026, 3, 1, 12, minute, 0, 0, time.UTC) }
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	log, err := OpenLog(path)
+	if err != nil {
+		t.Fatalf("OpenLog: %v", err)
+	}
+	if err := log.Add(
+		Change{Host: "a", Time: at(1), Kind: FirmwareChanged, Field: "firmware_version", Old: "1", New: "2"},
+		Change{Host: "b", Time: at(2), Kind: TrayAdded, Field: "paper_trays", New: "Tray 3"},
+	); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The changes are loaded back after a restart and new ones appended
+	log, err = OpenLog(path)
+	if err != nil {
+		t.Fatalf("OpenLog again: %v", err)
+	}
+	defer log.Close()
+	log.Add(Change{Host: "a", Time: at(3), Kind: TrayRemoved, Field: "paper_trays", Old: "Tray 2"})
+
+	if got := log.Query("", "", time.Time{}); len(got) != 3 || got[0].New != "2" || !got[0].Time.Equal(at(1)) {
+		t.Errorf("all changes = %v", got)
+	}
+	if got := log.Query("a", "", at(2)); len(got) != 1 || got[0].Kind != TrayRemoved {
+		t.Errorf("changes of a since 12:02 = %v", got)
+	}
+	if got := log.Query("", TrayAdded, time.Time{}); len(got) != 1 || got[0].Host != "b" {
+		t.Errorf("added trays = %v", got)
+	}
+
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 3 {
+		t.Errorf("log file has %d lines, want 3", lines)
+	}
+}
+
+func TestOpenLogCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	os.WriteFile(path, []byte(`{"host":"a","kind":"tray_added"}`+"\nnot json\n"), 0o644)
+
+	if _, err := OpenLog(path); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("error = %v, want the corrupt line", err)
+	}
+}
+
+func TestMemoryLog(t *testing.T) {
+	log := NewLog()
+	if err := log.Add(Change{Host: "a", Kind: SupplyReplaced}); err != nil {
+		t.Errorf("Add: %v", err)
+	}
+	if got := log.Query("a", SupplyReplaced, time.Time{}); len(got) != 1 {
+		t.Errorf("changes = %v", got)
+	}
+	if err := log.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
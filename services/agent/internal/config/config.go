@@ -0,0 +1,100 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config is the agent's configuration file
+type Config struct {
+	Targets []Target `json:"targets"`
+}
+
+// Target is a printer to poll together with the metadata used for reporting
+type Target struct {
+	Host       string   `json:"host"`
+	Name       string   `json:"name,omitempty"`       // Friendly name shown in reports
+	Site       string   `json:"site,omitempty"`       // Building or office
+	Department string   `json:"department,omitempty"` // Cost center pages are charged to
+	Tags       []string `json:"tags,omitempty"`
+	Protocol   string   `json:"protocol,omitempty"` // snmp (default), ipp or pjl
+	Fallback   string   `json:"fallback,omitempty"` // Protocol to try when a poll over Protocol fails
+	Merge      []string `json:"merge,omitempty"`    // Protocols also polled, their fields merged with Protocol's
+}
+
+// Protocols a target can be polled with
+const (
+	ProtocolSNMP = "snmp"
+	ProtocolIPP  = "ipp"
+	ProtocolPJL  = "pjl"
+)
+
+// Load reads a JSON configuration file
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg := &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode config %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for i, target := range cfg.Targets {
+		if target.Host == "" {
+			return nil, fmt.Errorf("config %s: target %d has no host", path, i+1)
+		}
+		if seen[target.Host] {
+			return nil, fmt.Errorf("config %s: target %s is listed twice", path, target.Host)
+		}
+		valid := validProtocol(target.Protocol) && validProtocol(target.Fallback)
+		for _, protocol := range target.Merge {
+			valid = valid && protocol != "" && validProtocol(protocol)
+		}
+		if !valid {
+			return nil, fmt.Errorf("config %s: target %s has an unknown protocol, expected snmp, ipp or pjl", path, target.Host)
+		}
+		seen[target.Host] = true
+	}
+	return cfg, nil
+}
+
+// validProtocol reports whether protocol is empty or a known protocol
+func validProtocol(protocol string) bool {
+	switch protocol {
+	case "", ProtocolSNMP, ProtocolIPP, ProtocolPJL:
+		return true
+	}
+	return false
+}
+
+// FromHosts builds a configuration for bare hosts without any metadata
+func FromHosts(hosts []string) *Config {
+	cfg := &Config{}
+	for _, host := range hosts {
+		cfg.Targets = append(cfg.Targets, Target{Host: host})
+	}
+	return cfg
+}
+
+// Hosts returns the host of every target
+func (c *Config) Hosts() []string {
+	hosts := make([]string, 0, len(c.Targets))
+	for _, target := range c.Targets {
+		hosts = append(hosts, target.Host)
+	}
+	return hosts
+}
+
+// Target returns the target for host. Unknown hosts get an empty target.
+func (c *Config) Target(host string) Target {
+	for _, target := range c.Targets {
+		if target.Host == host {
+			return target
+		}
+	}
+	return Target{Host: host}
+}
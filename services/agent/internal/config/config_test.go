@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func write(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := Load(write(t, `{"targets": [
+		{"host": "10.0.0.5", "name": "Reception", "site": "HQ", "department": "Finance", "tags": ["color"]},
+		{"host": "10.0.0.6", "protocol": "ipp", "fallback": "pjl", "merge": ["snmp"]}
+	]}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.Hosts(); !reflect.DeepEqual(got, []string{"10.0.0.5", "10.0.0.6"}) {
+		t.Errorf("hosts = %q", got)
+	}
+	if target := cfg.Target("10.0.0.5"); target.Name != "Reception" || target.Department != "Finance" || len(target.Tags) != 1 {
+		t.Errorf("target = %+v", target)
+	}
+	if target := cfg.Target("10.0.0.6"); target.Protocol != ProtocolIPP || target.Fallback != ProtocolPJL || target.Merge[0] != ProtocolSNMP {
+		t.Errorf("target = %+v", target)
+	}
+	if target := cfg.Target("10.0.0.7"); !reflect.DeepEqual(target, Target{Host: "10.0.0.7"}) {
+		t.Errorf("unknown target = %+v", target)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"invalid JSON", `{"targets": [`, "failed to decode"},
+		{"no host", `{"targets": [{"name": "Reception"}]}`, "target 1 has no host"},
+		{"duplicate", `{"targets": [{"host": "a"}, {"host": "a"}]}`, "listed twice"},
+		{"unknown protocol", `{"targets": [{"host": "a", "protocol": "lpd"}]}`, "unknown protocol"},
+		{"unknown fallback", `{"targets": [{"host": "a", "fallback": "http"}]}`, "unknown protocol"},
+		{"empty merge", `{"targets": [{"host": "a", "merge": [""]}]}`, "unknown protocol"},
+	}
+	for _, test := range tests {
+		_, err := Load(write(t, test.data))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error = %v, want %q", test.name, err, test.want)
+		}
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Load of a missing file succeeded")
+	}
+}
+
+func TestFromHosts(t *testing.T) {
+	cfg := FromHosts([]string{"a", "b"})
+	if len(cfg.Targets) != 2 || cfg.Targets[1].Host != "b" || cfg.Targets[1].Protocol != "" {
+		t.Errorf("targets = %+v", cfg.Targets)
+	}
+}
@@ -0,0 +1,217 @@
+// Package deviceid parses IEEE 1284 device IDs, the "MFG:Brother;MDL:HL-L2350DW;"
+// strings printers report over SNMP, IPP and PJL.
+package deviceid
+
+import (
+	"sort"
+	"strings"
+)
+
+// DeviceID is a parsed IEEE 1284 device ID
+type DeviceID struct {
+	Manufacturer string            `json:"manufacturer,omitempty"` // MFG or MANUFACTURER
+	Model        string            `json:"model,omitempty"`        // MDL or MODEL
+	CommandSet   []string          `json:"command_set,omitempty"`  // CMD or COMMAND SET, e.g. PCL, PJL, POSTSCRIPT
+	Class        string            `json:"class,omitempty"`        // CLS or CLASS, e.g. PRINTER
+	Description  string            `json:"description,omitempty"`  // DES or DESCRIPTION
+	SerialNumber string            `json:"serial_number,omitempty"`
+	Fields       map[string]string `json:"fields"` // Every key with its value, keys upper case as reported
+}
+
+// aliases maps the long and vendor spellings of keys to the short ones
+var aliases = map[string]string{
+	"MANUFACTURER": "MFG",
+	"MODEL":        "MDL",
+	"COMMAND SET":  "CMD",
+	"COMMANDSET":   "CMD",
+	"CLASS":        "CLS",
+	"DESCRIPTION":  "DES",
+	"SERN":         "SN",
+	"SERIALNUMBER": "SN",
+	"SERIAL":       "SN",
+}
+
+// Parse parses a device ID. Keys are case insensitive and may be spelled out
+// (MANUFACTURER for MFG). A backslash escapes the next character, so values
+// may contain ";" and ":". Malformed fields are skipped rather than failing
+// the whole ID.
+func Parse(raw string) DeviceID {
+	id := DeviceID{Fields: make(map[string]string)}
+	for _, field := range split(stripLength(raw)) {
+		key, value, ok := cut(field)
+		if !ok {
+			continue
+		}
+		key = strings.ToUpper(strings.Join(strings.Fields(key), " "))
+		value = strings.TrimSpace(value)
+		if key == "" {
+			continue
+		}
+		if short, ok := aliases[key]; ok {
+			key = short
+		}
+		if _, ok := id.Fields[key]; ok {
+			continue // The first occurrence wins
+		}
+		id.Fields[key] = value
+
+		switch key {
+		case "MFG":
+			id.Manufacturer = value
+		case "MDL":
+			id.Model = value
+		case "CMD":
+			for _, command := range strings.Split(value, ",") {
+				if command = strings.TrimSpace(command); command != "" {
+					id.CommandSet = append(id.CommandSet, command)
+				}
+			}
+		case "CLS":
+			id.Class = value
+		case "DES":
+			id.Description = value
+		case "SN":
+			id.SerialNumber = value
+		}
+	}
+	return id
+}
+
+// Empty reports whether the ID had no fields at all
+func (id DeviceID) Empty() bool {
+	return len(id.Fields) == 0
+}
+
+// leading are the keys String writes first, in the order printers report them
+var leading = []string{"MFG", "MDL", "CMD", "CLS", "DES"}
+
+// String formats the ID's fields as a device ID that parses back to the same
+// fields, e.g. "MFG:Brother;MDL:HL-L2350DW;". The usual keys come first, the
+// others in alphabetical order.
+func (id DeviceID) String() string {
+	keys := make([]string, 0, len(id.Fields))
+	for key := range id.Fields {
+		keys = append(keys, key)
+	}
+	rank := func(key string) int {
+		for i, k := range leading {
+			if k == key {
+				return i
+			}
+		}
+		return len(leading)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if rank(keys[i]) != rank(keys[j]) {
+			return rank(keys[i]) < rank(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	var out strings.Builder
+	for _, key := range keys {
+		out.WriteString(escape(key))
+		out.WriteByte(':')
+		out.WriteString(escape(id.Fields[key]))
+		out.WriteByte(';')
+	}
+	return out.String()
+}
+
+// escape puts a backslash before the separators, backslashes and control
+// characters of a key or value
+func escape(s string) string {
+	var out strings.Builder
+	for _, r := range s {
+		if r == '\\' || r == ';' || r == ':' || r < 0x20 || r == 0x7f {
+			out.WriteByte('\\')
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+// stripLength drops the two byte big-endian length that prefixes device IDs
+// read straight from the parallel port or USB, and any other leading control
+// characters
+func stripLength(raw string) string {
+	if len(raw) >= 2 {
+		// The length counts either the whole ID or just the text after it. IDs
+		// shorter than 256 bytes start with a NUL, which text never does.
+		length := int(raw[0])<<8 | int(raw[1])
+		if raw[0] == 0 || length == len(raw) || length == len(raw)-2 {
+			raw = raw[2:]
+		}
+	}
+	return strings.TrimLeftFunc(raw, func(r rune) bool {
+		return r < 0x20 || r == 0x7f
+	})
+}
+
+// split splits a device ID into its fields at unescaped semicolons
+func split(raw string) []string {
+	var fields []string
+	var field strings.Builder
+	escaped := false
+	for _, r := range raw {
+		switch {
+		case escaped:
+			field.WriteRune('\\')
+			field.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ';':
+			fields = append(fields, field.String())
+			field.Reset()
+		case r == 0:
+			// NUL padding after the last field
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if escaped {
+		field.WriteRune('\\')
+	}
+	if strings.TrimSpace(field.String()) != "" {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// cut splits a field at its first unescaped colon and unescapes both halves
+func cut(field string) (key, value string, ok bool) {
+	escaped := false
+	for i, r := range field {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ':':
+			return unescape(field[:i]), unescape(field[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// unescape removes the backslashes that escape the following character
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var out strings.Builder
+	escaped := false
+	for _, r := range s {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		out.WriteRune(r)
+		escaped = false
+	}
+	if escaped {
+		out.WriteRune('\\') // A trailing lone backslash is kept as is
+	}
+	return out.String()
+}
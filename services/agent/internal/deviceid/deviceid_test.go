@@ -0,0 +1,141 @@
+package deviceid
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want DeviceID
+	}{
+		{
+			name: "short keys",
+			raw:  "MFG:Brother;CMD:PJL,PCL,PCLXL,URF;MDL:HL-L2350DW series;CLS:PRINTER;CID:Brother Laser Type1;",
+			want: DeviceID{
+				Manufacturer: "Brother",
+				Model:        "HL-L2350DW series",
+				CommandSet:   []string{"PJL", "PCL", "PCLXL", "URF"},
+				Class:        "PRINTER",
+				Fields: map[string]string{
+					"MFG": "Brother", "CMD": "PJL,PCL,PCLXL,URF", "MDL": "HL-L2350DW series",
+					"CLS": "PRINTER", "CID": "Brother Laser Type1",
+				},
+			},
+		},
+		{
+			name: "long keys",
+			raw:  "MANUFACTURER:Hewlett-Packard;COMMAND SET:PJL,PML,POSTSCRIPT;MODEL:HP LaserJet 4250;CLASS:PRINTER;DESCRIPTION:HP LaserJet 4250;SERN:CNRXB12345",
+			want: DeviceID{
+				Manufacturer: "Hewlett-Packard",
+				Model:        "HP LaserJet 4250",
+				CommandSet:   []string{"PJL", "PML", "POSTSCRIPT"},
+				Class:        "PRINTER",
+				Description:  "HP LaserJet 4250",
+				SerialNumber: "CNRXB12345",
+				Fields: map[string]string{
+					"MFG": "Hewlett-Packard", "CMD": "PJL,PML,POSTSCRIPT", "MDL": "HP LaserJet 4250",
+					"CLS": "PRINTER", "DES": "HP LaserJet 4250", "SN": "CNRXB12345",
+				},
+			},
+		},
+		{
+			name: "case and whitespace",
+			raw:  "  mfg : Kyocera ;  command   set: PCL , KPDL ,;Model:ECOSYS P2040dn  ;",
+			want: DeviceID{
+				Manufacturer: "Kyocera",
+				Model:        "ECOSYS P2040dn",
+				CommandSet:   []string{"PCL", "KPDL"},
+				Fields:       map[string]string{"MFG": "Kyocera", "CMD": "PCL , KPDL ,", "MDL": "ECOSYS P2040dn"},
+			},
+		},
+		{
+			name: "escaped separators",
+			raw:  `MFG:ACME\; Inc.;MDL:Model\:1;DES:back\\slash;`,
+			want: DeviceID{
+				Manufacturer: "ACME; Inc.",
+				Model:        "Model:1",
+				Description:  `back\slash`,
+				Fields:       map[string]string{"MFG": "ACME; Inc.", "MDL": "Model:1", "DES": `back\slash`},
+			},
+		},
+		{
+			name: "first occurrence wins",
+			raw:  "MFG:First;MANUFACTURER:Second;",
+			want: DeviceID{Manufacturer: "First", Fields: map[string]string{"MFG": "First"}},
+		},
+		{
+			name: "malformed fields skipped",
+			raw:  "garbage;:novalue;MDL:X;;",
+			want: DeviceID{Model: "X", Fields: map[string]string{"MDL": "X"}},
+		},
+		{
+			name: "length prefix counting the whole ID",
+			raw:  "\x00\x0dMFG:A;MDL:B;",
+			want: DeviceID{Manufacturer: "A", Model: "B", Fields: map[string]string{"MFG": "A", "MDL": "B"}},
+		},
+		{
+			name: "length prefix counting the text",
+			raw:  "\x00\x0bMFG:A;MDL:B;",
+			want: DeviceID{Manufacturer: "A", Model: "B", Fields: map[string]string{"MFG": "A", "MDL": "B"}},
+		},
+		{
+			name: "length prefix above 255",
+			raw:  "\x01\x07MFG:" + strings.Repeat("x", 258) + ";",
+			want: DeviceID{Manufacturer: strings.Repeat("x", 258), Fields: map[string]string{"MFG": strings.Repeat("x", 258)}},
+		},
+		{
+			name: "NUL padding",
+			raw:  "MFG:A;MDL:B;\x00\x00\x00",
+			want: DeviceID{Manufacturer: "A", Model: "B", Fields: map[string]string{"MFG": "A", "MDL": "B"}},
+		},
+		{
+			name: "empty",
+			raw:  "",
+			want: DeviceID{Fields: map[string]string{}},
+		},
+	}
+	for _, test := range tests {
+		got := Parse(test.raw)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Parse(%q) = %+v, want %+v", test.name, test.raw, got, test.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	id := Parse(`SN:123;X-VENDOR:a\:b;CMD:PCL;MDL:M;MFG:F;`)
+	want := `MFG:F;MDL:M;CMD:PCL;SN:123;X-VENDOR:a\:b;`
+	if got := id.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"MFG:Brother;MDL:HL-L2350DW series;CMD:PJL,PCL;",
+		"MANUFACTURER:HP;COMMAND SET:PJL;MODEL:LaserJet;",
+		`MFG:A\;B;MDL:C\:D;DES:E\\`,
+		"\x00\x10MFG:A;MDL:B;",
+		"\x01\x00;;::\\",
+		"k:v;\x01K:v\x00;",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, raw string) {
+		id := Parse(raw)
+
+		// Formatting and parsing again must give the same ID, and the same text
+		text := id.String()
+		again := Parse(text)
+		if !reflect.DeepEqual(id, again) {
+			t.Fatalf("Parse(%q) = %+v, but its String %q parses as %+v", raw, id, text, again)
+		}
+		if again.String() != text {
+			t.Fatalf("String is not stable: %q, then %q", text, again.String())
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+// Type identifies what happened to a device between two polls
+type Type string
+
+const (
+	// Reboot means sysUpTime went backwards
+	Reboot Type = "reboot"
+	// CounterReset means the page counter went back towards zero, e.g. after a board replacement
+	CounterReset Type = "counter_reset"
+	// CounterWrap means the Counter32 page counter rolled over
+	CounterWrap Type = "counter_wrap"
+	// DeviceSwapped means a different device now answers at the same address
+	DeviceSwapped Type = "device_swapped"
+)
+
+// counterMax is the range of Counter32 and TimeTicks values
+const counterMax int64 = 1 << 32
+
+// wrapMargin is how close to counterMax a value must be for a drop to be read as a wrap
+const wrapMargin int64 = 1 << 24
+
+// Event is something that happened to a device between two polls
+type Event struct {
+	Type   Type      `json:"type"`
+	Host   string    `json:"host"`
+	Time   time.Time `json:"time"`
+	Old    string    `json:"old,omitempty"`
+	New    string    `json:"new,omitempty"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// Detect compares two successive polls of the same host and returns the
+// counter and identity events between them. Counter events need both polls
+// to have read the page counter.
+func Detect(previous, current *snmp.PrinterStatus) []Event {
+	if previous == nil {
+		return nil
+	}
+
+	var events []Event
+	if swapped(previous, current) {
+		// Counters of a different device can't be compared with the old ones
+		field, before, after := "serial number", previous.SerialNumber, current.SerialNumber
+		if before == "" || after == "" {
+			field, before, after = "MAC address", previous.MACAddress, current.MACAddress
+		}
+		return append(events, Event{
+			Type:   DeviceSwapped,
+			Host:   current.Host,
+			Time:   current.LastSeen,
+			Old:    before,
+			New:    after,
+			Detail: fmt.Sprintf("%s changed from %s to %s", field, before, after),
+		})
+	}
+
+	if current.Uptime > 0 && current.Uptime < previous.Uptime && !wrapped(int64(previous.Uptime)) {
+		events = append(events, Event{
+			Type:   Reboot,
+			Host:   current.Host,
+			Time:   current.LastSeen,
+			Old:    strconv.FormatUint(uint64(previous.Uptime), 10),
+			New:    strconv.FormatUint(uint64(current.Uptime), 10),
+			Detail: fmt.Sprintf("up for %s", time.Duration(current.Uptime)*10*time.Millisecond),
+		})
+	}
+
+	if _, event := PageDelta(previous, current); event != nil {
+		events = append(events, *event)
+	}
+	return events
+}
+
+// PageDelta returns the pages printed between two successive polls of the
+// same host, together with the counter event that happened in between, if
+// any. It is 0 when either poll didn't read the page counter; compare the
+// next poll that does with the last one that did instead.
+func PageDelta(previous, current *snmp.PrinterStatus) (int, *Event) {
+	if !PagesReported(previous) || !PagesReported(current) {
+		// A counter that wasn't read is not a reset
+		return 0, nil
+	}
+	if swapped(previous, current) {
+		// The new device's lifetime count says nothing about what was printed here
+		return 0, nil
+	}
+	if current.TotalPages >= previous.TotalPages {
+		return current.TotalPages - previous.TotalPages, nil
+	}
+
+	event := &Event{
+		Host: current.Host,
+		Time: current.LastSeen,
+		Old:  strconv.Itoa(previous.TotalPages),
+		New:  strconv.Itoa(current.TotalPages),
+	}
+	if wrapped(int64(previous.TotalPages)) {
+		event.Type = CounterWrap
+		return int(int64(current.TotalPages) + counterMax - int64(previous.TotalPages)), event
+	}
+
+	// The counter started again from zero
+	event.Type = CounterReset
+	return current.TotalPages, event
+}
+
+// MonotonicPages derives a page count that never goes backwards across
+// resets, wraps and device swaps. previous is the latest poll of the same
+// host that read the page counter; a poll that didn't read it carries the
+// previous count forward.
+func MonotonicPages(previous, current *snmp.PrinterStatus) int64 {
+	if previous == nil {
+		return int64(current.TotalPages)
+	}
+
+	base := previous.MonotonicPages
+	if base == 0 {
+		// History recorded before monotonic counters were derived
+		base = int64(previous.TotalPages)
+	}
+	delta, _ := PageDelta(previous, current)
+	return base + int64(delta)
+}
+
+// PagesReported reports whether a poll read the page counter. A zero count
+// only counts when a source reported it as zero.
+func PagesReported(status *snmp.PrinterStatus) bool {
+	return status.TotalPages != 0 || status.ZeroReported("total_pages")
+}
+
+// swapped reports whether two polls came from devices with different serial
+// numbers, or different MAC addresses when the serial number is unknown
+func swapped(previous, current *snmp.PrinterStatus) bool {
+	if previous.SerialNumber != "" && current.SerialNumber != "" {
+		return previous.SerialNumber != current.SerialNumber
+	}
+	return previous.MACAddress != "" && current.MACAddress != "" && previous.MACAddress != current.MACAddress
+}
+
+// wrapped reports whether a 32 bit value was close enough to its maximum that a drop is a wrap
+func wrapped(value int64) bool {
+	return value >= counterMax-wrapMargin
+}
@@ -0,0 +1,163 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+func poll(serial string, uptime uint32, pages int) *snmp.PrinterStatus {
+	return &snmp.PrinterStatus{
+		Host:         "10.0.0.5",
+		SerialNumber: serial,
+		Uptime:       uptime,
+		TotalPages:   pages,
+		LastSeen:     time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestDetect(t *testing.T) {
+	nearMax := uint32(counterMax - 1000)
+	tests := []struct {
+		name     string
+		previous *snmp.PrinterStatus
+		current  *snmp.PrinterStatus
+		want     []Type
+	}{
+		{"first poll", nil, poll("E1", 100, 10), nil},
+		{"printing", poll("E1", 100, 10), poll("E1", 200, 20), nil},
+		{"reboot", poll("E1", 5000, 10), poll("E1", 100, 10), []Type{Reboot}},
+		{"uptime wrapped", poll("E1", nearMax, 10), poll("E1", 100, 10), nil},
+		{"uptime unknown", poll("E1", 5000, 10), poll("E1", 0, 10), nil},
+		{"counter reset", poll("E1", 100, 5000), poll("E1", 200, 3), []Type{CounterReset}},
+		{"counter wrapped", poll("E1", 100, int(nearMax)), poll("E1", 200, 500), []Type{CounterWrap}},
+		{"reboot and reset", poll("E1", 5000, 5000), poll("E1", 100, 3), []Type{Reboot, CounterReset}},
+		{"swapped", poll("E1", 5000, 5000), poll("E2", 100, 3), []Type{DeviceSwapped}},
+		{"serial learned", poll("", 100, 10), poll("E1", 200, 20), nil},
+	}
+	for _, test := range tests {
+		events := Detect(test.previous, test.current)
+		var got []Type
+		for _, event := range events {
+			got = append(got, event.Type)
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%s: events = %v, want %v", test.name, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: events = %v, want %v", test.name, got, test.want)
+			}
+		}
+	}
+}
+
+func TestDetectSwapByMAC(t *testing.T) {
+	previous, current := poll("", 100, 10), poll("", 200, 20)
+	previous.MACAddress, current.MACAddress = "00:11:22:33:44:55", "00:11:22:33:44:66"
+
+	events := Detect(previous, current)
+	if len(events) != 1 || events[0].Type != DeviceSwapped || events[0].Old != "00:11:22:33:44:55" ||
+		events[0].Detail != "MAC address changed from 00:11:22:33:44:55 to 00:11:22:33:44:66" {
+		t.Errorf("events = %+v", events)
+	}
+}
+
+func TestPageDelta(t *testing.T) {
+	tests := []struct {
+		name      string
+		previous  int
+		current   int
+		serial    string
+		want      int
+		wantEvent Type
+	}{
+		{"unchanged", 1000, 1000, "E1", 0, ""},
+		{"printed", 1000, 1250, "E1", 250, ""},
+		{"reset", 1000, 40, "E1", 40, CounterReset},
+		{"wrapped", int(counterMax - 100), 150, "E1", 250, CounterWrap},
+		{"below the wrap margin", int(counterMax - wrapMargin - 1), 150, "E1", 150, CounterReset},
+		{"swapped", 1000, 50000, "E2", 0, ""},
+	}
+	for _, test := range tests {
+		delta, event := PageDelta(poll("E1", 0, test.previous), poll(test.serial, 0, test.current))
+		if delta != test.want {
+			t.Errorf("%s: delta = %d, want %d", test.name, delta, test.want)
+		}
+		switch {
+		case event == nil && test.wantEvent != "":
+			t.Errorf("%s: no event, want %s", test.name, test.wantEvent)
+		case event != nil && event.Type != test.wantEvent:
+			t.Errorf("%s: event = %s, want %q", test.name, event.Type, test.wantEvent)
+		}
+	}
+}
+
+func TestMonotonicPages(t *testing.T) {
+	first := poll("E1", 100, 1000)
+	if got := MonotonicPages(nil, first); got != 1000 {
+		t.Fatalf("first poll = %d", got)
+	}
+	first.MonotonicPages = 1000
+
+	// A reset and a swap keep counting up from the last total
+	reset := poll("E1", 200, 30)
+	if reset.MonotonicPages = MonotonicPages(first, reset); reset.MonotonicPages != 1030 {
+		t.Errorf("after a reset = %d, want 1030", reset.MonotonicPages)
+	}
+	swapped := poll("E2", 300, 90000)
+	if swapped.MonotonicPages = MonotonicPages(reset, swapped); swapped.MonotonicPages != 1030 {
+		t.Errorf("after a swap = %d, want 1030", swapped.MonotonicPages)
+	}
+	after := poll("E2", 400, 90100)
+	if after.MonotonicPages = MonotonicPages(swapped, after); after.MonotonicPages != 1130 {
+		t.Errorf("after printing on the new device = %d, want 1130", after.MonotonicPages)
+	}
+
+	// A poll that didn't read the counter carries the count forward
+	missing := poll("E2", 500, 0)
+	if got := MonotonicPages(after, missing); got != 1130 {
+		t.Errorf("without a page count = %d, want 1130", got)
+	}
+
+	// History from before monotonic counts were kept starts from the total
+	if got := MonotonicPages(poll("E1", 100, 500), poll("E1", 200, 520)); got != 520 {
+		t.Errorf("without a previous monotonic count = %d, want 520", got)
+	}
+}
+
+func TestPagesNotRead(t *testing.T) {
+	// good -> missing -> good: the poll without a page count is skipped and
+	// the next good poll is compared with the last good one
+	good := poll("E1", 100, 1000)
+	good.MonotonicPages = 1000
+	missing := poll("E1", 200, 0)
+	next := poll("E1", 300, 1040)
+
+	if PagesReported(missing) {
+		t.Error("a zero count that wasn't reported counts as read")
+	}
+	if events := Detect(good, missing); len(events) != 0 {
+		t.Errorf("events after a missing count = %+v", events)
+	}
+	if delta, event := PageDelta(good, missing); delta != 0 || event != nil {
+		t.Errorf("delta to a missing count = %d, %+v", delta, event)
+	}
+	if delta, event := PageDelta(missing, next); delta != 0 || event != nil {
+		t.Errorf("delta from a missing count = %d, %+v", delta, event)
+	}
+	if delta, event := PageDelta(good, next); delta != 40 || event != nil {
+		t.Errorf("delta across a missing count = %d, %+v", delta, event)
+	}
+	if got := MonotonicPages(good, next); got != 1040 {
+		t.Errorf("monotonic count across a missing count = %d, want 1040", got)
+	}
+
+	// A counter reported as zero is read, and is a reset
+	missing.ReportedZero = []string{"total_pages"}
+	if _, event := PageDelta(good, missing); event == nil || event.Type != CounterReset {
+		t.Errorf("event for a reported zero = %+v", event)
+	}
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultMaxEvents bounds the in-memory event log
+const DefaultMaxEvents = 10000
+
+// Log keeps the most recent events of every host
+type Log struct {
+	maxEvents int
+	mu        sync.RWMutex
+	events    []Event
+}
+
+// NewLog creates an event log that keeps at most maxEvents events
+func NewLog(maxEvents int) *Log {
+	if maxEvents <= 0 {
+		maxEvents = DefaultMaxEvents
+	}
+	return &Log{maxEvents: maxEvents}
+}
+
+// Add appends events to the log, dropping the oldest once it is full
+func (l *Log) Add(events ...Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = append(l.events, events...)
+	if len(l.events) > l.maxEvents {
+		l.events = append([]Event(nil), l.events[len(l.events)-l.maxEvents:]...)
+	}
+}
+
+// Query returns the events matching host and type that happened at or after
+// since, oldest first. Empty filters match everything.
+func (l *Log) Query(host string, eventType Type, since time.Time) []Event {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	matched := []Event{}
+	for _, event := range l.events {
+		if host != "" && event.Host != host {
+			continue
+		}
+		if eventType != "" && event.Type != eventType {
+			continue
+		}
+		if event.Time.Before(since) {
+			continue
+		}
+		matched = append(matched, event)
+	}
+	return matched
+}
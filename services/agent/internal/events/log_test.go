@@ -0,0 +1,39 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLog(t *testing.T) {
+	at := func(minute int) time.Time { return time.Date(2026, 3, 1, 12, minute, 0, 0, time.UTC) }
+
+	log := NewLog(3)
+	log.Add()
+	log.Add(
+		Event{Type: Reboot, Host: "a", Time: at(1)},
+		Event{Type: CounterReset, Host: "a", Time: at(2)},
+	)
+	log.Add(
+		Event{Type: Reboot, Host: "b", Time: at(3)},
+		Event{Type: Reboot, Host: "a", Time: at(4)},
+	)
+
+	// The oldest event was dropped
+	if got := log.Query("", "", time.Time{}); len(got) != 3 || got[0].Time != at(2) {
+		t.Errorf("all events = %+v", got)
+	}
+	if got := log.Query("a", Reboot, time.Time{}); len(got) != 1 || got[0].Time != at(4) {
+		t.Errorf("reboots of a = %+v", got)
+	}
+	if got := log.Query("", "", at(3)); len(got) != 2 {
+		t.Errorf("events since 12:03 = %+v", got)
+	}
+	if got := log.Query("c", "", time.Time{}); got == nil || len(got) != 0 {
+		t.Errorf("events of an unknown host = %#v", got)
+	}
+
+	if NewLog(0).maxEvents != DefaultMaxEvents {
+		t.Error("a log without a limit isn't bounded")
+	}
+}
@@ -0,0 +1,145 @@
+package forecast
+
+import (
+	"sort"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+// DefaultWindow is how much recent history the consumption rate is fitted on
+const DefaultWindow = 30 * 24 * time.Hour
+
+// replacementJump is the rise in percent that marks a new cartridge. Smaller
+// rises are treated as sensor noise.
+const replacementJump = 10
+
+// Forecast is the predicted depletion of a single supply
+type Forecast struct {
+	Host           string     `json:"host"`
+	SupplyIndex    string     `json:"supply_index"`
+	Description    string     `json:"description"`
+	Type           int        `json:"type"`                      // prtMarkerSuppliesType
+	Percent        int        `json:"percent"`                   // Latest level
+	PercentPerDay  float64    `json:"percent_per_day"`           // Fitted consumption, positive while the supply is used
+	PercentPerPage float64    `json:"percent_per_page"`          // Fitted consumption per printed page
+	DaysRemaining  *float64   `json:"days_remaining,omitempty"`  // Unknown until consumption is seen
+	PagesRemaining *int       `json:"pages_remaining,omitempty"` // Unknown until consumption is seen
+	DepletionDate  *time.Time `json:"depletion_date,omitempty"`
+	Samples        int        `json:"samples"`               // Samples the fit was made on
+	ReplacedAt     *time.Time `json:"replaced_at,omitempty"` // Last detected cartridge replacement within the window
+}
+
+// sample is a single supply level observation
+type sample struct {
+	at      time.Time
+	pages   int
+	percent int
+}
+
+// Supplies forecasts every supply of a printer from its poll history, oldest
+// snapshot first. Only snapshots within window of the latest one are used.
+func Supplies(history []*snmp.PrinterStatus, window time.Duration) []Forecast {
+	if len(history) == 0 {
+		return []Forecast{}
+	}
+	latest := history[len(history)-1]
+	since := latest.LastSeen.Add(-window)
+
+	// Collect the level history of each supply that is still installed
+	series := make(map[string][]sample)
+	for _, status := range history {
+		if status.LastSeen.Before(since) {
+			continue
+		}
+		for _, supply := range status.Supplies {
+			if supply.Percent < 0 {
+				continue // Level unknown, e.g. Brother's "some remaining"
+			}
+			series[supply.Index] = append(series[supply.Index], sample{
+				at:      status.LastSeen,
+				pages:   status.TotalPages,
+				percent: supply.Percent,
+			})
+		}
+	}
+
+	forecasts := []Forecast{}
+	for _, supply := range latest.Supplies {
+		f := Forecast{
+			Host:        latest.Host,
+			SupplyIndex: supply.Index,
+			Description: supply.Description,
+			Type:        supply.Type,
+			Percent:     supply.Percent,
+		}
+		if supply.Percent >= 0 {
+			fit(&f, series[supply.Index], latest.LastSeen)
+		}
+		forecasts = append(forecasts, f)
+	}
+	return forecasts
+}
+
+// fit estimates the consumption rates and remaining life from a supply's samples
+func fit(f *Forecast, samples []sample, now time.Time) {
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].at.Before(samples[j].at)
+	})
+
+	// A jump up in level means the cartridge was replaced, only the current one counts
+	for i := len(samples) - 1; i > 0; i-- {
+		if samples[i].percent-samples[i-1].percent >= replacementJump {
+			replacedAt := samples[i].at
+			f.ReplacedAt = &replacedAt
+			samples = samples[i:]
+			break
+		}
+	}
+	f.Samples = len(samples)
+	if len(samples) < 2 {
+		return
+	}
+
+	start := samples[0].at
+	days := make([]float64, len(samples))
+	pages := make([]float64, len(samples))
+	levels := make([]float64, len(samples))
+	for i, s := range samples {
+		days[i] = s.at.Sub(start).Hours() / 24
+		pages[i] = float64(s.pages)
+		levels[i] = float64(s.percent)
+	}
+
+	if slope, ok := slope(days, levels); ok && slope < 0 {
+		f.PercentPerDay = -slope
+		remaining := float64(f.Percent) / f.PercentPerDay
+		depletion := now.Add(time.Duration(remaining * 24 * float64(time.Hour)))
+		f.DaysRemaining = &remaining
+		f.DepletionDate = &depletion
+	}
+
+	if slope, ok := slope(pages, levels); ok && slope < 0 {
+		f.PercentPerPage = -slope
+		remaining := int(float64(f.Percent) / f.PercentPerPage)
+		f.PagesRemaining = &remaining
+	}
+}
+
+// slope fits y = a + b*x by least squares and returns b. It fails when x does not vary.
+func slope(x, y []float64) (float64, bool) {
+	n := float64(len(x))
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range x {
+		sumX += x[i]
+		sumY += y[i]
+		sumXY += x[i] * y[i]
+		sumXX += x[i] * x[i]
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, false
+	}
+	return (n*sumXY - sumX*sumY) / denominator, true
+}
@@ -0,0 +1,106 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+var start = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// printer returns daily polls of a printer with one toner at the given levels,
+// printing 100 pages a day
+func printer(levels ...int) []*snmp.PrinterStatus {
+	var history []*snmp.PrinterStatus
+	for day, level := range levels {
+		history = append(history, &snmp.PrinterStatus{
+			Host:       "10.0.0.5",
+			TotalPages: 1000 + 100*day,
+			Supplies:   []snmp.Supply{{Index: "1.1", Description: "Black Toner", Type: 3, Percent: level}},
+			LastSeen:   start.AddDate(0, 0, day),
+		})
+	}
+	return history
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestSupplies(t *testing.T) {
+	forecasts := Supplies(printer(60, 58, 56, 54, 52), DefaultWindow)
+	if len(forecasts) != 1 {
+		t.Fatalf("forecasts = %+v", forecasts)
+	}
+	f := forecasts[0]
+	if f.Host != "10.0.0.5" || f.SupplyIndex != "1.1" || f.Description != "Black Toner" || f.Type != 3 || f.Percent != 52 || f.Samples != 5 {
+		t.Errorf("forecast = %+v", f)
+	}
+	if !near(f.PercentPerDay, 2) || !near(f.PercentPerPage, 0.02) {
+		t.Errorf("rates = %v per day, %v per page", f.PercentPerDay, f.PercentPerPage)
+	}
+	if f.DaysRemaining == nil || !near(*f.DaysRemaining, 26) {
+		t.Errorf("days remaining = %v", f.DaysRemaining)
+	}
+	if f.PagesRemaining == nil || *f.PagesRemaining != 2600 {
+		t.Errorf("pages remaining = %v", f.PagesRemaining)
+	}
+	if want := start.AddDate(0, 0, 4+26); f.DepletionDate == nil || !f.DepletionDate.Equal(want) {
+		t.Errorf("depletion date = %v, want %v", f.DepletionDate, want)
+	}
+	if f.ReplacedAt != nil {
+		t.Errorf("replaced at %v", f.ReplacedAt)
+	}
+}
+
+func TestSuppliesReplaced(t *testing.T) {
+	// Only the levels of the new cartridge count, small rises are noise
+	f := Supplies(printer(12, 8, 4, 98, 99, 94), DefaultWindow)[0]
+	if f.ReplacedAt == nil || !f.ReplacedAt.Equal(start.AddDate(0, 0, 3)) || f.Samples != 3 {
+		t.Errorf("replaced at %v, %d samples", f.ReplacedAt, f.Samples)
+	}
+	if !near(f.PercentPerDay, 2) {
+		t.Errorf("percent per day = %v", f.PercentPerDay)
+	}
+
+	// Right after a replacement there is nothing to fit yet
+	f = Supplies(printer(12, 8, 98), DefaultWindow)[0]
+	if f.Samples != 1 || f.DaysRemaining != nil || f.PagesRemaining != nil {
+		t.Errorf("forecast after a replacement = %+v", f)
+	}
+}
+
+func TestSuppliesUnknown(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []*snmp.PrinterStatus
+		window  time.Duration
+		samples int
+	}{
+		{"not used", printer(50, 50, 50), DefaultWindow, 3},
+		{"single poll", printer(50), DefaultWindow, 1},
+		{"level unknown", printer(-1, -1, -1), DefaultWindow, 0},
+		{"used before the window", printer(90, 80, 70, 70, 70, 70), 2 * 24 * time.Hour, 3},
+	}
+	for _, test := range tests {
+		f := Supplies(test.history, test.window)[0]
+		if f.Samples != test.samples || f.DaysRemaining != nil || f.PagesRemaining != nil || f.DepletionDate != nil {
+			t.Errorf("%s: forecast = %+v", test.name, f)
+		}
+	}
+
+	if forecasts := Supplies(nil, DefaultWindow); forecasts == nil || len(forecasts) != 0 {
+		t.Errorf("forecasts without history = %#v", forecasts)
+	}
+}
+
+func TestSlope(t *testing.T) {
+	if b, ok := slope([]float64{0, 1, 2}, []float64{1, 3, 5}); !ok || !near(b, 2) {
+		t.Errorf("slope = %v, %t", b, ok)
+	}
+	if _, ok := slope([]float64{1, 1}, []float64{1, 3}); ok {
+		t.Error("slope of a vertical line")
+	}
+}
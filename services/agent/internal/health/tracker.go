@@ -0,0 +1,166 @@
+package health
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// State is the circuit state of a polling target
+type State string
+
+const (
+	// StateClosed means the target is healthy and polled every interval
+	StateClosed State = "closed"
+	// StateOpen means the target failed repeatedly and is only probed with backoff
+	StateOpen State = "open"
+)
+
+// Target is the health of a single polling target
+type Target struct {
+	Host                string        `json:"host"`
+	State               State         `json:"state"`
+	ConsecutiveFailures int           `json:"consecutive_failures"`
+	LastError           string        `json:"last_error,omitempty"`
+	LastSuccess         time.Time     `json:"last_success"`
+	OfflineSince        time.Time     `json:"offline_since"`     // First failure of the current outage, zero while healthy
+	NextProbe           time.Time     `json:"next_probe"`        // When an open circuit is probed next
+	Backoff             time.Duration `json:"backoff,omitempty"` // Current delay between probes
+}
+
+// Tracker keeps per-target health and decides when unreachable targets are polled
+type Tracker struct {
+	threshold   int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	mu          sync.Mutex
+	targets     map[string]*Target
+}
+
+// NewTracker creates a tracker that opens a target's circuit after threshold
+// consecutive failures. Open circuits are probed after baseBackoff, doubling
+// after every failed probe up to maxBackoff.
+func NewTracker(threshold int, baseBackoff, maxBackoff time.Duration) *Tracker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	if maxBackoff < baseBackoff {
+		maxBackoff = baseBackoff
+	}
+	return &Tracker{
+		threshold:   threshold,
+		baseBackoff: baseBackoff,
+		maxBackoff:  maxBackoff,
+		targets:     make(map[string]*Target),
+	}
+}
+
+// Allow reports whether host should be polled at now. Healthy targets are
+// always allowed; open circuits only once their backoff has elapsed.
+func (t *Tracker) Allow(host string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	target, ok := t.targets[host]
+	if !ok || target.State == StateClosed {
+		return true
+	}
+	return !now.Before(target.NextProbe)
+}
+
+// State returns the circuit state of host
+func (t *Tracker) State(host string) State {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if target, ok := t.targets[host]; ok {
+		return target.State
+	}
+	return StateClosed
+}
+
+// RecordSuccess closes the circuit of host and returns it to the normal interval
+func (t *Tracker) RecordSuccess(host string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	target := t.target(host)
+	target.State = StateClosed
+	target.ConsecutiveFailures = 0
+	target.LastError = ""
+	target.LastSuccess = now
+	target.OfflineSince = time.Time{}
+	target.NextProbe = time.Time{}
+	target.Backoff = 0
+}
+
+// RecordFailure counts a failed poll or probe of host. It returns true when
+// this failure opened the circuit.
+func (t *Tracker) RecordFailure(host string, err error, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	target := t.target(host)
+	target.ConsecutiveFailures++
+	if err != nil {
+		target.LastError = err.Error()
+	}
+	if target.OfflineSince.IsZero() {
+		target.OfflineSince = now
+	}
+
+	opened := false
+	switch {
+	case target.State == StateOpen:
+		// A failed probe, back off further
+		target.Backoff *= 2
+		if target.Backoff > t.maxBackoff {
+			target.Backoff = t.maxBackoff
+		}
+	case target.ConsecutiveFailures >= t.threshold:
+		target.State = StateOpen
+		target.Backoff = t.baseBackoff
+		opened = true
+	default:
+		return false
+	}
+	target.NextProbe = now.Add(target.Backoff)
+	return opened
+}
+
+// Get returns the health of host
+func (t *Tracker) Get(host string) (Target, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	target, ok := t.targets[host]
+	if !ok {
+		return Target{}, false
+	}
+	return *target, true
+}
+
+// All returns the health of every target, sorted by host
+func (t *Tracker) All() []Target {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	all := make([]Target, 0, len(t.targets))
+	for _, target := range t.targets {
+		all = append(all, *target)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Host < all[j].Host
+	})
+	return all
+}
+
+// target returns the entry for host, creating it if needed. The caller must hold t.mu.
+func (t *Tracker) target(host string) *Target {
+	target, ok := t.targets[host]
+	if !ok {
+		target = &Target{Host: host, State: StateClosed}
+		t.targets[host] = target
+	}
+	return target
+}
@@ -0,0 +1,90 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	timeout := errors.New("request timeout")
+	tracker := NewTracker(3, time.Minute, 4*time.Minute)
+
+	if !tracker.Allow("a", now) || tracker.State("a") != StateClosed {
+		t.Fatal("an unknown target isn't polled")
+	}
+
+	// The circuit opens on the third failure in a row
+	for i := 1; i <= 3; i++ {
+		opened := tracker.RecordFailure("a", timeout, now.Add(time.Duration(i)*time.Second))
+		if opened != (i == 3) {
+			t.Errorf("failure %d opened = %t", i, opened)
+		}
+	}
+	target, _ := tracker.Get("a")
+	if target.State != StateOpen || target.ConsecutiveFailures != 3 || target.LastError != "request timeout" ||
+		!target.OfflineSince.Equal(now.Add(time.Second)) || target.Backoff != time.Minute {
+		t.Errorf("after opening = %+v", target)
+	}
+
+	// Open circuits are only probed once the backoff has elapsed
+	opened := now.Add(3 * time.Second)
+	if tracker.Allow("a", opened.Add(59*time.Second)) || !tracker.Allow("a", opened.Add(time.Minute)) {
+		t.Error("probed before or not after the backoff")
+	}
+
+	// Failed probes double the backoff up to the maximum
+	for _, want := range []time.Duration{2 * time.Minute, 4 * time.Minute, 4 * time.Minute} {
+		if tracker.RecordFailure("a", nil, now) {
+			t.Error("a failed probe reopened the circuit")
+		}
+		if target, _ := tracker.Get("a"); target.Backoff != want || !target.NextProbe.Equal(now.Add(want)) {
+			t.Errorf("backoff = %s, next probe %s, want %s", target.Backoff, target.NextProbe, want)
+		}
+	}
+	if target, _ := tracker.Get("a"); target.LastError != "request timeout" {
+		t.Errorf("a failure without an error cleared the last one: %q", target.LastError)
+	}
+
+	tracker.RecordSuccess("a", now.Add(time.Hour))
+	target, _ = tracker.Get("a")
+	if target.State != StateClosed || target.ConsecutiveFailures != 0 || target.LastError != "" ||
+		!target.OfflineSince.IsZero() || target.Backoff != 0 || !target.LastSuccess.Equal(now.Add(time.Hour)) {
+		t.Errorf("after recovering = %+v", target)
+	}
+	if !tracker.Allow("a", now) {
+		t.Error("a recovered target isn't polled")
+	}
+}
+
+func TestTrackerFailuresReset(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(2, time.Minute, time.Minute)
+
+	// A success in between starts the count over
+	tracker.RecordFailure("a", nil, now)
+	tracker.RecordSuccess("a", now)
+	if tracker.RecordFailure("a", nil, now) {
+		t.Error("opened on failures that weren't consecutive")
+	}
+}
+
+func TestTrackerAll(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(0, time.Minute, time.Second)
+	if tracker.threshold != 1 || tracker.maxBackoff != time.Minute {
+		t.Errorf("threshold, max backoff = %d, %s", tracker.threshold, tracker.maxBackoff)
+	}
+
+	tracker.RecordSuccess("c", now)
+	tracker.RecordFailure("a", nil, now)
+	tracker.RecordSuccess("b", now)
+	all := tracker.All()
+	if len(all) != 3 || all[0].Host != "a" || all[0].State != StateOpen || all[2].Host != "c" {
+		t.Errorf("All = %+v", all)
+	}
+	if _, ok := tracker.Get("d"); ok {
+		t.Error("Get of an unknown target succeeded")
+	}
+}
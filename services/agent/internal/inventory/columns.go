@@ -0,0 +1,103 @@
+package inventory
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"lynk/agent/internal/config"
+	"lynk/agent/internal/snmp"
+)
+
+// device is the data a column is drawn from
+type device struct {
+	status *snmp.PrinterStatus
+	target config.Target
+}
+
+// column extracts one inventory column from a device
+type column func(d device) string
+
+// columns lists every column an inventory can contain
+var columns = map[string]column{
+	"host":               func(d device) string { return d.status.Host },
+	"name":               func(d device) string { return d.target.Name },
+	"site":               func(d device) string { return d.target.Site },
+	"department":         func(d device) string { return d.target.Department },
+	"tags":               func(d device) string { return strings.Join(d.target.Tags, ";") },
+	"model":              func(d device) string { return d.status.Model },
+	"serial_number":      func(d device) string { return d.status.SerialNumber },
+	"firmware_version":   func(d device) string { return d.status.FirmwareVersion },
+	"device_name":        func(d device) string { return d.status.DeviceName },
+	"printer_name":       func(d device) string { return d.status.PrinterName },
+	"system_description": func(d device) string { return d.status.SystemDescription },
+	"location":           func(d device) string { return d.status.Location },
+	"contact":            func(d device) string { return d.status.Contact },
+	"object_id":          func(d device) string { return d.status.ObjectID },
+	"vendor":             func(d device) string { return d.status.Vendor },
+	"mac":                func(d device) string { return d.status.MACAddress },
+	"uuid":               func(d device) string { return d.status.UUID },
+	"protocol":           func(d device) string { return d.status.Protocol },
+	"status":             func(d device) string { return string(d.status.Status) },
+	"device_state":       func(d device) string { return string(d.status.DeviceState) },
+	"health":             func(d device) string { return string(d.status.Health) },
+	"total_pages":        func(d device) string { return strconv.Itoa(d.status.TotalPages) },
+	"paper_trays":        func(d device) string { return strconv.Itoa(len(d.status.PaperTrays)) },
+	"output_bins":        func(d device) string { return strconv.Itoa(len(d.status.OutputBins)) },
+	"duplex":             func(d device) string { return duplex(d.status.Capabilities) },
+	"max_media_size":     func(d device) string { return maxMediaSize(d.status.Capabilities) },
+	"languages":          func(d device) string { return strings.Join(d.status.Capabilities.Languages, ";") },
+	"memory_size":        func(d device) string { return d.status.MemorySize },
+	"disk_used":          func(d device) string { return diskUsed(d.status.Storage) },
+	"last_seen":          func(d device) string { return d.status.LastSeen.Format("2006-01-02 15:04:05") },
+}
+
+// DefaultColumns are exported when no columns are given
+var DefaultColumns = []string{
+	"host", "name", "site", "department", "location", "contact", "model", "serial_number", "mac",
+	"firmware_version", "total_pages", "last_seen",
+}
+
+// Columns returns the name of every available column
+func Columns() []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// duplex reports whether the printer duplexes, "" when its media paths are unknown
+func duplex(c snmp.Capabilities) string {
+	if len(c.MediaPaths) == 0 {
+		return ""
+	}
+	return strconv.FormatBool(c.Duplex)
+}
+
+// maxMediaSize names the largest media the printer takes, e.g. "A3"
+func maxMediaSize(c snmp.Capabilities) string {
+	if c.MaxMediaSize == nil {
+		return ""
+	}
+	if name := c.MaxMediaSize.Name(); name != "" {
+		return name
+	}
+	return c.MaxMediaSize.String()
+}
+
+// diskUsed is the used percentage of the fullest disk, "" when the printer
+// reports none
+func diskUsed(storage []snmp.Storage) string {
+	fullest := -1
+	for _, s := range storage {
+		if s.Disk() && s.Percent > fullest {
+			fullest = s.Percent
+		}
+	}
+	if fullest < 0 {
+		return ""
+	}
+	return strconv.Itoa(fullest)
+}
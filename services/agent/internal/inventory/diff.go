@@ -0,0 +1,125 @@
+package inventory
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Difference kinds
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// FieldChange is a column whose value differs between two inventories
+type FieldChange struct {
+	Column string `json:"column"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// Difference is a device that was added, removed or changed between two inventories
+type Difference struct {
+	Key     string        `json:"key"` // Serial number, or host when there is none
+	Kind    string        `json:"kind"`
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// Diff compares two inventories. Only columns present in both are compared.
+func Diff(before, after *Inventory) []Difference {
+	shared := []string{}
+	inBefore := make(map[string]bool)
+	for _, name := range before.Columns {
+		inBefore[name] = true
+	}
+	for _, name := range after.Columns {
+		if inBefore[name] {
+			shared = append(shared, name)
+		}
+	}
+
+	beforeRows := make(map[string]map[string]string)
+	for _, row := range before.Rows {
+		beforeRows[key(row, shared)] = row
+	}
+	afterRows := make(map[string]map[string]string)
+	for _, row := range after.Rows {
+		afterRows[key(row, shared)] = row
+	}
+
+	diffs := []Difference{}
+	for key, row := range afterRows {
+		old, ok := beforeRows[key]
+		if !ok {
+			diffs = append(diffs, Difference{Key: key, Kind: Added})
+			continue
+		}
+
+		var changes []FieldChange
+		for _, name := range shared {
+			if old[name] != row[name] {
+				changes = append(changes, FieldChange{Column: name, Old: old[name], New: row[name]})
+			}
+		}
+		if len(changes) > 0 {
+			diffs = append(diffs, Difference{Key: key, Kind: Changed, Changes: changes})
+		}
+	}
+	for key := range beforeRows {
+		if _, ok := afterRows[key]; !ok {
+			diffs = append(diffs, Difference{Key: key, Kind: Removed})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
+
+// key identifies a row across inventories: its serial number when both
+// inventories have one, otherwise its host, otherwise its first shared column
+func key(row map[string]string, shared []string) string {
+	for _, name := range []string{"serial_number", "host"} {
+		if contains(shared, name) && row[name] != "" {
+			return row[name]
+		}
+	}
+	if len(shared) > 0 {
+		return row[shared[0]]
+	}
+	return ""
+}
+
+// contains reports whether list contains value
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteDiff prints differences in a human readable form
+func WriteDiff(w io.Writer, diffs []Difference) {
+	if len(diffs) == 0 {
+		fmt.Fprintln(w, "No differences")
+		return
+	}
+	for _, d := range diffs {
+		switch d.Kind {
+		case Added:
+			fmt.Fprintf(w, "+ %s\n", d.Key)
+		case Removed:
+			fmt.Fprintf(w, "- %s\n", d.Key)
+		case Changed:
+			fmt.Fprintf(w, "~ %s\n", d.Key)
+			for _, c := range d.Changes {
+				fmt.Fprintf(w, "    %s: %q -> %q\n", c.Column, c.Old, c.New)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package inventory
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := &Inventory{
+		Columns: []string{"host", "serial_number", "location", "firmware_version"},
+		Rows: []map[string]string{
+			{"host": "10.0.0.1", "serial_number": "E1", "location": "Lobby", "firmware_version": "1.0"},
+			{"host": "10.0.0.2", "serial_number": "E2", "location": "Office", "firmware_version": "1.0"},
+			{"host": "10.0.0.3", "serial_number": "", "location": "Annex", "firmware_version": "2.0"},
+		},
+	}
+	after := &Inventory{
+		// total_pages is new, firmware_version isn't exported anymore
+		Columns: []string{"host", "serial_number", "location", "total_pages"},
+		Rows: []map[string]string{
+			{"host": "10.0.0.7", "serial_number": "E1", "location": "Lobby", "total_pages": "100"}, // Moved address
+			{"host": "10.0.0.2", "serial_number": "E2", "location": "Print room", "total_pages": "200"},
+			{"host": "10.0.0.4", "serial_number": "E4", "location": "Annex", "total_pages": "300"},
+		},
+	}
+
+	want := []Difference{
+		{Key: "10.0.0.3", Kind: Removed},
+		{Key: "E1", Kind: Changed, Changes: []FieldChange{{Column: "host", Old: "10.0.0.1", New: "10.0.0.7"}}},
+		{Key: "E2", Kind: Changed, Changes: []FieldChange{{Column: "location", Old: "Office", New: "Print room"}}},
+		{Key: "E4", Kind: Added},
+	}
+	if got := Diff(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := Diff(after, after); len(got) != 0 {
+		t.Errorf("Diff of an inventory with itself = %+v", got)
+	}
+}
+
+func TestDiffWithoutSerials(t *testing.T) {
+	// Inventories without serial or host columns match rows by their first shared column
+	before := &Inventory{Columns: []string{"mac", "model"}, Rows: []map[string]string{{"mac": "00:11", "model": "A"}}}
+	after := &Inventory{Columns: []string{"name", "mac", "model"}, Rows: []map[string]string{{"name": "x", "mac": "00:11", "model": "B"}}}
+
+	want := []Difference{{Key: "00:11", Kind: Changed, Changes: []FieldChange{{Column: "model", Old: "A", New: "B"}}}}
+	if got := Diff(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteDiff(t *testing.T) {
+	var buf bytes.Buffer
+	WriteDiff(&buf, []Difference{
+		{Key: "E1", Kind: Changed, Changes: []FieldChange{{Column: "location", Old: "Lobby", New: ""}}},
+		{Key: "E4", Kind: Added},
+		{Key: "E5", Kind: Removed},
+	})
+	want := "~ E1\n    location: \"Lobby\" -> \"\"\n+ E4\n- E5\n"
+	if buf.String() != want {
+		t.Errorf("WriteDiff = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	WriteDiff(&buf, nil)
+	if buf.String() != "No differences\n" {
+		t.Errorf("WriteDiff of nothing = %q", buf.String())
+	}
+}
@@ -0,0 +1,118 @@
+package inventory
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Formats an inventory can be exported as
+const (
+	FormatCSV   = "csv"
+	FormatExcel = "xlsx-csv" // CSV that Excel opens correctly: UTF-8 BOM and CRLF line endings
+	FormatJSON  = "json"
+)
+
+// utf8BOM tells Excel the CSV is UTF-8 rather than the system code page
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Write exports the inventory in the given format
+func (inv *Inventory) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatCSV:
+		return inv.writeCSV(w, false)
+	case FormatExcel:
+		if _, err := w.Write(utf8BOM); err != nil {
+			return err
+		}
+		return inv.writeCSV(w, true)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(inv.Rows)
+	default:
+		return fmt.Errorf("unknown format %q, expected csv, xlsx-csv or json", format)
+	}
+}
+
+// writeCSV writes a header row followed by one row per device
+func (inv *Inventory) writeCSV(w io.Writer, crlf bool) error {
+	out := csv.NewWriter(w)
+	out.UseCRLF = crlf
+
+	out.Write(inv.Columns)
+	for _, row := range inv.Rows {
+		record := make([]string, len(inv.Columns))
+		for i, name := range inv.Columns {
+			record[i] = row[name]
+		}
+		out.Write(record)
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// Read loads an inventory previously exported in any format, detected from its content
+func Read(r io.Reader) (*Inventory, error) {
+	data, err := io.ReadAll(bufio.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory: %w", err)
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return readJSON(trimmed)
+	}
+	return readCSV(data)
+}
+
+// readJSON loads an inventory exported as JSON
+func readJSON(data []byte) (*Inventory, error) {
+	inv := &Inventory{}
+	if err := json.Unmarshal(data, &inv.Rows); err != nil {
+		return nil, fmt.Errorf("failed to decode inventory: %w", err)
+	}
+
+	// JSON objects are unordered, list the columns alphabetically
+	seen := make(map[string]bool)
+	for _, row := range inv.Rows {
+		for name := range row {
+			if !seen[name] {
+				seen[name] = true
+				inv.Columns = append(inv.Columns, name)
+			}
+		}
+	}
+	sort.Strings(inv.Columns)
+	return inv, nil
+}
+
+// readCSV loads an inventory exported as CSV
+func readCSV(data []byte) (*Inventory, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1 // Rows edited by hand may lack trailing columns
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode inventory: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("inventory is empty")
+	}
+
+	inv := &Inventory{Columns: records[0]}
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(inv.Columns))
+		for i, name := range inv.Columns {
+			if i < len(record) {
+				row[name] = record[i]
+			}
+		}
+		inv.Rows = append(inv.Rows, row)
+	}
+	return inv, nil
+}
@@ -0,0 +1,73 @@
+package inventory
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var sample = &Inventory{
+	Columns: []string{"host", "name", "location"},
+	Rows: []map[string]string{
+		{"host": "10.0.0.1", "name": "Reception", "location": "Lobby, ground floor"},
+		{"host": "10.0.0.2", "name": "Büro", "location": ""},
+	},
+}
+
+func TestWriteRead(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatExcel, FormatJSON} {
+		var buf bytes.Buffer
+		if err := sample.Write(&buf, format); err != nil {
+			t.Fatalf("%s: Write: %v", format, err)
+		}
+		inv, err := Read(&buf)
+		if err != nil {
+			t.Fatalf("%s: Read: %v", format, err)
+		}
+
+		columns := sample.Columns
+		if format == FormatJSON {
+			columns = []string{"host", "location", "name"} // Alphabetical, JSON has no column order
+		}
+		if !reflect.DeepEqual(inv.Columns, columns) || !reflect.DeepEqual(inv.Rows, sample.Rows) {
+			t.Errorf("%s: read back %+v", format, inv)
+		}
+	}
+}
+
+func TestWriteExcel(t *testing.T) {
+	var buf bytes.Buffer
+	sample.Write(&buf, FormatExcel)
+	if !bytes.HasPrefix(buf.Bytes(), utf8BOM) {
+		t.Error("no byte order mark")
+	}
+	if !strings.Contains(buf.String(), "host,name,location\r\n") {
+		t.Errorf("no CRLF line endings: %q", buf.String())
+	}
+
+	if err := sample.Write(&buf, "xlsx"); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
+
+func TestRead(t *testing.T) {
+	// Rows shorter than the header leave the missing columns empty
+	inv, err := Read(strings.NewReader("host,model,serial_number\n10.0.0.1,LaserJet\n"))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if want := map[string]string{"host": "10.0.0.1", "model": "LaserJet"}; !reflect.DeepEqual(inv.Rows[0], want) {
+		t.Errorf("row = %q", inv.Rows[0])
+	}
+
+	for name, data := range map[string]string{
+		"empty":        "",
+		"invalid JSON": "[{\"host\": 1}]",
+		"invalid CSV":  "host,\"model\n",
+	} {
+		if _, err := Read(strings.NewReader(data)); err == nil {
+			t.Errorf("%s inventory accepted", name)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package inventory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"lynk/agent/internal/config"
+	"lynk/agent/internal/snmp"
+)
+
+// Inventory is a table of printers with one row per physical device
+type Inventory struct {
+	Columns []string
+	Rows    []map[string]string
+}
+
+// Build creates an inventory from the latest poll of every host. A device
+// that answered on several addresses is listed once, from its latest poll.
+func Build(latest []*snmp.PrinterStatus, cfg *config.Config, names []string) (*Inventory, error) {
+	if len(names) == 0 {
+		names = DefaultColumns
+	}
+	for _, name := range names {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("unknown column %q, available: %s", name, strings.Join(Columns(), ", "))
+		}
+	}
+
+	// Deduplicate by serial number, or MAC address without one, keeping the freshest poll
+	devices := make(map[string]*snmp.PrinterStatus)
+	for _, status := range latest {
+		key := status.SerialNumber
+		if key == "" && status.MACAddress != "" {
+			key = "mac:" + status.MACAddress
+		}
+		if key == "" {
+			key = "host:" + status.Host
+		}
+		if existing, ok := devices[key]; !ok || status.LastSeen.After(existing.LastSeen) {
+			devices[key] = status
+		}
+	}
+
+	sorted := make([]*snmp.PrinterStatus, 0, len(devices))
+	for _, status := range devices {
+		sorted = append(sorted, status)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Host < sorted[j].Host
+	})
+
+	inv := &Inventory{Columns: names, Rows: []map[string]string{}}
+	for _, status := range sorted {
+		d := device{status: status, target: cfg.Target(status.Host)}
+		row := make(map[string]string, len(names))
+		for _, name := range names {
+			row[name] = columns[name](d)
+		}
+		inv.Rows = append(inv.Rows, row)
+	}
+	return inv, nil
+}
+
+// ParseColumns splits a comma separated column list
+func ParseColumns(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
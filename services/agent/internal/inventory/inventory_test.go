@@ -0,0 +1,97 @@
+package inventory
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"lynk/agent/internal/config"
+	"lynk/agent/internal/snmp"
+)
+
+func polled(host, serial, mac string, minute int) *snmp.PrinterStatus {
+	return &snmp.PrinterStatus{
+		Host:         host,
+		SerialNumber: serial,
+		MACAddress:   mac,
+		Model:        "LaserJet 4250",
+		TotalPages:   1000 + minute,
+		LastSeen:     time.Date(2026, 3, 1, 12, minute, 0, 0, time.UTC),
+	}
+}
+
+func TestBuild(t *testing.T) {
+	latest := []*snmp.PrinterStatus{
+		polled("10.0.0.9", "E1", "", 5),
+		polled("10.0.0.2", "E1", "", 1), // The same device on an older address
+		polled("10.0.0.3", "", "00:11:22:33:44:55", 2),
+		polled("10.0.0.4", "", "00:11:22:33:44:55", 3),
+		polled("10.0.0.5", "", "", 4),
+		polled("10.0.0.6", "", "", 4),
+	}
+	cfg := &config.Config{Targets: []config.Target{
+		{Host: "10.0.0.9", Name: "Reception", Site: "HQ", Tags: []string{"color", "a3"}},
+	}}
+
+	inv, err := Build(latest, cfg, []string{"host", "name", "tags", "serial_number", "total_pages"})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	var hosts []string
+	for _, row := range inv.Rows {
+		hosts = append(hosts, row["host"])
+	}
+	if want := []string{"10.0.0.4", "10.0.0.5", "10.0.0.6", "10.0.0.9"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("hosts = %q, want %q", hosts, want)
+	}
+	want := map[string]string{"host": "10.0.0.9", "name": "Reception", "tags": "color;a3", "serial_number": "E1", "total_pages": "1005"}
+	if row := inv.Rows[3]; !reflect.DeepEqual(row, want) {
+		t.Errorf("row = %q, want %q", row, want)
+	}
+
+	defaults, _ := Build(latest, cfg, nil)
+	if !reflect.DeepEqual(defaults.Columns, DefaultColumns) || defaults.Rows[0]["last_seen"] != "2026-03-01 12:03:00" {
+		t.Errorf("default columns = %q, row %q", defaults.Columns, defaults.Rows[0])
+	}
+
+	if _, err := Build(latest, cfg, []string{"host", "colour"}); err == nil || !strings.Contains(err.Error(), `"colour"`) {
+		t.Errorf("unknown column error = %v", err)
+	}
+	if empty, err := Build(nil, cfg, nil); err != nil || empty.Rows == nil || len(empty.Rows) != 0 {
+		t.Errorf("empty inventory = %+v, %v", empty, err)
+	}
+}
+
+func TestColumns(t *testing.T) {
+	status := &snmp.PrinterStatus{
+		Capabilities: snmp.Capabilities{Duplex: true, MediaPaths: []snmp.MediaPath{{}}, Languages: []string{"PCL", "PostScript"}},
+		Storage: []snmp.Storage{
+			{Type: "fixed_disk", Percent: 40},
+			{Type: "ram", Percent: 90},
+			{Type: "flash_memory", Percent: 70},
+		},
+	}
+	d := device{status: status}
+	for name, want := range map[string]string{"duplex": "true", "languages": "PCL;PostScript", "disk_used": "70"} {
+		if got := columns[name](d); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	unknown := device{status: &snmp.PrinterStatus{}}
+	for _, name := range []string{"duplex", "max_media_size", "disk_used"} {
+		if got := columns[name](unknown); got != "" {
+			t.Errorf("%s of an unknown device = %q", name, got)
+		}
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	if got := ParseColumns(" host, model,,serial_number "); !reflect.DeepEqual(got, []string{"host", "model", "serial_number"}) {
+		t.Errorf("ParseColumns = %q", got)
+	}
+	if got := ParseColumns(""); got != nil {
+		t.Errorf("ParseColumns of nothing = %q", got)
+	}
+}
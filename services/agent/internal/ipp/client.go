@@ -0,0 +1,140 @@
+// Package ipp collects printer status over the Internet Printing Protocol,
+// for printers that have SNMP disabled but answer Get-Printer-Attributes.
+package ipp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+// DefaultPort is the port IPP printers listen on
+const DefaultPort = 631
+
+// maxResponse bounds the size of a response the client reads
+const maxResponse = 1 << 20
+
+// attributes are the printer attributes a poll requests
+var attributes = []string{
+	"printer-state",
+	"printer-state-reasons",
+	"printer-state-message",
+	"printer-make-and-model",
+	"printer-device-id",
+	"printer-uuid",
+	"printer-firmware-string-version",
+	"printer-name",
+	"printer-location",
+	"printer-info",
+	"printer-up-time",
+	"printer-input-tray",
+	"printer-output-tray",
+	"marker-names",
+	"marker-levels",
+	"marker-colors",
+	"marker-types",
+	"printer-impressions-completed",
+	"printer-impressions-completed-col",
+}
+
+// Client represents an IPP client for printer monitoring
+type Client struct {
+	path      string
+	timeout   time.Duration
+	http      *http.Client
+	requestID int32
+}
+
+// NewClient creates a new IPP client for printers serving IPP at path, usually "/ipp/print"
+func NewClient(path string) *Client {
+	if path == "" {
+		path = "/ipp/print"
+	}
+	return &Client{
+		path:    path,
+		timeout: 10 * time.Second,
+		http:    &http.Client{},
+	}
+}
+
+// Probe checks whether a printer answers IPP at all, using a single short
+// request instead of a full poll
+func (c *Client) Probe(ctx context.Context, host string) error {
+	timeout := c.timeout
+	if timeout > 3*time.Second {
+		timeout = 3 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	_, err := c.GetPrinterAttributes(ctx, host, []string{"printer-state"})
+	return err
+}
+
+// Poll queries a printer for its status
+func (c *Client) Poll(host string) (*snmp.PrinterStatus, error) {
+	return c.PollContext(context.Background(), host)
+}
+
+// PollContext queries a printer for its status over IPP and maps it into the
+// same PrinterStatus an SNMP poll produces
+func (c *Client) PollContext(ctx context.Context, host string) (*snmp.PrinterStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	attrs, err := c.GetPrinterAttributes(ctx, host, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return Status(host, attrs, time.Now()), nil
+}
+
+// GetPrinterAttributes sends a Get-Printer-Attributes request. host may carry
+// a port, otherwise DefaultPort is used.
+func (c *Client) GetPrinterAttributes(ctx context.Context, host string, requested []string) (Attributes, error) {
+	address := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		address = net.JoinHostPort(host, strconv.Itoa(DefaultPort))
+	}
+
+	requestID := atomic.AddInt32(&c.requestID, 1)
+	body := encodeRequest(requestID, "ipp://"+address+c.path, requested)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+address+c.path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/ipp")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("printer %s not responding: %w", host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("printer %s: HTTP %s", host, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponse))
+	if err != nil {
+		return nil, fmt.Errorf("printer %s: %w", host, err)
+	}
+	decoded, err := decodeResponse(data)
+	if err != nil {
+		return nil, fmt.Errorf("printer %s: %w", host, err)
+	}
+	if decoded.status > 0x00FF { // Anything but successful-ok*
+		return nil, fmt.Errorf("printer %s: IPP status 0x%04x", host, decoded.status)
+	}
+	if decoded.requestID != requestID {
+		return nil, fmt.Errorf("printer %s: response to request %d, expected %d", host, decoded.requestID, requestID)
+	}
+	return decoded.attributes, nil
+}
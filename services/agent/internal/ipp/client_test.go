@@ -0,0 +1,256 @@
+package ipp
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"lynk/agent/internal/snmp"
+)
+
+// attribute is one value of an encoded test response. An empty name adds a
+// value to the previous attribute.
+type attribute struct {
+	tag   byte
+	name  string
+	value []byte
+}
+
+func keyword(name, value string) attribute {
+	return attribute{tagKeyword, name, []byte(value)}
+}
+
+func text(name, value string) attribute {
+	return attribute{tagText, name, []byte(value)}
+}
+
+func octets(name, value string) attribute {
+	return attribute{tagOctetString, name, []byte(value)}
+}
+
+// member names the collection member the following value belongs to
+func member(name string) attribute {
+	return attribute{tagMemberName, "", []byte(name)}
+}
+
+func integer(tag byte, name string, value int32) attribute {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, uint32(value))
+	return attribute{tag, name, data}
+}
+
+// encodeResponse builds an IPP response with an operation attributes group
+// followed by a printer attributes group
+func encodeResponse(status uint16, requestID int32, printer []attribute) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{2, 0})
+	binary.Write(&buf, binary.BigEndian, status)
+	binary.Write(&buf, binary.BigEndian, requestID)
+	buf.WriteByte(tagOperationGroup)
+	writeAttribute(&buf, tagCharset, "attributes-charset", "utf-8")
+	writeAttribute(&buf, tagNaturalLanguage, "attributes-natural-language", "en")
+	buf.WriteByte(0x04) // printer-attributes-tag
+	for _, a := range printer {
+		buf.WriteByte(a.tag)
+		binary.Write(&buf, binary.BigEndian, uint16(len(a.name)))
+		buf.WriteString(a.name)
+		binary.Write(&buf, binary.BigEndian, uint16(len(a.value)))
+		buf.Write(a.value)
+	}
+	buf.WriteByte(tagEndOfAttributes)
+	return buf.Bytes()
+}
+
+// printerServer serves a Get-Printer-Attributes response with the given
+// printer attributes, echoing the request ID of each request
+func printerServer(t *testing.T, printer []attribute) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/ipp/print" || r.Header.Get("Content-Type") != "application/ipp" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+
+		// A request has the same layout as a response, with the operation in
+		// place of the status
+		request, err := decodeResponse(body)
+		if err != nil || request.status != opGetPrinterAttributes {
+			t.Errorf("invalid request: %v", err)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if !strings.HasPrefix(request.attributes.String("printer-uri"), "ipp://") {
+			t.Errorf("printer-uri = %q", request.attributes.String("printer-uri"))
+		}
+		if len(request.attributes.Strings("requested-attributes")) == 0 {
+			t.Errorf("no requested-attributes")
+		}
+
+		w.Header().Set("Content-Type", "application/ipp")
+		w.Write(encodeResponse(0x0000, request.requestID, printer))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// host returns the host:port of a test server
+func host(server *httptest.Server) string {
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestPollContext(t *testing.T) {
+	server := printerServer(t, []attribute{
+		integer(tagEnum, "printer-state", 5),
+		keyword("printer-state-reasons", "media-empty-error"),
+		keyword("", "cover-open"),
+		keyword("", "toner-low-report"),
+		text("printer-state-message", "Load paper in tray 2"),
+		text("printer-make-and-model", "Brother HL-L8360CDW series"),
+		text("printer-device-id", "MFG:Brother;MDL:HL-L8360CDW series;CMD:PJL,PCL,URF;SN:E12345;"),
+		keyword("printer-uuid", "urn:uuid:e3248000-80ce-11db-8000-30055c5b1b6a"),
+		integer(tagInteger, "printer-up-time", 3600),
+		text("marker-names", "Black Toner"),
+		text("", "Drum Unit"),
+		integer(tagInteger, "marker-levels", 40),
+		integer(tagInteger, "", -2),
+		keyword("marker-colors", "#000000"),
+		keyword("", "none"),
+		keyword("marker-types", "toner-cartridge"),
+		keyword("", "opc"),
+		octets("printer-input-tray", "type=sheetFeedAutoRemovableTray;maxcapacity=250;level=0;status=0;name=Tray 1;"),
+		octets("", "type=sheetFeedManual;maxcapacity=1;level=-2;name=MP Tray;"),
+		octets("printer-output-tray", "type=unRemovableBin;maxcapacity=150;remaining=150;status=0;stackingorder=lastToFirst;name=Face Down;"),
+		integer(tagInteger, "printer-impressions-completed", 12345),
+		attribute{tagBegCollection, "printer-impressions-completed-col", nil},
+		member("full-color"),
+		integer(tagInteger, "", 2345),
+		member("monochrome"),
+		integer(tagInteger, "", 10000),
+		attribute{tagEndCollection, "", nil},
+	})
+
+	status, err := NewClient("").PollContext(context.Background(), host(server))
+	if err != nil {
+		t.Fatalf("PollContext: %v", err)
+	}
+
+	if status.Protocol != "ipp" || status.Status != snmp.PrinterStopped || status.DeviceState != snmp.DeviceDown {
+		t.Errorf("protocol, state, device state = %s, %s, %s", status.Protocol, status.Status, status.DeviceState)
+	}
+	if status.Manufacturer != "Brother" || status.Model != "Brother HL-L8360CDW series" || status.SerialNumber != "E12345" {
+		t.Errorf("identity = %q %q %q", status.Manufacturer, status.Model, status.SerialNumber)
+	}
+	if status.Uptime != 360000 {
+		t.Errorf("uptime = %d, want 360000 TimeTicks", status.Uptime)
+	}
+	if status.TotalPages != 12345 || status.ColorPages != 2345 || status.MonoPages != 10000 {
+		t.Errorf("total, color, mono pages = %d, %d, %d", status.TotalPages, status.ColorPages, status.MonoPages)
+	}
+	if want := []string{"media-empty-error", "cover-open"}; !equal(status.ActiveAlerts, want) {
+		t.Errorf("active alerts = %q, want %q", status.ActiveAlerts, want)
+	}
+	if status.PaperStatus != "paper_out" || status.LastError != "Load paper in tray 2" || status.ErrorCount != 2 {
+		t.Errorf("paper status, last error, error count = %q, %q, %d", status.PaperStatus, status.LastError, status.ErrorCount)
+	}
+	if len(status.Covers) != 1 || !status.Covers[0].Open {
+		t.Errorf("covers = %+v", status.Covers)
+	}
+
+	if len(status.Supplies) != 2 {
+		t.Fatalf("supplies = %+v", status.Supplies)
+	}
+	if s := status.Supplies[0]; s.Type != 21 || s.Percent != 40 || s.Color != "#000000" {
+		t.Errorf("toner = %+v", s)
+	}
+	if s := status.Supplies[1]; s.Type != 9 || s.Percent != -1 || s.Level != -2 {
+		t.Errorf("drum = %+v", s)
+	}
+	if status.TonerLevel != 40 {
+		t.Errorf("toner level = %d", status.TonerLevel)
+	}
+
+	if len(status.PaperTrays) != 2 {
+		t.Fatalf("trays = %+v", status.PaperTrays)
+	}
+	if tray := status.PaperTrays[0]; tray.Name != "Tray 1" || tray.Condition != "empty" || tray.Type != 3 {
+		t.Errorf("tray 1 = %+v", tray)
+	}
+	if tray := status.PaperTrays[1]; tray.Status != snmp.SubUnitUnknown || tray.State.Availability != "unknown" || tray.Condition != "unknown" {
+		t.Errorf("tray without a status = %+v", tray)
+	}
+	if len(status.OutputBins) != 1 || status.OutputBins[0].StackingOrder != "last_to_first" || status.OutputBins[0].PercentFull != 0 {
+		t.Errorf("output bins = %+v", status.OutputBins)
+	}
+}
+
+func TestGetPrinterAttributesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    string
+	}{
+		{"HTTP error", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "nope", http.StatusInternalServerError)
+		}, "HTTP 500"},
+		{"IPP error status", func(w http.ResponseWriter, r *http.Request) {
+			w.Write(encodeResponse(0x0400, requestID(r), nil)) // client-error-bad-request
+		}, "IPP status 0x0400"},
+		{"request ID mismatch", func(w http.ResponseWriter, r *http.Request) {
+			w.Write(encodeResponse(0x0000, requestID(r)+1, nil))
+		}, "expected"},
+		{"truncated", func(w http.ResponseWriter, r *http.Request) {
+			data := encodeResponse(0x0000, requestID(r), []attribute{text("printer-name", "Printer")})
+			w.Write(data[:len(data)-4])
+		}, "truncated"},
+		{"bad version", func(w http.ResponseWriter, r *http.Request) {
+			data := encodeResponse(0x0000, requestID(r), nil)
+			data[0] = 9
+			w.Write(data)
+		}, "unsupported version"},
+	}
+	for _, test := range tests {
+		server := httptest.NewServer(test.handler)
+		_, err := NewClient("").GetPrinterAttributes(context.Background(), host(server), []string{"printer-state"})
+		server.Close()
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error = %v, want one containing %q", test.name, err, test.want)
+		}
+	}
+}
+
+func TestProbeUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	address := host(server)
+	server.Close()
+
+	if err := NewClient("").Probe(context.Background(), address); err == nil {
+		t.Error("Probe of a closed server succeeded")
+	}
+}
+
+// requestID reads the request ID of a request
+func requestID(r *http.Request) int32 {
+	body, _ := io.ReadAll(r.Body)
+	if len(body) < 8 {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(body[4:8]))
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,260 @@
+package ipp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Operations used by the collector
+const (
+	opGetPrinterAttributes = 0x000B
+)
+
+// Delimiter tags that start an attribute group (RFC 8010 section 3.5.1)
+const (
+	tagOperationGroup  = 0x01
+	tagEndOfAttributes = 0x03
+	maxDelimiterTag    = 0x0F
+)
+
+// Value tags (RFC 8010 section 3.5.2)
+const (
+	tagInteger          = 0x21
+	tagBoolean          = 0x22
+	tagEnum             = 0x23
+	tagOctetString      = 0x30
+	tagBegCollection    = 0x34
+	tagTextWithLanguage = 0x35
+	tagNameWithLanguage = 0x36
+	tagEndCollection    = 0x37
+	tagText             = 0x41
+	tagKeyword          = 0x44
+	tagURI              = 0x45
+	tagCharset          = 0x47
+	tagNaturalLanguage  = 0x48
+	tagMimeMediaType    = 0x49
+	tagMemberName       = 0x4A
+	tagExtension        = 0x7F // The value is the four byte extended tag, RFC 8010 section 3.5.2
+)
+
+// Attributes are the attributes of a response by name. Values are int for
+// integers and enums, bool for booleans and string for everything else that
+// is textual. Out-of-band values such as "unknown" are left out. Members of
+// a collection are named after it, e.g. "printer-impressions-completed-col.monochrome";
+// collections nested in those are left out.
+type Attributes map[string][]interface{}
+
+// String returns the first value of an attribute as a string, "" if it has none
+func (a Attributes) String(name string) string {
+	for _, value := range a[name] {
+		if s, ok := value.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// Strings returns every string value of an attribute
+func (a Attributes) Strings(name string) []string {
+	var values []string
+	for _, value := range a[name] {
+		if s, ok := value.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// Int returns the first value of an integer or enum attribute
+func (a Attributes) Int(name string) (int, bool) {
+	for _, value := range a[name] {
+		if n, ok := value.(int); ok {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// Ints returns every integer value of an attribute
+func (a Attributes) Ints(name string) []int {
+	var values []int
+	for _, value := range a[name] {
+		if n, ok := value.(int); ok {
+			values = append(values, n)
+		}
+	}
+	return values
+}
+
+// encodeRequest builds a Get-Printer-Attributes request for the printer at uri
+func encodeRequest(requestID int32, uri string, requested []string) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{2, 0}) // IPP 2.0
+	binary.Write(&buf, binary.BigEndian, uint16(opGetPrinterAttributes))
+	binary.Write(&buf, binary.BigEndian, requestID)
+
+	buf.WriteByte(tagOperationGroup)
+	writeAttribute(&buf, tagCharset, "attributes-charset", "utf-8")
+	writeAttribute(&buf, tagNaturalLanguage, "attributes-natural-language", "en")
+	writeAttribute(&buf, tagURI, "printer-uri", uri)
+	for i, name := range requested {
+		// Additional values of a multi-valued attribute have an empty name
+		attribute := ""
+		if i == 0 {
+			attribute = "requested-attributes"
+		}
+		writeAttribute(&buf, tagKeyword, attribute, name)
+	}
+	buf.WriteByte(tagEndOfAttributes)
+	return buf.Bytes()
+}
+
+// writeAttribute writes a single attribute value
+func writeAttribute(buf *bytes.Buffer, tag byte, name, value string) {
+	buf.WriteByte(tag)
+	binary.Write(buf, binary.BigEndian, uint16(len(name)))
+	buf.WriteString(name)
+	binary.Write(buf, binary.BigEndian, uint16(len(value)))
+	buf.WriteString(value)
+}
+
+// response is a decoded IPP response
+type response struct {
+	status     uint16
+	requestID  int32
+	attributes Attributes // Operation and printer attributes together
+}
+
+// errTruncated is returned for responses that end in the middle of an attribute
+var errTruncated = errors.New("ipp: truncated response")
+
+// decodeResponse decodes an IPP response. Members of top level collections
+// are kept as "collection.member", e.g. "media-col.media-key"; members of
+// nested collections are skipped.
+func decodeResponse(data []byte) (*response, error) {
+	r := bytes.NewReader(data)
+	var header struct {
+		Version   [2]byte
+		Status    uint16
+		RequestID int32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, errTruncated
+	}
+	if header.Version[0] < 1 || header.Version[0] > 2 {
+		return nil, fmt.Errorf("ipp: unsupported version %d.%d", header.Version[0], header.Version[1])
+	}
+
+	resp := &response{status: header.Status, requestID: header.RequestID, attributes: Attributes{}}
+	var current string // Name of the attribute additional values belong to
+	var member string  // Name of the collection member values belong to
+	depth := 0         // Collection nesting
+	for {
+		tag, err := r.ReadByte()
+		if err != nil {
+			return nil, errTruncated
+		}
+		if tag == tagEndOfAttributes {
+			return resp, nil
+		}
+		if tag <= maxDelimiterTag {
+			current = "" // A new attribute group
+			continue
+		}
+		name, err := readField(r)
+		if err != nil {
+			return nil, err
+		}
+		value, err := readField(r)
+		if err != nil {
+			return nil, err
+		}
+
+		switch tag {
+		case tagBegCollection:
+			if depth == 0 {
+				if len(name) > 0 {
+					current = string(name)
+				}
+				member = ""
+			}
+			depth++
+			continue
+		case tagEndCollection:
+			if depth > 0 {
+				depth--
+			}
+			continue
+		}
+		if depth > 0 {
+			switch {
+			case depth > 1 || current == "":
+				// A member of a nested collection
+			case tag == tagMemberName:
+				member = string(value)
+			case member != "":
+				if v, ok := decodeValue(tag, value); ok {
+					key := current + "." + member
+					resp.attributes[key] = append(resp.attributes[key], v)
+				}
+			}
+			continue
+		}
+		if len(name) > 0 {
+			current = string(name)
+		}
+		if current == "" {
+			continue // A value without an attribute
+		}
+		if v, ok := decodeValue(tag, value); ok {
+			resp.attributes[current] = append(resp.attributes[current], v)
+		}
+	}
+}
+
+// readField reads a two byte length and that many bytes
+func readField(r *bytes.Reader) ([]byte, error) {
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, errTruncated
+	}
+	field := make([]byte, length)
+	if _, err := io.ReadFull(r, field); err != nil {
+		return nil, errTruncated
+	}
+	return field, nil
+}
+
+// decodeValue converts a value to int, bool or string. Out-of-band and binary
+// values the collector has no use for are dropped.
+func decodeValue(tag byte, value []byte) (interface{}, bool) {
+	switch {
+	case tag == tagInteger || tag == tagEnum:
+		if len(value) != 4 {
+			return nil, false
+		}
+		return int(int32(binary.BigEndian.Uint32(value))), true
+	case tag == tagBoolean:
+		if len(value) != 1 {
+			return nil, false
+		}
+		return value[0] != 0, true
+	case tag == tagOctetString || (tag >= tagText && tag <= tagMimeMediaType):
+		// octetString and the character string types
+		return string(value), true
+	case tag == tagTextWithLanguage || tag == tagNameWithLanguage:
+		// textWithLanguage and nameWithLanguage: language, then text
+		if len(value) < 2 {
+			return nil, false
+		}
+		n := int(binary.BigEndian.Uint16(value))
+		if 2+n+2 > len(value) {
+			return nil, false
+		}
+		return string(value[2+n+2:]), true
+	}
+	return nil, false
+}
@@ -0,0 +1,328 @@
+package ipp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"lynk/agent/internal/deviceid"
+	"lynk/agent/internal/snmp"
+)
+
+// printerStates maps printer-state values
+var printerStates = map[int]snmp.PrinterState{
+	3: snmp.PrinterIdle,
+	4: snmp.PrinterPrinting, // processing
+	5: snmp.PrinterStopped,
+}
+
+// inputTypes maps the type keywords of printer-input-tray to prtInputType values
+var inputTypes = map[string]int{
+	"other":                         1,
+	"unknown":                       2,
+	"sheetFeedAutoRemovableTray":    3,
+	"sheetFeedAutoNonRemovableTray": 4,
+	"sheetFeedManual":               5,
+	"continuousRoll":                6,
+	"continuousFanFold":             7,
+}
+
+// outputTypes maps the type keywords of printer-output-tray to prtOutputType values
+var outputTypes = map[string]int{
+	"other":                1,
+	"unknown":              2,
+	"removableBin":         3,
+	"unRemovableBin":       4,
+	"continuousRollDevice": 5,
+	"mailBox":              6,
+	"continuousFanFold":    7,
+}
+
+// coverNames names the covers printer-state-reasons report open
+var coverNames = map[string]string{
+	"door-open":      "Door",
+	"cover-open":     "Cover",
+	"interlock-open": "Interlock",
+}
+
+// dimensionUnits maps the dimunit keywords of trays to PrtMediaUnitTC values
+var dimensionUnits = map[string]int{
+	"tenThousandthsOfInches": 3,
+	"micrometers":            4,
+}
+
+// sources maps the status fields to the attributes they are read from, so a
+// merged status can tell which attribute a value came from
+var sources = map[string]string{
+	"device_id":          "printer-device-id",
+	"manufacturer":       "printer-device-id",
+	"serial_number":      "printer-device-id",
+	"command_set":        "printer-device-id",
+	"model":              "printer-make-and-model",
+	"uuid":               "printer-uuid",
+	"firmware_version":   "printer-firmware-string-version",
+	"printer_name":       "printer-name",
+	"location":           "printer-location",
+	"system_description": "printer-info",
+	"uptime":             "printer-up-time",
+	"status":             "printer-state",
+	"device_state":       "printer-state",
+	"active_alerts":      "printer-state-reasons",
+	"error_count":        "printer-state-reasons",
+	"paper_status":       "printer-state-reasons",
+	"covers":             "printer-state-reasons",
+	"last_error":         "printer-state-message",
+	"total_pages":        "printer-impressions-completed",
+	"color_pages":        "printer-impressions-completed-col.full-color",
+	"mono_pages":         "printer-impressions-completed-col.monochrome",
+	"supplies":           "marker-levels",
+	"toner_level":        "marker-levels",
+	"drum_level":         "marker-levels",
+	"drum_max_capacity":  "marker-levels",
+	"paper_trays":        "printer-input-tray",
+	"output_bins":        "printer-output-tray",
+}
+
+// Status maps the attributes of a Get-Printer-Attributes response into a
+// PrinterStatus, so the rest of the agent doesn't care which protocol was used
+func Status(host string, attrs Attributes, now time.Time) *snmp.PrinterStatus {
+	status := &snmp.PrinterStatus{
+		Host:     host,
+		Protocol: "ipp",
+		LastSeen: now,
+		Status:   "unknown",
+	}
+
+	// Identity
+	if raw := attrs.String("printer-device-id"); raw != "" {
+		status.DeviceID = raw
+		snmp.ApplyDeviceID(status, deviceid.Parse(raw))
+	}
+	if model := attrs.String("printer-make-and-model"); model != "" {
+		status.Model = model
+	}
+	status.UUID = attrs.String("printer-uuid")
+	status.FirmwareVersion = attrs.String("printer-firmware-string-version")
+	status.PrinterName = attrs.String("printer-name")
+	status.Location = attrs.String("printer-location")
+	status.SystemDescription = attrs.String("printer-info")
+	if upTime, ok := attrs.Int("printer-up-time"); ok && upTime > 0 {
+		status.Uptime = uint32(upTime) * 100 // Seconds to TimeTicks
+	}
+
+	// State
+	if state, ok := attrs.Int("printer-state"); ok {
+		if name, ok := printerStates[state]; ok {
+			status.Status = name
+		} else {
+			status.Status = snmp.PrinterOther
+		}
+	}
+	applyReasons(status, attrs.Strings("printer-state-reasons"))
+	if status.Status != snmp.PrinterUnknown {
+		status.DeviceState = deviceState(status)
+	}
+	if message := attrs.String("printer-state-message"); message != "" && status.LastError != "" {
+		status.LastError = message // The printer's own wording of the problem
+	}
+
+	// Counters and supplies
+	if pages, ok := attrs.Int("printer-impressions-completed"); ok {
+		status.TotalPages = pages
+	}
+	// PWG 5100.13 splits the count by color mode
+	if pages, ok := attrs.Int("printer-impressions-completed-col.full-color"); ok {
+		status.ColorPages = pages
+	}
+	if pages, ok := attrs.Int("printer-impressions-completed-col.monochrome"); ok {
+		status.MonoPages = pages
+	}
+	status.Supplies = supplies(attrs)
+	for _, supply := range status.Supplies {
+		switch supply.Type {
+		case 3, 21: // toner, tonerCartridge
+			if status.TonerLevel == 0 && supply.Percent >= 0 {
+				status.TonerLevel = supply.Percent
+			}
+		case 9: // opc
+			if supply.Level >= 0 {
+				status.DrumLevel = supply.Level
+				status.DrumMaxCapacity = supply.MaxCapacity
+			}
+		}
+	}
+
+	status.PaperTrays = trays(attrs.Strings("printer-input-tray"))
+	status.OutputBins = outputBins(attrs.Strings("printer-output-tray"))
+
+	for field, name := range sources {
+		if _, ok := attrs[name]; ok {
+			status.From(field, name)
+		}
+	}
+	return status
+}
+
+// deviceState derives what hrDeviceStatus would report from the printer state
+// and the reasons applyReasons kept
+func deviceState(status *snmp.PrinterStatus) snmp.DeviceState {
+	if status.Status == snmp.PrinterStopped {
+		return snmp.DeviceDown
+	}
+	for _, reason := range status.ActiveAlerts {
+		if strings.HasSuffix(reason, "-error") {
+			return snmp.DeviceDown
+		}
+	}
+	if len(status.ActiveAlerts) > 0 {
+		return snmp.DeviceWarning
+	}
+	return snmp.DeviceRunning
+}
+
+// applyReasons maps printer-state-reasons such as "media-empty-error" onto
+// the alert, paper status and cover fields
+func applyReasons(status *snmp.PrinterStatus, reasons []string) {
+	status.ActiveAlerts = []string{}
+	for _, reason := range reasons {
+		if reason == "none" || strings.HasSuffix(reason, "-report") {
+			continue
+		}
+		status.ActiveAlerts = append(status.ActiveAlerts, reason)
+
+		base := strings.TrimSuffix(strings.TrimSuffix(reason, "-error"), "-warning")
+		switch base {
+		case "media-empty", "media-needed":
+			status.PaperStatus = "paper_out"
+		case "media-jam":
+			status.PaperStatus = "paper_jam"
+		case "toner-low", "marker-supply-low":
+			if status.PaperStatus == "" {
+				status.PaperStatus = "toner_low"
+			}
+		case "door-open", "cover-open", "interlock-open":
+			status.Covers = append(status.Covers, snmp.Cover{
+				Index:       len(status.Covers) + 1,
+				Description: coverNames[base],
+				Status:      3, // coverOpen
+				State:       "open",
+				Open:        true,
+			})
+		}
+	}
+	status.ErrorCount = len(status.ActiveAlerts)
+	if len(status.ActiveAlerts) > 0 {
+		status.LastError = status.ActiveAlerts[0]
+	}
+}
+
+// supplies builds the supplies from the parallel marker-* attributes
+func supplies(attrs Attributes) []snmp.Supply {
+	names := attrs.Strings("marker-names")
+	levels := attrs.Ints("marker-levels")
+	colors := attrs.Strings("marker-colors")
+	types := attrs.Strings("marker-types")
+
+	result := []snmp.Supply{}
+	for i, name := range names {
+		supply := snmp.Supply{
+			Index:       "1." + strconv.Itoa(i+1),
+			Type:        2, // unknown
+			Description: name,
+			MaxCapacity: 100, // marker-levels are percentages
+			Level:       -2,
+			Percent:     -1,
+		}
+		if i < len(types) {
+			supply.Type = snmp.SupplyType(types[i])
+		}
+		if i < len(colors) {
+			supply.Color = colors[i]
+		}
+		if i < len(levels) {
+			supply.Level = levels[i]
+			if levels[i] >= 0 {
+				supply.Percent = levels[i]
+			}
+		}
+		result = append(result, supply)
+	}
+	return result
+}
+
+// keyValues parses a printer-input-tray or printer-output-tray value such as
+// "type=sheetFeedAutoRemovableTray;maxcapacity=250;level=100;status=0;name=Tray 1;"
+func keyValues(value string) map[string]string {
+	fields := make(map[string]string)
+	for _, field := range strings.Split(value, ";") {
+		key, value, ok := strings.Cut(field, "=")
+		if ok {
+			fields[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
+
+// number parses an integer field, returning fallback when it is missing or malformed
+func number(fields map[string]string, key string, fallback int) int {
+	n, err := strconv.Atoi(fields[key])
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+// trays maps printer-input-tray values to paper trays
+func trays(values []string) []snmp.PaperTray {
+	result := []snmp.PaperTray{}
+	for i, value := range values {
+		fields := keyValues(value)
+		tray := snmp.PaperTray{
+			Index:     i + 1,
+			Name:      fields["name"],
+			Type:      inputTypes[fields["type"]],
+			Status:    number(fields, "status", snmp.SubUnitUnknown),
+			Capacity:  number(fields, "maxcapacity", -2),
+			Level:     number(fields, "level", -2),
+			MediaName: fields["medianame"],
+			MediaSize: snmp.NewMediaSize(dimensionUnits[fields["dimunit"]],
+				number(fields, "mediaxfeed", -2), number(fields, "mediafeed", -2)),
+		}
+		if tray.Name == "" {
+			tray.Name = fmt.Sprintf("Tray %d", tray.Index)
+		}
+		tray.Decode()
+		result = append(result, tray)
+	}
+	return result
+}
+
+// outputBins maps printer-output-tray values to output bins
+func outputBins(values []string) []snmp.OutputBin {
+	result := []snmp.OutputBin{}
+	for i, value := range values {
+		fields := keyValues(value)
+		bin := snmp.OutputBin{
+			Index:         i + 1,
+			Name:          fields["name"],
+			Type:          outputTypes[fields["type"]],
+			Status:        number(fields, "status", snmp.SubUnitUnknown),
+			Capacity:      number(fields, "maxcapacity", -2),
+			Remaining:     number(fields, "remaining", -2),
+			StackingOrder: "unknown",
+		}
+		switch fields["stackingorder"] {
+		case "firstToLast":
+			bin.StackingOrder = "first_to_last"
+		case "lastToFirst":
+			bin.StackingOrder = "last_to_first"
+		}
+		if bin.Name == "" {
+			bin.Name = fmt.Sprintf("Output %d", bin.Index)
+		}
+		bin.Decode()
+		result = append(result, bin)
+	}
+	return result
+}
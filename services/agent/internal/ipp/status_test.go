@@ -0,0 +1,257 @@
+package ipp
+
+import (
+	"testing"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+func TestStatusPrinterState(t *testing.T) {
+	tests := []struct {
+		attrs       Attributes
+		state       snmp.PrinterState
+		deviceState snmp.DeviceState
+	}{
+		{Attributes{}, snmp.PrinterUnknown, ""},
+		{Attributes{"printer-state": {3}}, snmp.PrinterIdle, snmp.DeviceRunning},
+		{Attributes{"printer-state": {4}}, snmp.PrinterPrinting, snmp.DeviceRunning},
+		{Attributes{"printer-state": {5}}, snmp.PrinterStopped, snmp.DeviceDown},
+		{Attributes{"printer-state": {9}}, snmp.PrinterOther, snmp.DeviceRunning},
+		{Attributes{"printer-state": {3}, "printer-state-reasons": {"toner-low-warning"}}, snmp.PrinterIdle, snmp.DeviceWarning},
+		{Attributes{"printer-state": {4}, "printer-state-reasons": {"media-jam-error"}}, snmp.PrinterPrinting, snmp.DeviceDown},
+		{Attributes{"printer-state": {"idle"}}, snmp.PrinterUnknown, ""}, // Not an enum
+	}
+	for _, test := range tests {
+		status := Status("printer", test.attrs, time.Now())
+		if status.Status != test.state || status.DeviceState != test.deviceState {
+			t.Errorf("%v: state %s, device state %q, want %s, %q", test.attrs, status.Status, status.DeviceState, test.state, test.deviceState)
+		}
+	}
+}
+
+func TestStatusReasons(t *testing.T) {
+	tests := []struct {
+		reasons     []interface{}
+		alerts      int
+		paperStatus string
+		covers      int
+	}{
+		{[]interface{}{"none"}, 0, "", 0},
+		{[]interface{}{"media-empty-error"}, 1, "paper_out", 0},
+		{[]interface{}{"media-needed-warning"}, 1, "paper_out", 0},
+		{[]interface{}{"media-jam-error"}, 1, "paper_jam", 0},
+		{[]interface{}{"toner-low-warning", "media-jam-error"}, 2, "paper_jam", 0},
+		{[]interface{}{"media-jam-error", "toner-low-warning"}, 2, "paper_jam", 0},
+		{[]interface{}{"marker-supply-low-report"}, 0, "", 0}, // Reports are informational
+		{[]interface{}{"door-open", "interlock-open-error"}, 2, "", 2},
+	}
+	for _, test := range tests {
+		status := Status("printer", Attributes{"printer-state-reasons": test.reasons}, time.Now())
+		if len(status.ActiveAlerts) != test.alerts || status.ErrorCount != test.alerts {
+			t.Errorf("%v: %d alerts, error count %d, want %d", test.reasons, len(status.ActiveAlerts), status.ErrorCount, test.alerts)
+		}
+		if status.PaperStatus != test.paperStatus {
+			t.Errorf("%v: paper status %q, want %q", test.reasons, status.PaperStatus, test.paperStatus)
+		}
+		if len(status.Covers) != test.covers {
+			t.Errorf("%v: %d covers, want %d", test.reasons, len(status.Covers), test.covers)
+		}
+	}
+}
+
+func TestStatusMarkers(t *testing.T) {
+	// Fewer levels, colors and types than names: the rest stays unknown
+	status := Status("printer", Attributes{
+		"marker-names":  {"Cyan", "Magenta", "Waste"},
+		"marker-levels": {80, -3},
+		"marker-colors": {"#00FFFF"},
+		"marker-types":  {"toner", "toner", "waste-toner"},
+	}, time.Now())
+
+	want := []snmp.Supply{
+		{Index: "1.1", Type: 3, Description: "Cyan", MaxCapacity: 100, Level: 80, Percent: 80, Color: "#00FFFF"},
+		{Index: "1.2", Type: 3, Description: "Magenta", MaxCapacity: 100, Level: -3, Percent: -1},
+		{Index: "1.3", Type: 4, Description: "Waste", MaxCapacity: 100, Level: -2, Percent: -1},
+	}
+	if len(status.Supplies) != len(want) {
+		t.Fatalf("supplies = %+v", status.Supplies)
+	}
+	for i := range want {
+		if status.Supplies[i] != want[i] {
+			t.Errorf("supply %d = %+v, want %+v", i, status.Supplies[i], want[i])
+		}
+	}
+	if status.TonerLevel != 80 {
+		t.Errorf("toner level = %d, want 80", status.TonerLevel)
+	}
+}
+
+func TestStatusInputTray(t *testing.T) {
+	tests := []struct {
+		value     string
+		tray      snmp.PaperTray
+		condition string
+	}{
+		{
+			"type=sheetFeedAutoRemovableTray;mediafeed=297000;mediaxfeed=210000;dimunit=micrometers;maxcapacity=250;level=125;status=0;name=Tray 1;medianame=iso_a4_210x297mm;",
+			snmp.PaperTray{Index: 1, Name: "Tray 1", Type: 3, Capacity: 250, Level: 125, Percent: 50, MediaName: "iso_a4_210x297mm"},
+			"ok",
+		},
+		{
+			"type=sheetFeedManual;maxcapacity=1;level=-3;status=1;",
+			snmp.PaperTray{Index: 1, Name: "Tray 1", Type: 5, Status: 1, Capacity: 1, Level: -3, Percent: -1},
+			"ok", // on_request, e.g. a manual feed slot
+		},
+		{
+			"type=sheetFeedAutoRemovableTray;maxcapacity=500;level=0;status=19;name=Tray 2;",
+			snmp.PaperTray{Index: 1, Name: "Tray 2", Type: 3, Status: 19, Capacity: 500, Level: 0, Percent: 0},
+			"error", // broken with a critical alert
+		},
+		{
+			"garbage",
+			snmp.PaperTray{Index: 1, Name: "Tray 1", Status: snmp.SubUnitUnknown, Capacity: -2, Level: -2, Percent: -1},
+			"unknown",
+		},
+	}
+	for _, test := range tests {
+		got := trays([]string{test.value})
+		if len(got) != 1 {
+			t.Fatalf("%q: %d trays", test.value, len(got))
+		}
+		tray := got[0]
+		if tray.Name != test.tray.Name || tray.Type != test.tray.Type || tray.Status != test.tray.Status ||
+			tray.Capacity != test.tray.Capacity || tray.Level != test.tray.Level || tray.Percent != test.tray.Percent ||
+			tray.MediaName != test.tray.MediaName {
+			t.Errorf("%q: tray = %+v, want %+v", test.value, tray, test.tray)
+		}
+		if tray.Condition != test.condition {
+			t.Errorf("%q: condition = %q, want %q", test.value, tray.Condition, test.condition)
+		}
+	}
+
+	sized := trays([]string{tests[0].value})[0]
+	if sized.MediaSize == nil || sized.MediaSize.Width != 210 || sized.MediaSize.Length != 297 {
+		t.Errorf("media size = %+v, want 210 x 297 mm", sized.MediaSize)
+	}
+	letter := trays([]string{"mediafeed=110000;mediaxfeed=85000;dimunit=tenThousandthsOfInches;"})[0]
+	if letter.MediaSize == nil || letter.MediaSize.Name() != "Letter" {
+		t.Errorf("media size in inches = %+v, want Letter", letter.MediaSize)
+	}
+	if unsized := trays([]string{"mediafeed=297000;mediaxfeed=210000;"})[0]; unsized.MediaSize != nil {
+		t.Errorf("media size without a unit = %+v", unsized.MediaSize)
+	}
+}
+
+func TestDecodeResponseMalformedGroups(t *testing.T) {
+	header := []byte{2, 0, 0, 0, 0, 0, 0, 1}
+	field := func(tag byte, name, value string) []byte {
+		out := []byte{tag, byte(len(name) >> 8), byte(len(name))}
+		out = append(out, name...)
+		out = append(out, byte(len(value)>>8), byte(len(value)))
+		return append(out, value...)
+	}
+	join := func(parts ...[]byte) []byte {
+		var out []byte
+		for _, part := range parts {
+			out = append(out, part...)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want Attributes // nil for an error
+	}{
+		{
+			"value without an attribute name",
+			join(header, []byte{0x04}, field(tagKeyword, "", "orphan"), field(tagKeyword, "printer-name", "P"), []byte{tagEndOfAttributes}),
+			Attributes{"printer-name": {"P"}},
+		},
+		{
+			"additional values don't cross groups",
+			join(header, []byte{0x04}, field(tagKeyword, "a", "1"), []byte{0x05}, field(tagKeyword, "", "2"), []byte{tagEndOfAttributes}),
+			Attributes{"a": {"1"}},
+		},
+		{
+			"collection members are named after the collection",
+			join(header, []byte{0x04},
+				field(tagBegCollection, "printer-impressions-completed-col", ""),
+				field(tagMemberName, "", "monochrome"), field(tagInteger, "", "\x00\x00\x01\x00"),
+				field(tagMemberName, "", "full-color"), field(tagInteger, "", "\x00\x00\x00\x10"),
+				field(tagEndCollection, "", ""),
+				field(tagKeyword, "printer-name", "P"),
+				[]byte{tagEndOfAttributes}),
+			Attributes{"printer-impressions-completed-col.monochrome": {256}, "printer-impressions-completed-col.full-color": {16}, "printer-name": {"P"}},
+		},
+		{
+			"nested collection members are skipped",
+			join(header, []byte{0x04},
+				field(tagBegCollection, "media-col", ""),
+				field(tagMemberName, "", "media-size"), field(tagBegCollection, "", ""),
+				field(tagMemberName, "", "x-dimension"), field(tagInteger, "", "\x00\x00\x52\x08"),
+				field(tagEndCollection, "", ""),
+				field(tagMemberName, "", "media-key"), field(tagKeyword, "", "iso_a4_210x297mm"),
+				field(tagEndCollection, "", ""),
+				field(tagKeyword, "printer-name", "P"),
+				[]byte{tagEndOfAttributes}),
+			Attributes{"media-col.media-key": {"iso_a4_210x297mm"}, "printer-name": {"P"}},
+		},
+		{
+			"out-of-band and malformed values are dropped",
+			join(header, []byte{0x04},
+				field(0x12, "printer-location", ""),          // unknown
+				field(tagInteger, "printer-up-time", "\x01"), // wrong length
+				field(tagBoolean, "color-supported", "\x01"),
+				[]byte{tagEndOfAttributes}),
+			Attributes{"color-supported": {true}},
+		},
+		{
+			"name and language",
+			join(header, []byte{0x04}, field(tagNameWithLanguage, "printer-name", "\x00\x02en\x00\x01P"), []byte{tagEndOfAttributes}),
+			Attributes{"printer-name": {"P"}},
+		},
+		{
+			"extended tags are skipped like other unknown types",
+			join(header, []byte{0x04},
+				field(tagExtension, "printer-x-extended", "\x00\x00\x01\x00"),
+				field(tagExtension, "", "\x00\x00\x01\x01"),
+				field(tagKeyword, "printer-name", "P"),
+				[]byte{tagEndOfAttributes}),
+			Attributes{"printer-name": {"P"}},
+		},
+		{"no end tag", join(header, []byte{0x04}, field(tagKeyword, "a", "1")), nil},
+		{"name longer than the data", join(header, []byte{0x04, tagKeyword, 0x00, 0x10, 'a'}), nil},
+		{"short header", []byte{2, 0, 0}, nil},
+	}
+	for _, test := range tests {
+		resp, err := decodeResponse(test.data)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("%s: no error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if len(resp.attributes) != len(test.want) {
+			t.Errorf("%s: attributes = %v, want %v", test.name, resp.attributes, test.want)
+			continue
+		}
+		for name, values := range test.want {
+			got := resp.attributes[name]
+			if len(got) != len(values) {
+				t.Errorf("%s: %s = %v, want %v", test.name, name, got, values)
+				continue
+			}
+			for i := range values {
+				if got[i] != values[i] {
+					t.Errorf("%s: %s = %v, want %v", test.name, name, got, values)
+				}
+			}
+		}
+	}
+}
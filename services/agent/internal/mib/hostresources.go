@@ -0,0 +1,120 @@
+package mib
+
+// hrDeviceStatus is the values of hrDeviceStatus
+var hrDeviceStatus = Enum{1: "unknown", 2: "running", 3: "warning", 4: "testing", 5: "down"}
+
+// hrPrinterStatus is the values of hrPrinterStatus
+var hrPrinterStatus = Enum{1: "other", 2: "unknown", 3: "idle", 4: "printing", 5: "warmup"}
+
+// hostResourcesMIB is HOST-RESOURCES-MIB (RFC 2790)
+var hostResourcesMIB = []definition{
+	{"host", "1.3.6.1.2.1.25", nil},
+
+	{"hrSystem", "1.3.6.1.2.1.25.1", nil},
+	{"hrSystemUptime", "1.3.6.1.2.1.25.1.1", nil},
+	{"hrSystemDate", "1.3.6.1.2.1.25.1.2", nil},
+	{"hrSystemInitialLoadDevice", "1.3.6.1.2.1.25.1.3", nil},
+	{"hrSystemInitialLoadParameters", "1.3.6.1.2.1.25.1.4", nil},
+	{"hrSystemNumUsers", "1.3.6.1.2.1.25.1.5", nil},
+	{"hrSystemProcesses", "1.3.6.1.2.1.25.1.6", nil},
+	{"hrSystemMaxProcesses", "1.3.6.1.2.1.25.1.7", nil},
+
+	{"hrStorage", "1.3.6.1.2.1.25.2", nil},
+	{"hrStorageTypes", "1.3.6.1.2.1.25.2.1", nil},
+	{"hrMemorySize", "1.3.6.1.2.1.25.2.2", nil},
+	{"hrStorageTable", "1.3.6.1.2.1.25.2.3", nil},
+	{"hrStorageEntry", "1.3.6.1.2.1.25.2.3.1", nil},
+	{"hrStorageIndex", "1.3.6.1.2.1.25.2.3.1.1", nil},
+	{"hrStorageType", "1.3.6.1.2.1.25.2.3.1.2", nil},
+	{"hrStorageDescr", "1.3.6.1.2.1.25.2.3.1.3", nil},
+	{"hrStorageAllocationUnits", "1.3.6.1.2.1.25.2.3.1.4", nil},
+	{"hrStorageSize", "1.3.6.1.2.1.25.2.3.1.5", nil},
+	{"hrStorageUsed", "1.3.6.1.2.1.25.2.3.1.6", nil},
+	{"hrStorageAllocationFailures", "1.3.6.1.2.1.25.2.3.1.7", nil},
+
+	{"hrDevice", "1.3.6.1.2.1.25.3", nil},
+	{"hrDeviceTypes", "1.3.6.1.2.1.25.3.1", nil},
+	{"hrDeviceTable", "1.3.6.1.2.1.25.3.2", nil},
+	{"hrDeviceEntry", "1.3.6.1.2.1.25.3.2.1", nil},
+	{"hrDeviceIndex", "1.3.6.1.2.1.25.3.2.1.1", nil},
+	{"hrDeviceType", "1.3.6.1.2.1.25.3.2.1.2", nil},
+	{"hrDeviceDescr", "1.3.6.1.2.1.25.3.2.1.3", nil},
+	{"hrDeviceID", "1.3.6.1.2.1.25.3.2.1.4", nil},
+	{"hrDeviceStatus", "1.3.6.1.2.1.25.3.2.1.5", hrDeviceStatus},
+	{"hrDeviceErrors", "1.3.6.1.2.1.25.3.2.1.6", nil},
+	{"hrProcessorTable", "1.3.6.1.2.1.25.3.3", nil},
+	{"hrProcessorEntry", "1.3.6.1.2.1.25.3.3.1", nil},
+	{"hrProcessorFrwID", "1.3.6.1.2.1.25.3.3.1.1", nil},
+	{"hrProcessorLoad", "1.3.6.1.2.1.25.3.3.1.2", nil},
+	{"hrNetworkTable", "1.3.6.1.2.1.25.3.4", nil},
+	{"hrNetworkEntry", "1.3.6.1.2.1.25.3.4.1", nil},
+	{"hrNetworkIfIndex", "1.3.6.1.2.1.25.3.4.1.1", nil},
+	{"hrPrinterTable", "1.3.6.1.2.1.25.3.5", nil},
+	{"hrPrinterEntry", "1.3.6.1.2.1.25.3.5.1", nil},
+	{"hrPrinterStatus", "1.3.6.1.2.1.25.3.5.1.1", hrPrinterStatus},
+	{"hrPrinterDetectedErrorState", "1.3.6.1.2.1.25.3.5.1.2", nil}, // A BITS octet string
+	{"hrDiskStorageTable", "1.3.6.1.2.1.25.3.6", nil},
+	{"hrDiskStorageEntry", "1.3.6.1.2.1.25.3.6.1", nil},
+	{"hrDiskStorageAccess", "1.3.6.1.2.1.25.3.6.1.1", Enum{1: "readWrite", 2: "readOnly"}},
+	{"hrDiskStorageMedia", "1.3.6.1.2.1.25.3.6.1.2", Enum{
+		1: "other", 2: "unknown", 3: "hardDisk", 4: "floppyDisk",
+		5: "opticalDiskROM", 6: "opticalDiskWORM", 7: "opticalDiskRW", 8: "ramDisk",
+	}},
+	{"hrDiskStorageRemoveble", "1.3.6.1.2.1.25.3.6.1.3", Enum{1: "true", 2: "false"}},
+	{"hrDiskStorageCapacity", "1.3.6.1.2.1.25.3.6.1.4", nil},
+	{"hrPartitionTable", "1.3.6.1.2.1.25.3.7", nil},
+	{"hrPartitionEntry", "1.3.6.1.2.1.25.3.7.1", nil},
+	{"hrPartitionIndex", "1.3.6.1.2.1.25.3.7.1.1", nil},
+	{"hrPartitionLabel", "1.3.6.1.2.1.25.3.7.1.2", nil},
+	{"hrPartitionID", "1.3.6.1.2.1.25.3.7.1.3", nil},
+	{"hrPartitionSize", "1.3.6.1.2.1.25.3.7.1.4", nil},
+	{"hrPartitionFSIndex", "1.3.6.1.2.1.25.3.7.1.5", nil},
+	{"hrFSTable", "1.3.6.1.2.1.25.3.8", nil},
+	{"hrFSEntry", "1.3.6.1.2.1.25.3.8.1", nil},
+	{"hrFSIndex", "1.3.6.1.2.1.25.3.8.1.1", nil},
+	{"hrFSMountPoint", "1.3.6.1.2.1.25.3.8.1.2", nil},
+	{"hrFSRemoteMountPoint", "1.3.6.1.2.1.25.3.8.1.3", nil},
+	{"hrFSType", "1.3.6.1.2.1.25.3.8.1.4", nil},
+	{"hrFSAccess", "1.3.6.1.2.1.25.3.8.1.5", Enum{1: "readWrite", 2: "readOnly"}},
+	{"hrFSBootable", "1.3.6.1.2.1.25.3.8.1.6", Enum{1: "true", 2: "false"}},
+	{"hrFSStorageIndex", "1.3.6.1.2.1.25.3.8.1.7", nil},
+	{"hrFSTypes", "1.3.6.1.2.1.25.3.9", nil},
+
+	{"hrSWRun", "1.3.6.1.2.1.25.4", nil},
+	{"hrSWRunPerf", "1.3.6.1.2.1.25.5", nil},
+	{"hrSWInstalled", "1.3.6.1.2.1.25.6", nil},
+}
+
+// hostResourcesTypes is the storage and device types of HOST-RESOURCES-TYPES,
+// the values of hrStorageType and hrDeviceType
+var hostResourcesTypes = []definition{
+	{"hrStorageOther", "1.3.6.1.2.1.25.2.1.1", nil},
+	{"hrStorageRam", "1.3.6.1.2.1.25.2.1.2", nil},
+	{"hrStorageVirtualMemory", "1.3.6.1.2.1.25.2.1.3", nil},
+	{"hrStorageFixedDisk", "1.3.6.1.2.1.25.2.1.4", nil},
+	{"hrStorageRemovableDisk", "1.3.6.1.2.1.25.2.1.5", nil},
+	{"hrStorageFloppyDisk", "1.3.6.1.2.1.25.2.1.6", nil},
+	{"hrStorageCompactDisc", "1.3.6.1.2.1.25.2.1.7", nil},
+	{"hrStorageRamDisk", "1.3.6.1.2.1.25.2.1.8", nil},
+	{"hrStorageFlashMemory", "1.3.6.1.2.1.25.2.1.9", nil},
+	{"hrStorageNetworkDisk", "1.3.6.1.2.1.25.2.1.10", nil},
+
+	{"hrDeviceOther", "1.3.6.1.2.1.25.3.1.1", nil},
+	{"hrDeviceUnknown", "1.3.6.1.2.1.25.3.1.2", nil},
+	{"hrDeviceProcessor", "1.3.6.1.2.1.25.3.1.3", nil},
+	{"hrDeviceNetwork", "1.3.6.1.2.1.25.3.1.4", nil},
+	{"hrDevicePrinter", "1.3.6.1.2.1.25.3.1.5", nil},
+	{"hrDeviceDiskStorage", "1.3.6.1.2.1.25.3.1.6", nil},
+	{"hrDeviceVideo", "1.3.6.1.2.1.25.3.1.10", nil},
+	{"hrDeviceAudio", "1.3.6.1.2.1.25.3.1.11", nil},
+	{"hrDeviceCoprocessor", "1.3.6.1.2.1.25.3.1.12", nil},
+	{"hrDeviceKeyboard", "1.3.6.1.2.1.25.3.1.13", nil},
+	{"hrDeviceModem", "1.3.6.1.2.1.25.3.1.14", nil},
+	{"hrDeviceParallelPort", "1.3.6.1.2.1.25.3.1.15", nil},
+	{"hrDevicePointing", "1.3.6.1.2.1.25.3.1.16", nil},
+	{"hrDeviceSerialPort", "1.3.6.1.2.1.25.3.1.17", nil},
+	{"hrDeviceTape", "1.3.6.1.2.1.25.3.1.18", nil},
+	{"hrDeviceClock", "1.3.6.1.2.1.25.3.1.19", nil},
+	{"hrDeviceVolatileMemory", "1.3.6.1.2.1.25.3.1.20", nil},
+	{"hrDeviceNonVolatileMemory", "1.3.6.1.2.1.25.3.1.21", nil},
+}
@@ -0,0 +1,203 @@
+package mib
+
+// Textual conventions of IANA-PRINTER-MIB and Printer-MIB (RFC 3805), the
+// enumerations Printer-MIB objects take their values from
+
+// PrtAlertCodeTC is the values of prtAlertCode
+var PrtAlertCodeTC = Enum{
+	1: "other", 2: "unknown",
+	3: "coverOpen", 4: "coverClosed", 5: "interlockOpen", 6: "interlockClosed",
+	7: "configurationChange", 8: "jam",
+	9: "subunitMissing", 10: "subunitLifeAlmostOver", 11: "subunitLifeOver",
+	12: "subunitAlmostEmpty", 13: "subunitEmpty", 14: "subunitAlmostFull", 15: "subunitFull",
+	16: "subunitNearLimit", 17: "subunitAtLimit", 18: "subunitOpened", 19: "subunitClosed",
+	20: "subunitTurnedOn", 21: "subunitTurnedOff", 22: "subunitOffline",
+	23: "subunitPowerSaver", 24: "subunitWarmingUp", 25: "subunitAdded", 26: "subunitRemoved",
+	27: "subunitResourceAdded", 28: "subunitResourceRemoved",
+	29: "subunitRecoverableFailure", 30: "subunitUnrecoverableFailure",
+	31: "subunitRecoverableStorageError", 32: "subunitUnrecoverableStorageError",
+	33: "subunitMotorFailure", 34: "subunitMemoryExhausted",
+	35: "subunitUnderTemperature", 36: "subunitOverTemperature",
+	37: "subunitTimingFailure", 38: "subunitThermistorFailure",
+
+	501: "doorOpen", 502: "doorClosed", 503: "poweredUp", 504: "poweredDown",
+	505: "printerNMSReset", 506: "printerManualReset", 507: "printerReadyToPrint",
+
+	801: "inputMediaTrayMissing", 802: "inputMediaSizeChange", 803: "inputMediaWeightChange",
+	804: "inputMediaTypeChange", 805: "inputMediaColorChange", 806: "inputMediaFormPartsChange",
+	807: "inputMediaSupplyLow", 808: "inputMediaSupplyEmpty",
+	809: "inputManualInputRequest", 810: "inputManualInputComplete",
+
+	901: "outputMediaTrayMissing", 902: "outputMediaTrayAlmostFull", 903: "outputMediaTrayFull",
+
+	1001: "markerFuserUnderTemperature", 1002: "markerFuserOverTemperature",
+	1003: "markerFuserTimingFailure", 1004: "markerFuserThermistorFailure",
+	1005: "markerAdjustingPrintQuality",
+
+	1101: "markerTonerEmpty", 1102: "markerInkEmpty", 1103: "markerPrintRibbonEmpty",
+	1104: "markerTonerAlmostEmpty", 1105: "markerInkAlmostEmpty", 1106: "markerPrintRibbonAlmostEmpty",
+	1107: "markerWasteTonerReceptacleAlmostFull", 1108: "markerWasteInkReceptacleAlmostFull",
+	1109: "markerWasteTonerReceptacleFull", 1110: "markerWasteInkReceptacleFull",
+	1111: "markerOpcLifeAlmostOver", 1112: "markerOpcLifeOver",
+	1113: "markerDeveloperAlmostEmpty", 1114: "markerDeveloperEmpty",
+	1115: "markerTonerCartridgeMissing",
+
+	1301: "mediaPathMediaTrayMissing", 1302: "mediaPathMediaTrayAlmostFull",
+	1303: "mediaPathMediaTrayFull", 1304: "mediaPathCannotDuplexMediaSelected",
+
+	1501: "interpreterMemoryIncrease", 1502: "interpreterMemoryDecrease",
+	1503: "interpreterCartridgeAdded", 1504: "interpreterCartridgeDeleted",
+	1505: "interpreterResourceAdded", 1506: "interpreterResourceDeleted",
+	1507: "interpreterResourceUnavailable", 1509: "interpreterComplexPageEncountered",
+
+	1801: "alertRemovalOfBinaryChangeEntry",
+}
+
+// PrtAlertGroupTC is the values of prtAlertGroup, the table an alert is about
+var PrtAlertGroupTC = Enum{
+	1: "other", 3: "hostResourcesMIBStorageTable", 4: "hostResourcesMIBDeviceTable",
+	5: "generalPrinter", 6: "cover", 7: "localization", 8: "input", 9: "output",
+	10: "marker", 11: "markerSupplies", 12: "markerColorant", 13: "mediaPath",
+	14: "channel", 15: "interpreter", 16: "consoleDisplayBuffer", 17: "consoleLights",
+	18: "alert", 30: "finDevice", 31: "finSupply", 32: "finSupplyMediaInput", 33: "finAttribute",
+}
+
+// PrtAlertTrainingLevelTC is the values of prtAlertTrainingLevel
+var PrtAlertTrainingLevelTC = Enum{
+	1: "other", 2: "unknown", 3: "untrained", 4: "trained",
+	5: "fieldService", 6: "management", 7: "noInterventionRequired",
+}
+
+// PrtMarkerSuppliesTypeTC is the values of prtMarkerSuppliesType
+var PrtMarkerSuppliesTypeTC = Enum{
+	1: "other", 2: "unknown", 3: "toner", 4: "wasteToner", 5: "ink", 6: "inkCartridge",
+	7: "inkRibbon", 8: "wasteInk", 9: "opc", 10: "developer", 11: "fuserOil",
+	12: "solidWax", 13: "ribbonWax", 14: "wasteWax", 15: "fuser", 16: "coronaWire",
+	17: "fuserOilWick", 18: "cleanerUnit", 19: "fuserCleaningPad", 20: "transferUnit",
+	21: "tonerCartridge", 22: "fuserOiler", 23: "water", 24: "wasteWater",
+	25: "glueWaterAdditive", 26: "wastePaper", 27: "bindingSupply", 28: "bandingSupply",
+	29: "stitchingWire", 30: "shrinkWrap", 31: "paperWrap", 32: "staples", 33: "inserts",
+	34: "covers",
+}
+
+// PrtMarkerSuppliesSupplyUnitTC is the values of prtMarkerSuppliesSupplyUnit
+var PrtMarkerSuppliesSupplyUnitTC = Enum{
+	1: "other", 2: "unknown", 3: "tenThousandthsOfInches", 4: "micrometers",
+	7: "impressions", 8: "sheets", 11: "hours", 12: "thousandthsOfOunces",
+	13: "tenthsOfGrams", 14: "hundrethsOfFluidOunces", 15: "tenthsOfMilliliters",
+	16: "feet", 17: "meters", 18: "items", 19: "percent",
+}
+
+// PrtMarkerCounterUnitTC is the values of prtMarkerCounterUnit
+var PrtMarkerCounterUnitTC = Enum{
+	3: "tenThousandthsOfInches", 4: "micrometers", 5: "characters", 6: "lines",
+	7: "impressions", 8: "sheets", 9: "dotRow", 11: "hours", 16: "feet", 17: "meters",
+}
+
+// PrtMarkerMarkTechTC is the values of prtMarkerMarkTech
+var PrtMarkerMarkTechTC = Enum{
+	1: "other", 2: "unknown", 3: "electrophotographicLED", 4: "electrophotographicLaser",
+	5: "electrophotographicOther", 6: "impactMovingHeadDotMatrix9pin",
+	7: "impactMovingHeadDotMatrix24pin", 8: "impactMovingHeadDotMatrixOther",
+	9: "impactMovingHeadFullyFormed", 10: "impactBand", 11: "impactOther",
+	12: "inkjetAqueous", 13: "inkjetSolid", 14: "inkjetOther", 15: "pen",
+	16: "thermalTransfer", 17: "thermalSensitive", 18: "thermalDiffusion", 19: "thermalOther",
+	20: "electroerosion", 21: "electrostatic", 22: "photographicMicrofiche",
+	23: "photographicImagesetter", 24: "photographicOther", 25: "ionDeposition",
+	26: "eBeam", 27: "typesetter",
+}
+
+// PrtInputTypeTC is the values of prtInputType
+var PrtInputTypeTC = Enum{
+	1: "other", 2: "unknown", 3: "sheetFeedAutoRemovableTray", 4: "sheetFeedAutoNonRemovableTray",
+	5: "sheetFeedManual", 6: "continuousRoll", 7: "continuousFanFold",
+}
+
+// PrtOutputTypeTC is the values of prtOutputType
+var PrtOutputTypeTC = Enum{
+	1: "other", 2: "unknown", 3: "removableBin", 4: "unRemovableBin",
+	5: "continuousRollDevice", 6: "mailBox", 7: "continuousFanFold",
+}
+
+// PrtCapacityUnitTC is the values of prtInputCapacityUnit and prtOutputCapacityUnit
+var PrtCapacityUnitTC = Enum{
+	1: "other", 2: "unknown", 3: "tenThousandthsOfInches", 4: "micrometers",
+	8: "sheets", 16: "feet", 17: "meters", 18: "items", 19: "percent",
+}
+
+// PrtMediaUnitTC is the values of prtInputDimUnit and prtMediaPathMediaSizeUnit
+var PrtMediaUnitTC = Enum{3: "tenThousandthsOfInches", 4: "micrometers"}
+
+// PrtCoverStatusTC is the values of prtCoverStatus
+var PrtCoverStatusTC = Enum{
+	1: "other", 3: "coverOpen", 4: "coverClosed", 5: "interlockOpen", 6: "interlockClosed",
+}
+
+// PrtConsoleColorTC is the values of prtConsoleColor
+var PrtConsoleColorTC = Enum{
+	1: "other", 2: "unknown", 3: "white", 4: "red", 5: "green",
+	6: "blue", 7: "cyan", 8: "magenta", 9: "yellow", 10: "orange",
+}
+
+// PrtMediaPathTypeTC is the values of prtMediaPathType
+var PrtMediaPathTypeTC = Enum{
+	1: "other", 2: "unknown", 3: "longEdgeBindingDuplex", 4: "shortEdgeBindingDuplex", 5: "simplex",
+}
+
+// PrtMediaPathMaxSpeedPrintUnitTC is the values of prtMediaPathMaxSpeedPrintUnit
+var PrtMediaPathMaxSpeedPrintUnitTC = Enum{
+	3: "tenThousandthsOfInchesPerHour", 4: "micrometersPerHour", 5: "charactersPerHour",
+	6: "linesPerHour", 7: "impressionsPerHour", 8: "sheetsPerHour", 9: "dotRowPerHour",
+	16: "feetPerHour", 17: "metersPerHour",
+}
+
+// PrtInterpreterLangFamilyTC is the values of prtInterpreterLangFamily
+var PrtInterpreterLangFamilyTC = Enum{
+	1: "other", 2: "unknown", 3: "langPCL", 4: "langHPGL", 5: "langPJL", 6: "langPS",
+	7: "langIPDS", 8: "langPPDS", 9: "langEscapeP", 10: "langEpson", 11: "langDDIF",
+	12: "langInterpress", 13: "langISO6429", 14: "langLineData", 15: "langMODCA",
+	16: "langREGIS", 17: "langSCS", 18: "langSPDL", 19: "langTEK4014", 20: "langPDS",
+	21: "langIGP", 22: "langCodeV", 23: "langDSCDSE", 24: "langWPS", 25: "langLN03",
+	26: "langCCITT", 27: "langQUIC", 28: "langCPAP", 29: "langDecPPL", 30: "langSimpleText",
+	31: "langNPAP", 32: "langDOC", 33: "langimPress", 34: "langPinwriter", 35: "langNPDL",
+	36: "langNEC201PL", 37: "langAutomatic", 38: "langPages", 39: "langLIPS", 40: "langTIFF",
+	41: "langDiagnostic", 42: "langPSPrinter", 43: "langCaPSL", 44: "langEXCL", 45: "langLCDS",
+	46: "langXES", 47: "langPCLXL", 48: "langART", 49: "langTIPSI", 50: "langPrescribe",
+	51: "langLinePrinter", 52: "langIDP", 53: "langXJCL", 54: "langPDF", 55: "langRPDL",
+	56: "langIntermecIPL", 57: "langUBIFingerprint", 58: "langUBIDirectProtocol",
+	59: "langFujitsu", 60: "langCGM", 61: "langJPEG", 62: "langCALS1", 63: "langCALS2",
+	64: "langNIRS", 65: "langC4",
+}
+
+// PrtOutputStackingOrderTC is the values of prtOutputStackingOrder
+var PrtOutputStackingOrderTC = Enum{2: "unknown", 3: "firstToLast", 4: "lastToFirst"}
+
+// PrtOutputPageDeliveryOrientationTC is the values of prtOutputPageDeliveryOrientation
+var PrtOutputPageDeliveryOrientationTC = Enum{3: "faceUp", 4: "faceDown"}
+
+// PresentOnOff is the values of the Printer-MIB's on/off switches
+var PresentOnOff = Enum{1: "other", 3: "on", 4: "off", 5: "notPresent"}
+
+// textualConventions are the enumerations above by name, for MIB files that
+// import them
+var textualConventions = map[string]Enum{
+	"PrtAlertCodeTC":                     PrtAlertCodeTC,
+	"PrtAlertGroupTC":                    PrtAlertGroupTC,
+	"PrtAlertTrainingLevelTC":            PrtAlertTrainingLevelTC,
+	"PrtMarkerSuppliesTypeTC":            PrtMarkerSuppliesTypeTC,
+	"PrtMarkerSuppliesSupplyUnitTC":      PrtMarkerSuppliesSupplyUnitTC,
+	"PrtMarkerCounterUnitTC":             PrtMarkerCounterUnitTC,
+	"PrtMarkerMarkTechTC":                PrtMarkerMarkTechTC,
+	"PrtInputTypeTC":                     PrtInputTypeTC,
+	"PrtOutputTypeTC":                    PrtOutputTypeTC,
+	"PrtCapacityUnitTC":                  PrtCapacityUnitTC,
+	"PrtMediaUnitTC":                     PrtMediaUnitTC,
+	"PrtCoverStatusTC":                   PrtCoverStatusTC,
+	"PrtConsoleColorTC":                  PrtConsoleColorTC,
+	"PrtMediaPathTypeTC":                 PrtMediaPathTypeTC,
+	"PrtMediaPathMaxSpeedPrintUnitTC":    PrtMediaPathMaxSpeedPrintUnitTC,
+	"PrtInterpreterLangFamilyTC":         PrtInterpreterLangFamilyTC,
+	"PrtOutputStackingOrderTC":           PrtOutputStackingOrderTC,
+	"PrtOutputPageDeliveryOrientationTC": PrtOutputPageDeliveryOrientationTC,
+	"PresentOnOff":                       PresentOnOff,
+}
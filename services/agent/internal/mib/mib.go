@@ -0,0 +1,260 @@
+// Package mib names the SNMP objects the agent reads. It translates between
+// OIDs and names such as "prtMarkerSuppliesLevel.1.1" and decodes enumerated
+// values, from a built-in table of SNMPv2-MIB, HOST-RESOURCES-MIB,
+// Printer-MIB, IANA-PRINTER-MIB and vendor objects that MIB files can extend.
+package mib
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Enum maps the values of an enumerated INTEGER to their labels
+type Enum map[int]string
+
+// Value returns the label of value, "" if the enum has none
+func (e Enum) Value(value int) string {
+	return e[value]
+}
+
+// Label returns the value a label stands for
+func (e Enum) Label(label string) (int, bool) {
+	for value, name := range e {
+		if name == label {
+			return value, true
+		}
+	}
+	return 0, false
+}
+
+// Object is a named node of the OID tree
+type Object struct {
+	Name   string
+	Module string // Defining MIB module, e.g. Printer-MIB
+	OID    string
+	Enum   Enum // Values of an enumerated INTEGER, nil for other syntaxes
+}
+
+// Registry translates between names and OIDs. It is safe for concurrent use.
+type Registry struct {
+	mu          sync.RWMutex
+	byName      map[string]*Object
+	byOID       map[string]*Object
+	conventions map[string]Enum // Enumerated textual conventions by name, for loading MIB files
+}
+
+// New returns an empty registry
+func New() *Registry {
+	return &Registry{
+		byName:      make(map[string]*Object),
+		byOID:       make(map[string]*Object),
+		conventions: make(map[string]Enum),
+	}
+}
+
+// Add registers an object. Adding a name again with the same OID updates it,
+// with a different OID is an error.
+func (r *Registry) Add(object Object) error {
+	object.OID = strings.TrimPrefix(object.OID, ".")
+	if !numeric(object.OID) {
+		return fmt.Errorf("mib: %s has an invalid OID %q", object.Name, object.OID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.byName[object.Name]; ok {
+		if existing.OID != object.OID {
+			return fmt.Errorf("mib: %s is already defined as %s by %s", object.Name, existing.OID, existing.Module)
+		}
+		if object.Enum == nil {
+			object.Enum = existing.Enum
+		}
+	}
+	r.byName[object.Name] = &object
+	r.byOID[object.OID] = &object
+	return nil
+}
+
+// Lookup returns the object with the given name, optionally qualified by its
+// module as in "Printer-MIB::prtAlertCode"
+func (r *Registry) Lookup(name string) (Object, bool) {
+	module, name, qualified := strings.Cut(name, "::")
+	if !qualified {
+		name = module
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	object, ok := r.byName[name]
+	if !ok || (qualified && object.Module != module) {
+		return Object{}, false
+	}
+	return *object, true
+}
+
+// Find returns the most specific object an OID lies under, and the rest of
+// the OID as its index, e.g. prtMarkerSuppliesLevel and "1.1"
+func (r *Registry) Find(oid string) (Object, string, bool) {
+	oid = strings.TrimPrefix(oid, ".")
+	if !numeric(oid) {
+		return Object{}, "", false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for prefix := oid; prefix != ""; {
+		if object, ok := r.byOID[prefix]; ok {
+			return *object, strings.TrimPrefix(strings.TrimPrefix(oid, prefix), "."), true
+		}
+		cut := strings.LastIndexByte(prefix, '.')
+		if cut < 0 {
+			break
+		}
+		prefix = prefix[:cut]
+	}
+	return Object{}, "", false
+}
+
+// Resolve translates a name such as "sysName.0" or "SNMPv2-MIB::sysName.0"
+// to its OID. Numeric OIDs are returned as they are, without a leading dot.
+func (r *Registry) Resolve(name string) (string, error) {
+	name = strings.TrimPrefix(name, ".")
+	if numeric(name) {
+		return name, nil
+	}
+
+	// Module names contain dashes, not dots, so the index starts at the first dot
+	symbol, index, _ := strings.Cut(name, ".")
+	object, ok := r.Lookup(symbol)
+	if !ok {
+		return "", fmt.Errorf("mib: unknown object %q", symbol)
+	}
+	if index == "" {
+		return object.OID, nil
+	}
+	if !numeric(index) {
+		return "", fmt.Errorf("mib: invalid index %q of %s", index, object.Name)
+	}
+	return object.OID + "." + index, nil
+}
+
+// MustResolve is like Resolve but panics if the name is unknown. It is meant
+// for the built-in names the collectors use.
+func (r *Registry) MustResolve(name string) string {
+	oid, err := r.Resolve(name)
+	if err != nil {
+		panic(err)
+	}
+	return oid
+}
+
+// Name translates an OID to the name of the object it lies under followed by
+// the index, e.g. "prtMarkerSuppliesLevel.1.1". It is "" when no object is known.
+func (r *Registry) Name(oid string) string {
+	object, index, ok := r.Find(oid)
+	if !ok {
+		return ""
+	}
+	if index == "" {
+		return object.Name
+	}
+	return object.Name + "." + index
+}
+
+// Decode returns the label of an enumerated value read from oid, e.g.
+// "critical" for prtAlertSeverityLevel.1.5 = 3. It is "" when the object
+// isn't an enumeration or the value isn't one of its labels.
+func (r *Registry) Decode(oid string, value int) string {
+	object, _, ok := r.Find(oid)
+	if !ok {
+		return ""
+	}
+	return object.Enum.Value(value)
+}
+
+// Objects returns every registered object, ordered by OID
+func (r *Registry) Objects() []Object {
+	r.mu.RLock()
+	objects := make([]Object, 0, len(r.byOID))
+	for _, object := range r.byOID {
+		objects = append(objects, *object)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(objects, func(i, j int) bool { return oidLess(objects[i].OID, objects[j].OID) })
+	return objects
+}
+
+// numeric reports whether s is a dotted list of numbers such as "1.3.6.1"
+func numeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, arc := range strings.Split(s, ".") {
+		if _, err := strconv.ParseUint(arc, 10, 32); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// oidLess orders OIDs arc by arc
+func oidLess(a, b string) bool {
+	aArcs, bArcs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aArcs) && i < len(bArcs); i++ {
+		if aArcs[i] != bArcs[i] {
+			x, _ := strconv.ParseUint(aArcs[i], 10, 32)
+			y, _ := strconv.ParseUint(bArcs[i], 10, 32)
+			return x < y
+		}
+	}
+	return len(aArcs) < len(bArcs)
+}
+
+// Default is the registry of the built-in MIB modules
+var Default = builtin()
+
+// builtin returns a registry of the modules compiled into the agent
+func builtin() *Registry {
+	r := New()
+	for name, enum := range textualConventions {
+		r.conventions[name] = enum
+	}
+	for _, module := range modules {
+		for _, def := range module.objects {
+			if err := r.Add(Object{Name: def.name, Module: module.name, OID: def.oid, Enum: def.enum}); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return r
+}
+
+// Resolve translates a name to its OID using the Default registry
+func Resolve(name string) (string, error) {
+	return Default.Resolve(name)
+}
+
+// MustResolve translates a built-in name to its OID using the Default
+// registry, panicking if it is unknown
+func MustResolve(name string) string {
+	return Default.MustResolve(name)
+}
+
+// Name translates an OID to its name using the Default registry
+func Name(oid string) string {
+	return Default.Name(oid)
+}
+
+// Decode labels an enumerated value using the Default registry
+func Decode(oid string, value int) string {
+	return Default.Decode(oid, value)
+}
+
+// LoadFile adds the objects of a MIB file to the Default registry
+func LoadFile(path string) error {
+	return Default.LoadFile(path)
+}
@@ -0,0 +1,112 @@
+package mib
+
+// module is a built-in MIB module
+type module struct {
+	name    string
+	objects []definition
+}
+
+// definition is an object of a built-in module
+type definition struct {
+	name string
+	oid  string
+	enum Enum
+}
+
+// modules are the built-in modules, parents before the modules under them
+var modules = []module{
+	{"SNMPv2-SMI", snmpv2SMI},
+	{"SNMPv2-MIB", snmpv2MIB},
+	{"IF-MIB", ifMIB},
+	{"IP-MIB", ipMIB},
+	{"HOST-RESOURCES-MIB", hostResourcesMIB},
+	{"HOST-RESOURCES-TYPES", hostResourcesTypes},
+	{"Printer-MIB", printerMIB},
+	{"PRINTER-PORT-MONITOR-MIB", portMonitorMIB},
+	{"vendor", vendorObjects},
+}
+
+// snmpv2SMI is the top of the OID tree (RFC 2578)
+var snmpv2SMI = []definition{
+	{"iso", "1", nil},
+	{"org", "1.3", nil},
+	{"dod", "1.3.6", nil},
+	{"internet", "1.3.6.1", nil},
+	{"directory", "1.3.6.1.1", nil},
+	{"mgmt", "1.3.6.1.2", nil},
+	{"mib-2", "1.3.6.1.2.1", nil},
+	{"transmission", "1.3.6.1.2.1.10", nil},
+	{"experimental", "1.3.6.1.3", nil},
+	{"private", "1.3.6.1.4", nil},
+	{"enterprises", "1.3.6.1.4.1", nil},
+	{"security", "1.3.6.1.5", nil},
+	{"snmpV2", "1.3.6.1.6", nil},
+	{"snmpDomains", "1.3.6.1.6.1", nil},
+	{"snmpProxys", "1.3.6.1.6.2", nil},
+	{"snmpModules", "1.3.6.1.6.3", nil},
+	{"zeroDotZero", "0.0", nil},
+}
+
+// snmpv2MIB is the system group (RFC 3418)
+var snmpv2MIB = []definition{
+	{"system", "1.3.6.1.2.1.1", nil},
+	{"sysDescr", "1.3.6.1.2.1.1.1", nil},
+	{"sysObjectID", "1.3.6.1.2.1.1.2", nil},
+	{"sysUpTime", "1.3.6.1.2.1.1.3", nil},
+	{"sysContact", "1.3.6.1.2.1.1.4", nil},
+	{"sysName", "1.3.6.1.2.1.1.5", nil},
+	{"sysLocation", "1.3.6.1.2.1.1.6", nil},
+	{"sysServices", "1.3.6.1.2.1.1.7", nil},
+	{"sysORLastChange", "1.3.6.1.2.1.1.8", nil},
+	{"sysORTable", "1.3.6.1.2.1.1.9", nil},
+	{"sysOREntry", "1.3.6.1.2.1.1.9.1", nil},
+	{"sysORIndex", "1.3.6.1.2.1.1.9.1.1", nil},
+	{"sysORID", "1.3.6.1.2.1.1.9.1.2", nil},
+	{"sysORDescr", "1.3.6.1.2.1.1.9.1.3", nil},
+	{"sysORUpTime", "1.3.6.1.2.1.1.9.1.4", nil},
+	{"snmp", "1.3.6.1.2.1.11", nil},
+	{"snmpInPkts", "1.3.6.1.2.1.11.1", nil},
+	{"snmpInBadCommunityNames", "1.3.6.1.2.1.11.4", nil},
+	{"snmpEnableAuthenTraps", "1.3.6.1.2.1.11.30", Enum{1: "enabled", 2: "disabled"}},
+}
+
+// ifStatus is the values of ifAdminStatus
+var ifStatus = Enum{1: "up", 2: "down", 3: "testing"}
+
+// ifMIB is the interfaces table (RFC 2863) the network collector walks
+var ifMIB = []definition{
+	{"interfaces", "1.3.6.1.2.1.2", nil},
+	{"ifNumber", "1.3.6.1.2.1.2.1", nil},
+	{"ifTable", "1.3.6.1.2.1.2.2", nil},
+	{"ifEntry", "1.3.6.1.2.1.2.2.1", nil},
+	{"ifIndex", "1.3.6.1.2.1.2.2.1.1", nil},
+	{"ifDescr", "1.3.6.1.2.1.2.2.1.2", nil},
+	{"ifType", "1.3.6.1.2.1.2.2.1.3", Enum{
+		1: "other", 6: "ethernetCsmacd", 23: "ppp", 24: "softwareLoopback",
+		53: "propVirtual", 71: "ieee80211", 131: "tunnel", 135: "l2vlan", 161: "ieee8023adLag",
+	}},
+	{"ifMtu", "1.3.6.1.2.1.2.2.1.4", nil},
+	{"ifSpeed", "1.3.6.1.2.1.2.2.1.5", nil},
+	{"ifPhysAddress", "1.3.6.1.2.1.2.2.1.6", nil},
+	{"ifAdminStatus", "1.3.6.1.2.1.2.2.1.7", ifStatus},
+	{"ifOperStatus", "1.3.6.1.2.1.2.2.1.8", Enum{
+		1: "up", 2: "down", 3: "testing", 4: "unknown", 5: "dormant", 6: "notPresent", 7: "lowerLayerDown",
+	}},
+	{"ifLastChange", "1.3.6.1.2.1.2.2.1.9", nil},
+	{"ifInOctets", "1.3.6.1.2.1.2.2.1.10", nil},
+	{"ifInErrors", "1.3.6.1.2.1.2.2.1.14", nil},
+	{"ifOutOctets", "1.3.6.1.2.1.2.2.1.16", nil},
+	{"ifOutErrors", "1.3.6.1.2.1.2.2.1.20", nil},
+}
+
+// ipMIB is the IPv4 address table (RFC 4293) the network collector walks
+var ipMIB = []definition{
+	{"ip", "1.3.6.1.2.1.4", nil},
+	{"ipAddrTable", "1.3.6.1.2.1.4.20", nil},
+	{"ipAddrEntry", "1.3.6.1.2.1.4.20.1", nil},
+	{"ipAdEntAddr", "1.3.6.1.2.1.4.20.1.1", nil},
+	{"ipAdEntIfIndex", "1.3.6.1.2.1.4.20.1.2", nil},
+	{"ipAdEntNetMask", "1.3.6.1.2.1.4.20.1.3", nil},
+	{"ipAdEntBcastAddr", "1.3.6.1.2.1.4.20.1.4", nil},
+	{"ipAdEntReasmMaxSize", "1.3.6.1.2.1.4.20.1.5", nil},
+}
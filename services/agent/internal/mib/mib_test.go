@@ -0,0 +1,119 @@
+package mib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"sysName.0", "1.3.6.1.2.1.1.5.0"},
+		{"SNMPv2-MIB::sysName.0", "1.3.6.1.2.1.1.5.0"},
+		{"prtMarkerSuppliesLevel.1.1", "1.3.6.1.2.1.43.11.1.1.9.1.1"},
+		{"prtAlertEntry", "1.3.6.1.2.1.43.18.1.1"},
+		{".1.3.6.1.2.1.1.5.0", "1.3.6.1.2.1.1.5.0"},
+	}
+	for _, test := range tests {
+		got, err := Resolve(test.name)
+		if err != nil || got != test.want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", test.name, got, err, test.want)
+		}
+	}
+
+	for _, name := range []string{"sysNom.0", "sysName.x", "Printer-MIB::sysName.0", ""} {
+		if oid, err := Resolve(name); err == nil {
+			t.Errorf("Resolve(%q) = %q", name, oid)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		oid  string
+		want string
+	}{
+		{"1.3.6.1.2.1.43.11.1.1.9.1.1", "prtMarkerSuppliesLevel.1.1"},
+		{".1.3.6.1.2.1.1.5.0", "sysName.0"},
+		{"1.3.6.1.2.1.43.18.1.1", "prtAlertEntry"},
+		{"1.3.6.1.4.1.99999.1", "enterprises.99999.1"},
+		{"2.999", ""},
+		{"not an oid", ""},
+	}
+	for _, test := range tests {
+		if got := Name(test.oid); got != test.want {
+			t.Errorf("Name(%q) = %q, want %q", test.oid, got, test.want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	if got := Decode("1.3.6.1.2.1.43.18.1.1.2.1.5", 3); got != "critical" {
+		t.Errorf("prtAlertSeverityLevel 3 = %q", got)
+	}
+	if got := Decode("1.3.6.1.2.1.43.18.1.1.2.1.5", 2); got != "" {
+		t.Errorf("undefined prtAlertSeverityLevel 2 = %q", got)
+	}
+	if got := Decode("1.3.6.1.2.1.1.5.0", 1); got != "" {
+		t.Errorf("sysName, not an enumeration, = %q", got)
+	}
+	if value, ok := PrtCoverStatusTC.Label("coverOpen"); !ok || value != 3 {
+		t.Errorf("Label(coverOpen) = %d, %t", value, ok)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	r := New()
+	if err := r.Add(Object{Name: "acme", Module: "ACME-MIB", OID: ".1.3.6.1.4.1.99999"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if object, ok := r.Lookup("ACME-MIB::acme"); !ok || object.OID != "1.3.6.1.4.1.99999" {
+		t.Errorf("Lookup = %+v, %t", object, ok)
+	}
+	if _, ok := r.Lookup("OTHER-MIB::acme"); ok {
+		t.Error("Lookup qualified by the wrong module succeeded")
+	}
+
+	// Adding it again updates it, keeping an enumeration it had
+	r.Add(Object{Name: "acmeState", Module: "ACME-MIB", OID: "1.3.6.1.4.1.99999.2", Enum: Enum{1: "ok"}})
+	if err := r.Add(Object{Name: "acmeState", Module: "ACME-V2-MIB", OID: "1.3.6.1.4.1.99999.2"}); err != nil {
+		t.Errorf("Add again: %v", err)
+	}
+	if object, _ := r.Lookup("acmeState"); object.Module != "ACME-V2-MIB" || object.Enum.Value(1) != "ok" {
+		t.Errorf("updated object = %+v", object)
+	}
+
+	if err := r.Add(Object{Name: "acme", OID: "1.3.6.1.4.1.1"}); err == nil || !strings.Contains(err.Error(), "already defined") {
+		t.Errorf("redefinition error = %v", err)
+	}
+	if err := r.Add(Object{Name: "bad", OID: "1.3.x"}); err == nil {
+		t.Error("invalid OID accepted")
+	}
+
+	r.Add(Object{Name: "acmeTen", OID: "1.3.6.1.4.1.99999.10"})
+	var oids []string
+	for _, object := range r.Objects() {
+		oids = append(oids, object.OID)
+	}
+	if want := "1.3.6.1.4.1.99999,1.3.6.1.4.1.99999.2,1.3.6.1.4.1.99999.10"; strings.Join(oids, ",") != want {
+		t.Errorf("Objects = %q, want %s", oids, want)
+	}
+}
+
+func TestBuiltinModules(t *testing.T) {
+	// Every built-in object has a unique name and OID
+	seen := make(map[string]string)
+	for _, module := range modules {
+		for _, def := range module.objects {
+			if other, ok := seen[def.oid]; ok && other != def.name {
+				t.Errorf("%s and %s share %s", other, def.name, def.oid)
+			}
+			seen[def.oid] = def.name
+		}
+	}
+	if len(Default.Objects()) != len(seen) {
+		t.Errorf("%d objects registered, %d defined", len(Default.Objects()), len(seen))
+	}
+}
@@ -0,0 +1,220 @@
+package mib
+
+// printerMIB is Printer-MIB (RFC 3805)
+var printerMIB = []definition{
+	{"printmib", "1.3.6.1.2.1.43", nil},
+
+	{"prtGeneral", "1.3.6.1.2.1.43.5", nil},
+	{"prtGeneralTable", "1.3.6.1.2.1.43.5.1", nil},
+	{"prtGeneralEntry", "1.3.6.1.2.1.43.5.1.1", nil},
+	{"prtGeneralConfigChanges", "1.3.6.1.2.1.43.5.1.1.1", nil},
+	{"prtGeneralCurrentLocalization", "1.3.6.1.2.1.43.5.1.1.2", nil},
+	{"prtGeneralReset", "1.3.6.1.2.1.43.5.1.1.3", Enum{
+		3: "notResetting", 4: "powerCycleReset", 5: "resetToNVRAM", 6: "resetToFactoryDefaults",
+	}},
+	{"prtGeneralCurrentOperator", "1.3.6.1.2.1.43.5.1.1.4", nil},
+	{"prtGeneralServicePerson", "1.3.6.1.2.1.43.5.1.1.5", nil},
+	{"prtInputDefaultIndex", "1.3.6.1.2.1.43.5.1.1.6", nil},
+	{"prtOutputDefaultIndex", "1.3.6.1.2.1.43.5.1.1.7", nil},
+	{"prtMarkerDefaultIndex", "1.3.6.1.2.1.43.5.1.1.8", nil},
+	{"prtMediaPathDefaultIndex", "1.3.6.1.2.1.43.5.1.1.9", nil},
+	{"prtConsoleLocalization", "1.3.6.1.2.1.43.5.1.1.10", nil},
+	{"prtConsoleNumberOfDisplayLines", "1.3.6.1.2.1.43.5.1.1.11", nil},
+	{"prtConsoleNumberOfDisplayChars", "1.3.6.1.2.1.43.5.1.1.12", nil},
+	{"prtConsoleDisable", "1.3.6.1.2.1.43.5.1.1.13", nil},
+	{"prtAuxiliarySheetStartupPage", "1.3.6.1.2.1.43.5.1.1.14", PresentOnOff},
+	{"prtAuxiliarySheetBannerPage", "1.3.6.1.2.1.43.5.1.1.15", PresentOnOff},
+	{"prtGeneralPrinterName", "1.3.6.1.2.1.43.5.1.1.16", nil},
+	{"prtGeneralSerialNumber", "1.3.6.1.2.1.43.5.1.1.17", nil},
+	{"prtAlertCriticalEvents", "1.3.6.1.2.1.43.5.1.1.18", nil},
+	{"prtAlertAllEvents", "1.3.6.1.2.1.43.5.1.1.19", nil},
+
+	{"prtCover", "1.3.6.1.2.1.43.6", nil},
+	{"prtCoverTable", "1.3.6.1.2.1.43.6.1", nil},
+	{"prtCoverEntry", "1.3.6.1.2.1.43.6.1.1", nil},
+	{"prtCoverIndex", "1.3.6.1.2.1.43.6.1.1.1", nil},
+	{"prtCoverDescription", "1.3.6.1.2.1.43.6.1.1.2", nil},
+	{"prtCoverStatus", "1.3.6.1.2.1.43.6.1.1.3", PrtCoverStatusTC},
+
+	{"prtLocalization", "1.3.6.1.2.1.43.7", nil},
+	{"prtLocalizationTable", "1.3.6.1.2.1.43.7.1", nil},
+	{"prtLocalizationEntry", "1.3.6.1.2.1.43.7.1.1", nil},
+	{"prtLocalizationIndex", "1.3.6.1.2.1.43.7.1.1.1", nil},
+	{"prtLocalizationLanguage", "1.3.6.1.2.1.43.7.1.1.2", nil},
+	{"prtLocalizationCountry", "1.3.6.1.2.1.43.7.1.1.3", nil},
+	{"prtLocalizationCharacterSet", "1.3.6.1.2.1.43.7.1.1.4", nil},
+
+	{"prtInput", "1.3.6.1.2.1.43.8", nil},
+	{"prtInputTable", "1.3.6.1.2.1.43.8.2", nil},
+	{"prtInputEntry", "1.3.6.1.2.1.43.8.2.1", nil},
+	{"prtInputIndex", "1.3.6.1.2.1.43.8.2.1.1", nil},
+	{"prtInputType", "1.3.6.1.2.1.43.8.2.1.2", PrtInputTypeTC},
+	{"prtInputDimUnit", "1.3.6.1.2.1.43.8.2.1.3", PrtMediaUnitTC},
+	{"prtInputMediaDimFeedDirDeclared", "1.3.6.1.2.1.43.8.2.1.4", nil},
+	{"prtInputMediaDimXFeedDirDeclared", "1.3.6.1.2.1.43.8.2.1.5", nil},
+	{"prtInputMediaDimFeedDirChosen", "1.3.6.1.2.1.43.8.2.1.6", nil},
+	{"prtInputMediaDimXFeedDirChosen", "1.3.6.1.2.1.43.8.2.1.7", nil},
+	{"prtInputCapacityUnit", "1.3.6.1.2.1.43.8.2.1.8", PrtCapacityUnitTC},
+	{"prtInputMaxCapacity", "1.3.6.1.2.1.43.8.2.1.9", nil},
+	{"prtInputCurrentLevel", "1.3.6.1.2.1.43.8.2.1.10", nil},
+	{"prtInputStatus", "1.3.6.1.2.1.43.8.2.1.11", nil}, // PrtSubUnitStatusTC, a bit field
+	{"prtInputMediaName", "1.3.6.1.2.1.43.8.2.1.12", nil},
+	{"prtInputName", "1.3.6.1.2.1.43.8.2.1.13", nil},
+	{"prtInputVendorName", "1.3.6.1.2.1.43.8.2.1.14", nil},
+	{"prtInputModel", "1.3.6.1.2.1.43.8.2.1.15", nil},
+	{"prtInputVersion", "1.3.6.1.2.1.43.8.2.1.16", nil},
+	{"prtInputSerialNumber", "1.3.6.1.2.1.43.8.2.1.17", nil},
+	{"prtInputDescription", "1.3.6.1.2.1.43.8.2.1.18", nil},
+	{"prtInputSecurity", "1.3.6.1.2.1.43.8.2.1.19", PresentOnOff},
+	{"prtInputMediaWeight", "1.3.6.1.2.1.43.8.2.1.20", nil},
+	{"prtInputMediaType", "1.3.6.1.2.1.43.8.2.1.21", nil},
+	{"prtInputMediaColor", "1.3.6.1.2.1.43.8.2.1.22", nil},
+	{"prtInputMediaFormParts", "1.3.6.1.2.1.43.8.2.1.23", nil},
+	{"prtInputMediaLoadTimeout", "1.3.6.1.2.1.43.8.2.1.24", nil},
+	{"prtInputNextIndex", "1.3.6.1.2.1.43.8.2.1.25", nil},
+
+	{"prtOutput", "1.3.6.1.2.1.43.9", nil},
+	{"prtOutputTable", "1.3.6.1.2.1.43.9.2", nil},
+	{"prtOutputEntry", "1.3.6.1.2.1.43.9.2.1", nil},
+	{"prtOutputIndex", "1.3.6.1.2.1.43.9.2.1.1", nil},
+	{"prtOutputType", "1.3.6.1.2.1.43.9.2.1.2", PrtOutputTypeTC},
+	{"prtOutputCapacityUnit", "1.3.6.1.2.1.43.9.2.1.3", PrtCapacityUnitTC},
+	{"prtOutputMaxCapacity", "1.3.6.1.2.1.43.9.2.1.4", nil},
+	{"prtOutputRemainingCapacity", "1.3.6.1.2.1.43.9.2.1.5", nil},
+	{"prtOutputStatus", "1.3.6.1.2.1.43.9.2.1.6", nil}, // PrtSubUnitStatusTC, a bit field
+	{"prtOutputName", "1.3.6.1.2.1.43.9.2.1.7", nil},
+	{"prtOutputVendorName", "1.3.6.1.2.1.43.9.2.1.8", nil},
+	{"prtOutputModel", "1.3.6.1.2.1.43.9.2.1.9", nil},
+	{"prtOutputVersion", "1.3.6.1.2.1.43.9.2.1.10", nil},
+	{"prtOutputSerialNumber", "1.3.6.1.2.1.43.9.2.1.11", nil},
+	{"prtOutputDescription", "1.3.6.1.2.1.43.9.2.1.12", nil},
+	{"prtOutputSecurity", "1.3.6.1.2.1.43.9.2.1.13", PresentOnOff},
+	{"prtOutputDimUnit", "1.3.6.1.2.1.43.9.2.1.14", PrtMediaUnitTC},
+	{"prtOutputMaxDimFeedDir", "1.3.6.1.2.1.43.9.2.1.15", nil},
+	{"prtOutputMaxDimXFeedDir", "1.3.6.1.2.1.43.9.2.1.16", nil},
+	{"prtOutputMinDimFeedDir", "1.3.6.1.2.1.43.9.2.1.17", nil},
+	{"prtOutputMinDimXFeedDir", "1.3.6.1.2.1.43.9.2.1.18", nil},
+	{"prtOutputStackingOrder", "1.3.6.1.2.1.43.9.2.1.19", PrtOutputStackingOrderTC},
+	{"prtOutputPageDeliveryOrientation", "1.3.6.1.2.1.43.9.2.1.20", PrtOutputPageDeliveryOrientationTC},
+	{"prtOutputBursting", "1.3.6.1.2.1.43.9.2.1.21", PresentOnOff},
+	{"prtOutputDecollating", "1.3.6.1.2.1.43.9.2.1.22", PresentOnOff},
+	{"prtOutputPageCollated", "1.3.6.1.2.1.43.9.2.1.23", PresentOnOff},
+	{"prtOutputOffsetStacking", "1.3.6.1.2.1.43.9.2.1.24", PresentOnOff},
+
+	{"prtMarker", "1.3.6.1.2.1.43.10", nil},
+	{"prtMarkerTable", "1.3.6.1.2.1.43.10.2", nil},
+	{"prtMarkerEntry", "1.3.6.1.2.1.43.10.2.1", nil},
+	{"prtMarkerIndex", "1.3.6.1.2.1.43.10.2.1.1", nil},
+	{"prtMarkerMarkTech", "1.3.6.1.2.1.43.10.2.1.2", PrtMarkerMarkTechTC},
+	{"prtMarkerCounterUnit", "1.3.6.1.2.1.43.10.2.1.3", PrtMarkerCounterUnitTC},
+	{"prtMarkerLifeCount", "1.3.6.1.2.1.43.10.2.1.4", nil},
+	{"prtMarkerPowerOnCount", "1.3.6.1.2.1.43.10.2.1.5", nil},
+	{"prtMarkerProcessColorants", "1.3.6.1.2.1.43.10.2.1.6", nil},
+	{"prtMarkerSpotColorants", "1.3.6.1.2.1.43.10.2.1.7", nil},
+	{"prtMarkerAddressabilityUnit", "1.3.6.1.2.1.43.10.2.1.8", Enum{3: "tenThousandthsOfInches", 4: "micrometers"}},
+	{"prtMarkerAddressabilityFeedDir", "1.3.6.1.2.1.43.10.2.1.9", nil},
+	{"prtMarkerAddressabilityXFeedDir", "1.3.6.1.2.1.43.10.2.1.10", nil},
+	{"prtMarkerNorthMargin", "1.3.6.1.2.1.43.10.2.1.11", nil},
+	{"prtMarkerSouthMargin", "1.3.6.1.2.1.43.10.2.1.12", nil},
+	{"prtMarkerWestMargin", "1.3.6.1.2.1.43.10.2.1.13", nil},
+	{"prtMarkerEastMargin", "1.3.6.1.2.1.43.10.2.1.14", nil},
+	{"prtMarkerStatus", "1.3.6.1.2.1.43.10.2.1.15", nil}, // PrtSubUnitStatusTC, a bit field
+
+	{"prtMarkerSupplies", "1.3.6.1.2.1.43.11", nil},
+	{"prtMarkerSuppliesTable", "1.3.6.1.2.1.43.11.1", nil},
+	{"prtMarkerSuppliesEntry", "1.3.6.1.2.1.43.11.1.1", nil},
+	{"prtMarkerSuppliesIndex", "1.3.6.1.2.1.43.11.1.1.1", nil},
+	{"prtMarkerSuppliesMarkerIndex", "1.3.6.1.2.1.43.11.1.1.2", nil},
+	{"prtMarkerSuppliesColorantIndex", "1.3.6.1.2.1.43.11.1.1.3", nil},
+	{"prtMarkerSuppliesClass", "1.3.6.1.2.1.43.11.1.1.4", Enum{1: "other", 3: "supplyThatIsConsumed", 4: "receptacleThatIsFilled"}},
+	{"prtMarkerSuppliesType", "1.3.6.1.2.1.43.11.1.1.5", PrtMarkerSuppliesTypeTC},
+	{"prtMarkerSuppliesDescription", "1.3.6.1.2.1.43.11.1.1.6", nil},
+	{"prtMarkerSuppliesSupplyUnit", "1.3.6.1.2.1.43.11.1.1.7", PrtMarkerSuppliesSupplyUnitTC},
+	{"prtMarkerSuppliesMaxCapacity", "1.3.6.1.2.1.43.11.1.1.8", nil},
+	{"prtMarkerSuppliesLevel", "1.3.6.1.2.1.43.11.1.1.9", nil},
+
+	{"prtMarkerColorant", "1.3.6.1.2.1.43.12", nil},
+	{"prtMarkerColorantTable", "1.3.6.1.2.1.43.12.1", nil},
+	{"prtMarkerColorantEntry", "1.3.6.1.2.1.43.12.1.1", nil},
+	{"prtMarkerColorantIndex", "1.3.6.1.2.1.43.12.1.1.1", nil},
+	{"prtMarkerColorantMarkerIndex", "1.3.6.1.2.1.43.12.1.1.2", nil},
+	{"prtMarkerColorantRole", "1.3.6.1.2.1.43.12.1.1.3", Enum{1: "other", 3: "process", 4: "spot"}},
+	{"prtMarkerColorantValue", "1.3.6.1.2.1.43.12.1.1.4", nil},
+	{"prtMarkerColorantTonality", "1.3.6.1.2.1.43.12.1.1.5", nil},
+
+	{"prtMediaPath", "1.3.6.1.2.1.43.13", nil},
+	{"prtMediaPathTable", "1.3.6.1.2.1.43.13.4", nil},
+	{"prtMediaPathEntry", "1.3.6.1.2.1.43.13.4.1", nil},
+	{"prtMediaPathIndex", "1.3.6.1.2.1.43.13.4.1.1", nil},
+	{"prtMediaPathMaxSpeedPrintUnit", "1.3.6.1.2.1.43.13.4.1.2", PrtMediaPathMaxSpeedPrintUnitTC},
+	{"prtMediaPathMediaSizeUnit", "1.3.6.1.2.1.43.13.4.1.3", PrtMediaUnitTC},
+	{"prtMediaPathMaxSpeed", "1.3.6.1.2.1.43.13.4.1.4", nil},
+	{"prtMediaPathMaxMediaFeedDir", "1.3.6.1.2.1.43.13.4.1.5", nil},
+	{"prtMediaPathMaxMediaXFeedDir", "1.3.6.1.2.1.43.13.4.1.6", nil},
+	{"prtMediaPathMinMediaFeedDir", "1.3.6.1.2.1.43.13.4.1.7", nil},
+	{"prtMediaPathMinMediaXFeedDir", "1.3.6.1.2.1.43.13.4.1.8", nil},
+	{"prtMediaPathType", "1.3.6.1.2.1.43.13.4.1.9", PrtMediaPathTypeTC},
+	{"prtMediaPathDescription", "1.3.6.1.2.1.43.13.4.1.10", nil},
+	{"prtMediaPathStatus", "1.3.6.1.2.1.43.13.4.1.11", nil}, // PrtSubUnitStatusTC, a bit field
+
+	{"prtChannel", "1.3.6.1.2.1.43.14", nil},
+	{"prtChannelTable", "1.3.6.1.2.1.43.14.1", nil},
+	{"prtChannelEntry", "1.3.6.1.2.1.43.14.1.1", nil},
+	{"prtChannelIndex", "1.3.6.1.2.1.43.14.1.1.1", nil},
+	{"prtChannelType", "1.3.6.1.2.1.43.14.1.1.2", nil},
+	{"prtChannelProtocolVersion", "1.3.6.1.2.1.43.14.1.1.3", nil},
+	{"prtChannelCurrentJobCntlLangIndex", "1.3.6.1.2.1.43.14.1.1.4", nil},
+	{"prtChannelDefaultPageDescLangIndex", "1.3.6.1.2.1.43.14.1.1.5", nil},
+	{"prtChannelState", "1.3.6.1.2.1.43.14.1.1.6", Enum{1: "other", 3: "printDataAccepted", 4: "noDataAccepted"}},
+	{"prtChannelIfIndex", "1.3.6.1.2.1.43.14.1.1.7", nil},
+	{"prtChannelStatus", "1.3.6.1.2.1.43.14.1.1.8", nil},
+	{"prtChannelInformation", "1.3.6.1.2.1.43.14.1.1.9", nil},
+
+	{"prtInterpreter", "1.3.6.1.2.1.43.15", nil},
+	{"prtInterpreterTable", "1.3.6.1.2.1.43.15.1", nil},
+	{"prtInterpreterEntry", "1.3.6.1.2.1.43.15.1.1", nil},
+	{"prtInterpreterIndex", "1.3.6.1.2.1.43.15.1.1.1", nil},
+	{"prtInterpreterLangFamily", "1.3.6.1.2.1.43.15.1.1.2", PrtInterpreterLangFamilyTC},
+	{"prtInterpreterLangLevel", "1.3.6.1.2.1.43.15.1.1.3", nil},
+	{"prtInterpreterLangVersion", "1.3.6.1.2.1.43.15.1.1.4", nil},
+	{"prtInterpreterDescription", "1.3.6.1.2.1.43.15.1.1.5", nil},
+	{"prtInterpreterVersion", "1.3.6.1.2.1.43.15.1.1.6", nil},
+	{"prtInterpreterDefaultOrientation", "1.3.6.1.2.1.43.15.1.1.7", Enum{1: "other", 3: "portrait", 4: "landscape"}},
+	{"prtInterpreterFeedAddressability", "1.3.6.1.2.1.43.15.1.1.8", nil},
+	{"prtInterpreterXFeedAddressability", "1.3.6.1.2.1.43.15.1.1.9", nil},
+	{"prtInterpreterDefaultCharSetIn", "1.3.6.1.2.1.43.15.1.1.10", nil},
+	{"prtInterpreterDefaultCharSetOut", "1.3.6.1.2.1.43.15.1.1.11", nil},
+	{"prtInterpreterTwoWay", "1.3.6.1.2.1.43.15.1.1.12", Enum{3: "yes", 4: "no"}},
+
+	{"prtConsoleDisplayBuffer", "1.3.6.1.2.1.43.16", nil},
+	{"prtConsoleDisplayBufferTable", "1.3.6.1.2.1.43.16.5", nil},
+	{"prtConsoleDisplayBufferEntry", "1.3.6.1.2.1.43.16.5.1", nil},
+	{"prtConsoleDisplayBufferIndex", "1.3.6.1.2.1.43.16.5.1.1", nil},
+	{"prtConsoleDisplayBufferText", "1.3.6.1.2.1.43.16.5.1.2", nil},
+
+	{"prtConsoleLights", "1.3.6.1.2.1.43.17", nil},
+	{"prtConsoleLightTable", "1.3.6.1.2.1.43.17.6", nil},
+	{"prtConsoleLightEntry", "1.3.6.1.2.1.43.17.6.1", nil},
+	{"prtConsoleLightIndex", "1.3.6.1.2.1.43.17.6.1.1", nil},
+	{"prtConsoleOnTime", "1.3.6.1.2.1.43.17.6.1.2", nil},
+	{"prtConsoleOffTime", "1.3.6.1.2.1.43.17.6.1.3", nil},
+	{"prtConsoleColor", "1.3.6.1.2.1.43.17.6.1.4", PrtConsoleColorTC},
+	{"prtConsoleDescription", "1.3.6.1.2.1.43.17.6.1.5", nil},
+
+	{"prtAlert", "1.3.6.1.2.1.43.18", nil},
+	{"prtAlertTable", "1.3.6.1.2.1.43.18.1", nil},
+	{"prtAlertEntry", "1.3.6.1.2.1.43.18.1.1", nil},
+	{"prtAlertIndex", "1.3.6.1.2.1.43.18.1.1.1", nil},
+	{"prtAlertSeverityLevel", "1.3.6.1.2.1.43.18.1.1.2", Enum{
+		1: "other", 3: "critical", 4: "warning", 5: "warningBinaryChangeEvent",
+	}},
+	{"prtAlertTrainingLevel", "1.3.6.1.2.1.43.18.1.1.3", PrtAlertTrainingLevelTC},
+	{"prtAlertGroup", "1.3.6.1.2.1.43.18.1.1.4", PrtAlertGroupTC},
+	{"prtAlertGroupIndex", "1.3.6.1.2.1.43.18.1.1.5", nil},
+	{"prtAlertLocation", "1.3.6.1.2.1.43.18.1.1.6", nil},
+	{"prtAlertCode", "1.3.6.1.2.1.43.18.1.1.7", PrtAlertCodeTC},
+	{"prtAlertDescription", "1.3.6.1.2.1.43.18.1.1.8", nil},
+	{"prtAlertTime", "1.3.6.1.2.1.43.18.1.1.9", nil},
+	{"printerV1Alert", "1.3.6.1.2.1.43.18.2", nil},
+	{"printerV2Alert", "1.3.6.1.2.1.43.18.2.0.1", nil},
+}
@@ -0,0 +1,320 @@
+package mib
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// macros are the SMI macros whose invocations assign an OID to a name
+var macros = map[string]bool{
+	"OBJECT-TYPE":        true,
+	"OBJECT-IDENTITY":    true,
+	"MODULE-IDENTITY":    true,
+	"NOTIFICATION-TYPE":  true,
+	"OBJECT-GROUP":       true,
+	"NOTIFICATION-GROUP": true,
+	"MODULE-COMPLIANCE":  true,
+	"AGENT-CAPABILITIES": true,
+}
+
+// smiObject is an OID assignment read from a MIB file, not yet resolved
+type smiObject struct {
+	module string
+	name   string
+	parent string   // Name the OID is relative to, "" for an absolute OID
+	arcs   []string // Arcs below the parent
+	syntax string   // Type name of an OBJECT-TYPE, to look enumerations up by
+	enum   Enum     // Enumeration declared inline in the SYNTAX clause
+}
+
+// LoadFile adds the objects of a MIB file to the registry, see Load
+func (r *Registry) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := r.Load(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Load adds the objects of one or more SMIv2 (or SMIv1) MIB modules to the
+// registry. Only OID assignments and INTEGER enumerations are read, of the
+// objects themselves and of the textual conventions they use. Names a module
+// imports must already be registered, by the built-in modules or by loading
+// the module that defines them first.
+func (r *Registry) Load(reader io.Reader) error {
+	source, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	objects, conventions, err := parseSMI(tokenize(string(source)))
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	for name, enum := range conventions {
+		r.conventions[name] = enum
+	}
+	r.mu.Unlock()
+
+	// Objects may be defined before the objects they are relative to
+	resolved := make(map[string]string)
+	for pending := objects; len(pending) > 0; {
+		var unresolved []smiObject
+		for _, object := range pending {
+			base, ok := resolved[object.parent]
+			if !ok && object.parent != "" {
+				parent, known := r.Lookup(object.parent)
+				if !known {
+					unresolved = append(unresolved, object)
+					continue
+				}
+				base = parent.OID
+			}
+			arcs := object.arcs
+			if base != "" {
+				arcs = append([]string{base}, arcs...)
+			}
+			resolved[object.name] = strings.Join(arcs, ".")
+		}
+		if len(unresolved) == len(pending) {
+			var names []string
+			for _, object := range unresolved {
+				names = append(names, fmt.Sprintf("%s (under %s)", object.name, object.parent))
+			}
+			sort.Strings(names)
+			return fmt.Errorf("mib: cannot resolve %s", strings.Join(names, ", "))
+		}
+		pending = unresolved
+	}
+
+	for _, object := range objects {
+		enum := object.enum
+		if enum == nil && object.syntax != "" {
+			r.mu.RLock()
+			enum = r.conventions[object.syntax]
+			r.mu.RUnlock()
+		}
+		if err := r.Add(Object{Name: object.name, Module: object.module, OID: resolved[object.name], Enum: enum}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseSMI reads the OID assignments and enumerated types of the modules in
+// a tokenized MIB file
+func parseSMI(tokens []string) ([]smiObject, map[string]Enum, error) {
+	var objects []smiObject
+	conventions := make(map[string]Enum)
+	module := ""
+	at := func(i int) string {
+		if i < len(tokens) {
+			return tokens[i]
+		}
+		return ""
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token == "DEFINITIONS" && i > 0:
+			module = tokens[i-1]
+		case token == "IMPORTS" || token == "EXPORTS":
+			for i < len(tokens) && tokens[i] != ";" {
+				i++
+			}
+		case token == "MACRO":
+			for i < len(tokens) && tokens[i] != "END" {
+				i++
+			}
+
+		case lowerIdent(token) && macros[at(i+1)]:
+			// name OBJECT-TYPE ... ::= { parent arc }
+			object := smiObject{module: module, name: token}
+			j := i + 2
+			for ; j < len(tokens) && tokens[j] != "::="; j++ {
+				if tokens[j] == "SYNTAX" && at(i+1) == "OBJECT-TYPE" && object.syntax == "" && object.enum == nil {
+					object.syntax, object.enum, j = parseSyntax(tokens, j+1)
+				}
+			}
+			if at(j+1) != "{" {
+				i = j // A TRAP-TYPE style number, not an OID
+				continue
+			}
+			var err error
+			if object.parent, object.arcs, i, err = parseOID(tokens, j+2); err != nil {
+				return nil, nil, fmt.Errorf("mib: %s: %w", token, err)
+			}
+			objects = append(objects, object)
+
+		case lowerIdent(token) && at(i+1) == "OBJECT" && at(i+2) == "IDENTIFIER" && at(i+3) == "::=" && at(i+4) == "{":
+			// name OBJECT IDENTIFIER ::= { parent arc }
+			object := smiObject{module: module, name: token}
+			var err error
+			if object.parent, object.arcs, i, err = parseOID(tokens, i+5); err != nil {
+				return nil, nil, fmt.Errorf("mib: %s: %w", token, err)
+			}
+			objects = append(objects, object)
+
+		case upperIdent(token) && at(i+1) == "::=":
+			// Name ::= TEXTUAL-CONVENTION ... SYNTAX INTEGER { label(value), ... }
+			// or Name ::= INTEGER { ... }
+			j := i + 2
+			if at(j) == "TEXTUAL-CONVENTION" {
+				for j < len(tokens) && tokens[j] != "SYNTAX" && tokens[j] != "::=" {
+					j++
+				}
+				j++
+			}
+			if at(j) == "INTEGER" && at(j+1) == "{" {
+				var enum Enum
+				_, enum, i = parseSyntax(tokens, j)
+				conventions[token] = enum
+			}
+		}
+	}
+
+	if module == "" {
+		return nil, nil, fmt.Errorf("mib: no module definition found")
+	}
+	return objects, conventions, nil
+}
+
+// parseSyntax reads a SYNTAX clause starting at tokens[i]. It returns the
+// type name, or the enumeration for an enumerated INTEGER, and the index of
+// the clause's last token.
+func parseSyntax(tokens []string, i int) (string, Enum, int) {
+	if i >= len(tokens) {
+		return "", nil, i
+	}
+	if tokens[i] != "INTEGER" || i+1 >= len(tokens) || tokens[i+1] != "{" {
+		return tokens[i], nil, i
+	}
+
+	enum := make(Enum)
+	j := i + 2
+	for ; j+3 < len(tokens) && tokens[j] != "}"; j++ {
+		// label ( value )
+		if tokens[j+1] != "(" || tokens[j+3] != ")" {
+			continue
+		}
+		var value int
+		if _, err := fmt.Sscan(tokens[j+2], &value); err == nil {
+			enum[value] = tokens[j]
+		}
+		j += 3
+	}
+	return "", enum, j
+}
+
+// parseOID reads an OID value such as "{ mib-2 43 }" or "{ iso(1) org(3) 6 }"
+// whose first component is at tokens[i], returning the name it is relative
+// to, the arcs below it and the index of the closing brace
+func parseOID(tokens []string, i int) (string, []string, int, error) {
+	parent := ""
+	var arcs []string
+	for ; i < len(tokens) && tokens[i] != "}"; i++ {
+		token := tokens[i]
+		switch {
+		case numeric(token):
+			arcs = append(arcs, token)
+		case i+3 < len(tokens) && tokens[i+1] == "(" && tokens[i+3] == ")":
+			// name(number), the number is what counts
+			arcs = append(arcs, tokens[i+2])
+			i += 3
+		case parent == "" && len(arcs) == 0:
+			parent = token
+		default:
+			return "", nil, i, fmt.Errorf("unexpected %q in OID value", token)
+		}
+	}
+	if i >= len(tokens) {
+		return "", nil, i, fmt.Errorf("unterminated OID value")
+	}
+	if parent == "" && len(arcs) == 0 {
+		return "", nil, i, fmt.Errorf("empty OID value")
+	}
+	return parent, arcs, i, nil
+}
+
+// tokenize splits a MIB file into identifiers, numbers and symbols. Comments
+// are dropped and quoted strings become a single "" token.
+func tokenize(source string) []string {
+	var tokens []string
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f':
+			i++
+		case strings.HasPrefix(source[i:], "--"):
+			// A comment runs to the end of the line or the next "--"
+			end := i + 2
+			for end < len(source) && source[end] != '\n' && !strings.HasPrefix(source[end:], "--") {
+				end++
+			}
+			i = end
+			if strings.HasPrefix(source[i:], "--") {
+				i += 2
+			}
+		case c == '"':
+			end := strings.IndexByte(source[i+1:], '"')
+			if end < 0 {
+				return tokens
+			}
+			tokens = append(tokens, `""`)
+			i += end + 2
+		case c == '\'':
+			// Hex or binary string such as 'ff'H
+			end := strings.IndexByte(source[i+1:], '\'')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 2
+			if i < len(source) && (source[i] == 'H' || source[i] == 'h' || source[i] == 'B' || source[i] == 'b') {
+				i++
+			}
+			tokens = append(tokens, "''")
+		case strings.HasPrefix(source[i:], "::="):
+			tokens = append(tokens, "::=")
+			i += 3
+		case strings.HasPrefix(source[i:], ".."):
+			tokens = append(tokens, "..")
+			i += 2
+		case isWordByte(c) || (c == '-' && i+1 < len(source) && source[i+1] >= '0' && source[i+1] <= '9'):
+			end := i + 1
+			for end < len(source) && (isWordByte(source[end]) || (source[end] == '-' && !strings.HasPrefix(source[end:], "--"))) {
+				end++
+			}
+			tokens = append(tokens, source[i:end])
+			i = end
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens
+}
+
+// isWordByte reports whether c can be part of an identifier or number
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// lowerIdent reports whether token is a value name, which starts lower case
+func lowerIdent(token string) bool {
+	return token != "" && unicode.IsLower(rune(token[0]))
+}
+
+// upperIdent reports whether token is a type name, which starts upper case
+func upperIdent(token string) bool {
+	return token != "" && unicode.IsUpper(rune(token[0]))
+}
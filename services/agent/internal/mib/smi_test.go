@@ -0,0 +1,174 @@
+package mib
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// acmeMIB is a vendor MIB using the constructs the loader reads
+const acmeMIB = `
+ACME-PRINTER-MIB DEFINITIONS ::= BEGIN
+
+IMPORTS
+    MODULE-IDENTITY, OBJECT-TYPE, Integer32, enterprises
+        FROM SNMPv2-SMI
+    TEXTUAL-CONVENTION FROM SNMPv2-TC
+    PrtCoverStatusTC FROM IANA-PRINTER-MIB;
+
+acmePrinterMIB MODULE-IDENTITY
+    LAST-UPDATED "202601010000Z"
+    ORGANIZATION "ACME -- printers"
+    CONTACT-INFO "support@acme.example"
+    DESCRIPTION  "Objects of ACME printers."
+    ::= { acme 1 }
+
+-- Defined after the module identity that is relative to it
+acme OBJECT IDENTIFIER ::= { enterprises 99999 }
+
+AcmeTonerState ::= TEXTUAL-CONVENTION
+    STATUS  current
+    DESCRIPTION "State of a toner cartridge."
+    SYNTAX  INTEGER { ok(1), low(2), empty(3) }
+
+AcmeDrumState ::= INTEGER { fine(1), worn(2) }
+
+acmeObjects OBJECT IDENTIFIER ::= { acmePrinterMIB 2 }
+
+acmeTonerState OBJECT-TYPE
+    SYNTAX      AcmeTonerState
+    MAX-ACCESS  read-only
+    STATUS      current
+    DESCRIPTION "The state of the toner, -- not a comment."
+    DEFVAL      { ok }
+    ::= { acmeObjects 1 }
+
+acmeDrumState OBJECT-TYPE
+    SYNTAX      AcmeDrumState
+    MAX-ACCESS  read-only
+    STATUS      current
+    DESCRIPTION "The state of the drum."
+    ::= { acmeObjects 2 }
+
+acmeTrayMode OBJECT-TYPE
+    SYNTAX      INTEGER { auto(1), manual(2), -- inline comment
+                          disabled(-1) }
+    MAX-ACCESS  read-write
+    STATUS      current
+    DESCRIPTION "How the tray feeds."
+    ::= { acmeObjects 3 }
+
+acmeDoor OBJECT-TYPE
+    SYNTAX      PrtCoverStatusTC
+    MAX-ACCESS  read-only
+    STATUS      current
+    DESCRIPTION "The front door."
+    ::= { acmeObjects 4 }
+
+acmeSerial OBJECT-TYPE
+    SYNTAX      OCTET STRING (SIZE (0..32))
+    MAX-ACCESS  read-only
+    STATUS      current
+    DESCRIPTION "Serial number."
+    DEFVAL      { 'ff'H }
+    ::= { acmeObjects 5 }
+
+acmeLegacy OBJECT IDENTIFIER ::= { iso(1) org(3) dod(6) internet(1) private(4) enterprises(1) 99999 9 }
+
+END
+`
+
+func TestLoad(t *testing.T) {
+	r := builtin()
+	if err := r.Load(strings.NewReader(acmeMIB)); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		oid  string
+		enum Enum
+	}{
+		{"acme", "1.3.6.1.4.1.99999", nil},
+		{"acmePrinterMIB", "1.3.6.1.4.1.99999.1", nil},
+		{"acmeObjects", "1.3.6.1.4.1.99999.1.2", nil},
+		{"acmeTonerState", "1.3.6.1.4.1.99999.1.2.1", Enum{1: "ok", 2: "low", 3: "empty"}},
+		{"acmeDrumState", "1.3.6.1.4.1.99999.1.2.2", Enum{1: "fine", 2: "worn"}},
+		{"acmeTrayMode", "1.3.6.1.4.1.99999.1.2.3", Enum{1: "auto", 2: "manual", -1: "disabled"}},
+		{"acmeDoor", "1.3.6.1.4.1.99999.1.2.4", PrtCoverStatusTC},
+		{"acmeSerial", "1.3.6.1.4.1.99999.1.2.5", nil},
+		{"acmeLegacy", "1.3.6.1.4.1.99999.9", nil},
+	}
+	for _, test := range tests {
+		object, ok := r.Lookup("ACME-PRINTER-MIB::" + test.name)
+		if !ok {
+			t.Errorf("%s not loaded", test.name)
+			continue
+		}
+		if object.OID != test.oid || !reflect.DeepEqual(object.Enum, test.enum) {
+			t.Errorf("%s = %s %v, want %s %v", test.name, object.OID, object.Enum, test.oid, test.enum)
+		}
+	}
+	if got := r.Name("1.3.6.1.4.1.99999.1.2.1.0"); got != "acmeTonerState.0" {
+		t.Errorf("Name = %q", got)
+	}
+	if got := r.Decode("1.3.6.1.4.1.99999.1.2.1.0", 2); got != "low" {
+		t.Errorf("Decode = %q", got)
+	}
+
+	// The Default registry is left alone
+	if _, ok := Default.Lookup("acme"); ok {
+		t.Error("loading into a registry changed Default")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"no module", "acme OBJECT IDENTIFIER ::= { enterprises 99999 }", "no module"},
+		{"unknown parent", "X-MIB DEFINITIONS ::= BEGIN\nx OBJECT IDENTIFIER ::= { nowhere 1 }\nEND", "cannot resolve x (under nowhere)"},
+		{"circular", "X-MIB DEFINITIONS ::= BEGIN\na OBJECT IDENTIFIER ::= { b 1 }\nb OBJECT IDENTIFIER ::= { a 1 }\nEND", "cannot resolve a (under b), b (under a)"},
+		{"unterminated OID", "X-MIB DEFINITIONS ::= BEGIN\nx OBJECT IDENTIFIER ::= { enterprises 1", "unterminated"},
+		{"empty OID", "X-MIB DEFINITIONS ::= BEGIN\nx OBJECT IDENTIFIER ::= { }\nEND", "empty OID"},
+		{"redefined", "X-MIB DEFINITIONS ::= BEGIN\nsysName OBJECT IDENTIFIER ::= { enterprises 1 }\nEND", "already defined"},
+	}
+	for _, test := range tests {
+		err := builtin().Load(strings.NewReader(test.source))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error = %v, want %q", test.name, err, test.want)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ACME-PRINTER-MIB.txt")
+	if err := os.WriteFile(path, []byte(acmeMIB), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := builtin().LoadFile(path); err != nil {
+		t.Errorf("LoadFile: %v", err)
+	}
+
+	broken := filepath.Join(dir, "BROKEN-MIB.txt")
+	os.WriteFile(broken, []byte("x OBJECT IDENTIFIER ::= { acme 1 }"), 0o644)
+	if err := builtin().LoadFile(broken); err == nil || !strings.HasPrefix(err.Error(), broken+": ") {
+		t.Errorf("error = %v, want it prefixed with the path", err)
+	}
+	if err := builtin().LoadFile(filepath.Join(dir, "MISSING-MIB.txt")); err == nil {
+		t.Error("loading a missing file succeeded")
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := tokenize(`a ::= { b 1 } -- comment -- c "quoted -- text" 'ff'H SIZE (0..32) d-e --x`)
+	want := []string{"a", "::=", "{", "b", "1", "}", "c", `""`, "''", "SIZE", "(", "0", "..", "32", ")", "d-e"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenize = %q, want %q", got, want)
+	}
+}
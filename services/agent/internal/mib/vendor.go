@@ -0,0 +1,33 @@
+package mib
+
+// portMonitorMIB is the printer table of the PWG Port Monitor MIB, which
+// holds the IEEE 1284 device ID on printers that don't expose it elsewhere
+var portMonitorMIB = []definition{
+	{"pwg", "1.3.6.1.4.1.2699", nil},
+	{"ppmPrinterTable", "1.3.6.1.4.1.2699.1.2.1.2.1", nil},
+	{"ppmPrinterEntry", "1.3.6.1.4.1.2699.1.2.1.2.1.1", nil},
+	{"ppmPrinterIndex", "1.3.6.1.4.1.2699.1.2.1.2.1.1.1", nil},
+	{"ppmPrinterName", "1.3.6.1.4.1.2699.1.2.1.2.1.1.2", nil},
+	{"ppmPrinterIEEE1284DeviceId", "1.3.6.1.4.1.2699.1.2.1.2.1.1.3", nil},
+	{"ppmPrinterNumberOfPorts", "1.3.6.1.4.1.2699.1.2.1.2.1.1.4", nil},
+	{"ppmPrinterPreferredPortIndex", "1.3.6.1.4.1.2699.1.2.1.2.1.1.5", nil},
+	{"ppmPrinterHrDeviceIndex", "1.3.6.1.4.1.2699.1.2.1.2.1.1.6", nil},
+}
+
+// vendorObjects are the vendor specific objects the collectors read. The
+// vendors don't publish MIBs for all of them, so they are named after what
+// they were found to hold.
+var vendorObjects = []definition{
+	{"hp", "1.3.6.1.4.1.11", nil},
+	{"hpIEEE1284DeviceId", "1.3.6.1.4.1.11.2.3.9.1.1.7", nil},
+
+	{"brother", "1.3.6.1.4.1.2435", nil},
+	{"brotherErrorStatus", "1.3.6.1.4.1.2435.2.3.9.1.1.2", nil},
+	{"brotherErrorDescription", "1.3.6.1.4.1.2435.2.3.9.1.1.3", nil},
+	{"brotherIEEE1284DeviceId", "1.3.6.1.4.1.2435.2.3.9.1.1.7", nil},
+	{"brotherPaperJams", "1.3.6.1.4.1.2435.2.3.9.2.1.2.9", nil},
+	{"brotherPageCounters", "1.3.6.1.4.1.2435.2.3.9.4.2.1.1.1.6", nil},
+	// Rows of KEY="value" text: 1 model, 2 serial number, 7 main firmware,
+	// 8 and 9 sub firmware ID and version
+	{"brotherMaintenanceInfo", "1.3.6.1.4.1.2435.2.4.3.99.3.1.6.1.2", nil},
+}
@@ -0,0 +1,162 @@
+// Package pjl collects printer status with read-only PJL INFO queries over
+// raw TCP port 9100, for old printers whose SNMP agent can't be trusted.
+package pjl
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+// DefaultPort is the raw printing port PJL is spoken on
+const DefaultPort = 9100
+
+// Read-only queries the collector sends
+const (
+	InfoID        = "@PJL INFO ID"
+	InfoStatus    = "@PJL INFO STATUS"
+	InfoPageCount = "@PJL INFO PAGECOUNT"
+	InfoConfig    = "@PJL INFO CONFIG"
+)
+
+// allowed is the allowlist of commands Query will send. Anything that could
+// change settings or print, such as @PJL SET or a job, is refused: the
+// collector must never alter a printer.
+var allowed = map[string]bool{
+	InfoID:        true,
+	InfoStatus:    true,
+	InfoPageCount: true,
+	InfoConfig:    true,
+}
+
+// ErrNotAllowed is returned for commands outside the read-only allowlist
+var ErrNotAllowed = errors.New("pjl: command not allowed")
+
+// uel is the Universal Exit Language sequence that starts and ends a PJL job
+const uel = "\x1b%-12345X"
+
+// maxResponse bounds the size of a single response
+const maxResponse = 64 << 10
+
+// Client represents a PJL client for printer monitoring
+type Client struct {
+	timeout time.Duration
+}
+
+// NewClient creates a new PJL client
+func NewClient() *Client {
+	return &Client{timeout: 10 * time.Second}
+}
+
+// Query sends read-only INFO commands to a printer and returns each
+// command's response without its echo line. host may carry a port,
+// otherwise DefaultPort is used.
+func (c *Client) Query(ctx context.Context, host string, commands ...string) (map[string]string, error) {
+	for _, command := range commands {
+		if !allowed[command] {
+			return nil, fmt.Errorf("%w: %q", ErrNotAllowed, command)
+		}
+	}
+
+	address := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		address = net.JoinHostPort(host, strconv.Itoa(DefaultPort))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("printer %s not responding: %w", host, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	// Unblock reads and writes as soon as the poll is cancelled
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	responses := make(map[string]string)
+	reader := bufio.NewReader(conn)
+	for i, command := range commands {
+		request := command + "\r\n"
+		if i == 0 {
+			request = uel + "@PJL\r\n" + request
+		}
+		if _, err := conn.Write([]byte(request)); err != nil {
+			return nil, fmt.Errorf("printer %s: %w", host, err)
+		}
+
+		response, err := readResponse(reader)
+		if err != nil {
+			return nil, fmt.Errorf("printer %s: %s: %w", host, command, err)
+		}
+		responses[command] = response
+	}
+	conn.Write([]byte(uel)) // End the job
+	return responses, nil
+}
+
+// readResponse reads a response up to its terminating form feed and drops
+// the echoed command line
+func readResponse(reader *bufio.Reader) (string, error) {
+	var response strings.Builder
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if b == '\f' {
+			break
+		}
+		if response.Len() >= maxResponse {
+			return "", errors.New("response too long")
+		}
+		response.WriteByte(b)
+	}
+
+	text := strings.ReplaceAll(response.String(), "\r\n", "\n")
+	text = strings.TrimLeft(text, "\n")
+	if strings.HasPrefix(strings.ToUpper(text), "@PJL") {
+		_, text, _ = strings.Cut(text, "\n")
+	}
+	return strings.TrimRight(text, "\n"), nil
+}
+
+// Probe checks whether a printer answers PJL at all, using a single short
+// query instead of a full poll
+func (c *Client) Probe(ctx context.Context, host string) error {
+	timeout := c.timeout
+	if timeout > 3*time.Second {
+		timeout = 3 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	_, err := c.Query(ctx, host, InfoID)
+	return err
+}
+
+// Poll queries a printer for its status
+func (c *Client) Poll(host string) (*snmp.PrinterStatus, error) {
+	return c.PollContext(context.Background(), host)
+}
+
+// PollContext queries a printer's identity, status, page count and
+// configuration over PJL and maps them into a PrinterStatus
+func (c *Client) PollContext(ctx context.Context, host string) (*snmp.PrinterStatus, error) {
+	responses, err := c.Query(ctx, host, InfoID, InfoStatus, InfoPageCount, InfoConfig)
+	if err != nil {
+		return nil, err
+	}
+	return Status(host, responses, time.Now()), nil
+}
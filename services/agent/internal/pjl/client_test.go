@@ -0,0 +1,183 @@
+package pjl
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+// standIn is a local TCP server that answers PJL INFO queries like a
+// printer on port 9100, echoing each command before its reply
+type standIn struct {
+	listener net.Listener
+	replies  map[string]string
+
+	mu       sync.Mutex
+	commands []string
+}
+
+// newStandIn starts a stand-in that answers the given commands. Other PJL
+// commands get no reply; anything that isn't PJL closes the connection.
+func newStandIn(t *testing.T, replies map[string]string) *standIn {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &standIn{listener: listener, replies: replies}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *standIn) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *standIn) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSpace(strings.ReplaceAll(line, uel, ""))
+		if line == "" || line == "@PJL" {
+			continue
+		}
+		if !strings.HasPrefix(line, "@PJL") {
+			return // Not PJL, e.g. an HTTP request
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, line)
+		s.mu.Unlock()
+		if reply, ok := s.replies[line]; ok {
+			conn.Write([]byte(line + "\r\n" + strings.ReplaceAll(reply, "\n", "\r\n") + "\r\n\f"))
+		}
+	}
+}
+
+// Address is the host:port of the stand-in
+func (s *standIn) Address() string {
+	return s.listener.Addr().String()
+}
+
+// Commands returns the commands received so far
+func (s *standIn) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+// laserJet are the replies of a LaserJet with a door open
+var laserJet = map[string]string{
+	InfoID:        `"HP LaserJet 4250"`,
+	InfoStatus:    "CODE=40021\nDISPLAY=\"CLOSE DOOR\"\nONLINE=TRUE",
+	InfoPageCount: "PAGECOUNT=184022",
+	InfoConfig: "IN TRAYS [3 ENUMERATED]\n\tINTRAY1 MP\n\tINTRAY2 PC\n\tINTRAY3 LC\n" +
+		"OUT TRAYS [1 ENUMERATED]\n\tNORMAL FACEDOWN\n" +
+		"DUPLEX\n" +
+		"LANGUAGES [2 ENUMERATED]\n\tPCL\n\tPOSTSCRIPT\n" +
+		"MEMORY=80MB",
+}
+
+func TestQuery(t *testing.T) {
+	s := newStandIn(t, laserJet)
+
+	responses, err := NewClient().Query(context.Background(), s.Address(), InfoID, InfoPageCount)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if responses[InfoID] != `"HP LaserJet 4250"` || responses[InfoPageCount] != "PAGECOUNT=184022" {
+		t.Errorf("responses = %q", responses)
+	}
+	if got := s.Commands(); len(got) != 2 || got[0] != InfoID || got[1] != InfoPageCount {
+		t.Errorf("commands sent = %q", got)
+	}
+}
+
+func TestQueryAllowlist(t *testing.T) {
+	s := newStandIn(t, laserJet)
+
+	for _, command := range []string{
+		"@PJL SET COPIES=2",
+		"@PJL DEFAULT LANG=FRENCH",
+		"@PJL INFO VARIABLES",
+		"@PJL RDYMSG DISPLAY=\"HELLO\"",
+		"@pjl info id",
+		InfoID + "\r\n@PJL SET COPIES=2",
+	} {
+		_, err := NewClient().Query(context.Background(), s.Address(), InfoID, command)
+		if !errors.Is(err, ErrNotAllowed) {
+			t.Errorf("%q: error = %v, want ErrNotAllowed", command, err)
+		}
+	}
+	if got := s.Commands(); len(got) != 0 {
+		t.Errorf("commands sent with a refused one = %q", got)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	// A printer that accepts the connection but never answers
+	s := newStandIn(t, nil)
+	client := NewClient()
+	client.timeout = 200 * time.Millisecond
+	if _, err := client.Query(context.Background(), s.Address(), InfoID); err == nil {
+		t.Error("Query of a silent printer succeeded")
+	}
+
+	// Nothing listening
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+	if err := NewClient().Probe(context.Background(), address); err == nil {
+		t.Error("Probe of a closed port succeeded")
+	}
+}
+
+func TestPollContext(t *testing.T) {
+	s := newStandIn(t, laserJet)
+
+	status, err := NewClient().PollContext(context.Background(), s.Address())
+	if err != nil {
+		t.Fatalf("PollContext: %v", err)
+	}
+	if status.Protocol != "pjl" || status.Model != "HP LaserJet 4250" || status.TotalPages != 184022 {
+		t.Errorf("protocol, model, pages = %s, %q, %d", status.Protocol, status.Model, status.TotalPages)
+	}
+	if status.Status != snmp.PrinterStopped || status.DeviceState != snmp.DeviceDown {
+		t.Errorf("state = %s, %s", status.Status, status.DeviceState)
+	}
+	if len(status.Covers) != 1 || !status.Covers[0].Open {
+		t.Errorf("covers = %+v", status.Covers)
+	}
+	if len(status.PaperTrays) != 3 || len(status.OutputBins) != 1 || !status.Capabilities.Duplex {
+		t.Fatalf("trays, bins, duplex = %d, %d, %t", len(status.PaperTrays), len(status.OutputBins), status.Capabilities.Duplex)
+	}
+	if tray := status.PaperTrays[0]; tray.Name != "INTRAY1 MP" || tray.Status != snmp.SubUnitUnknown || tray.Condition != "unknown" {
+		t.Errorf("tray 1 = %+v", tray)
+	}
+
+	want := []string{InfoID, InfoStatus, InfoPageCount, InfoConfig}
+	if got := s.Commands(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("commands sent = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,201 @@
+package pjl
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"lynk/agent/internal/deviceid"
+	"lynk/agent/internal/snmp"
+)
+
+// enumerated matches the header of an enumerated INFO CONFIG entry, e.g. "IN TRAYS [3 ENUMERATED]"
+var enumerated = regexp.MustCompile(`^(.+?)\s*\[(\d+)\s+[A-Z ]+\]$`)
+
+// Status maps the responses of the INFO queries into a PrinterStatus, so the
+// rest of the agent doesn't care which protocol was used
+func Status(host string, responses map[string]string, now time.Time) *snmp.PrinterStatus {
+	status := &snmp.PrinterStatus{
+		Host:     host,
+		Protocol: "pjl",
+		LastSeen: now,
+		Status:   "unknown",
+	}
+
+	applyID(status, responses[InfoID])
+	applyStatus(status, responses[InfoStatus])
+	applyPageCount(status, responses[InfoPageCount])
+	applyConfig(status, responses[InfoConfig])
+	return status
+}
+
+// applyID takes the model from INFO ID, a quoted model name or on some
+// printers a full IEEE 1284 device ID
+func applyID(status *snmp.PrinterStatus, response string) {
+	id := strings.Trim(strings.TrimSpace(response), `"`)
+	if id == "" {
+		return
+	}
+	if strings.Contains(id, ":") && strings.Contains(id, ";") {
+		if parsed := deviceid.Parse(id); parsed.Model != "" {
+			status.DeviceID = id
+			snmp.ApplyDeviceID(status, parsed)
+			for _, field := range []string{"device_id", "manufacturer", "model", "serial_number", "command_set"} {
+				status.From(field, InfoID)
+			}
+			return
+		}
+	}
+	status.Model = id
+	status.From("model", InfoID)
+}
+
+// values parses the NAME=VALUE lines of a response
+func values(response string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(response, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok {
+			fields[strings.ToUpper(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return fields
+}
+
+// applyStatus maps INFO STATUS, e.g. CODE=40021 DISPLAY="CLOSE DOOR" ONLINE=TRUE
+func applyStatus(status *snmp.PrinterStatus, response string) {
+	fields := values(response)
+	status.ActiveAlerts = []string{}
+	if display := fields["DISPLAY"]; display != "" {
+		status.DisplayLines = []string{display}
+	}
+
+	code, err := strconv.Atoi(fields["CODE"])
+	if err != nil {
+		return
+	}
+	status.Status, status.DeviceState = states(code)
+	if strings.EqualFold(fields["ONLINE"], "FALSE") {
+		status.Status = snmp.PrinterStopped
+	}
+	for _, field := range []string{"status", "device_state", "display_lines", "active_alerts", "error_count", "last_error", "covers", "paper_status"} {
+		status.From(field, InfoStatus)
+	}
+
+	if code >= 30000 || isWarning(code) {
+		message := "PJL status " + strconv.Itoa(code)
+		if display := fields["DISPLAY"]; display != "" {
+			message += ": " + display
+		}
+		status.ActiveAlerts = append(status.ActiveAlerts, message)
+		status.ErrorCount = 1
+		status.LastError = message
+	}
+
+	switch {
+	case code == 40021 || code == 40022:
+		status.Covers = []snmp.Cover{{Index: 1, Description: "Door", Status: 3, State: "open", Open: true}}
+	case code/1000 == 41:
+		status.PaperStatus = "paper_out"
+	case code/1000 == 42 || code/1000 == 44:
+		status.PaperStatus = "paper_jam"
+	case isWarning(code):
+		status.PaperStatus = "toner_low"
+	}
+}
+
+// states maps a PJL status code to the printer and device state
+func states(code int) (snmp.PrinterState, snmp.DeviceState) {
+	switch {
+	case code == 10003 || code == 10004:
+		return snmp.PrinterWarmup, snmp.DeviceRunning
+	case code == 10023 || code == 10024:
+		return snmp.PrinterPrinting, snmp.DeviceRunning
+	case isWarning(code):
+		return snmp.PrinterIdle, snmp.DeviceWarning
+	case code < 30000:
+		return snmp.PrinterIdle, snmp.DeviceRunning
+	case code < 40000:
+		return snmp.PrinterIdle, snmp.DeviceWarning // Auto-continuable warning
+	}
+	return snmp.PrinterStopped, snmp.DeviceDown // Operator intervention or a hardware error
+}
+
+// isWarning reports whether an informational code is a low supply warning
+func isWarning(code int) bool {
+	return code == 10006 || code == 10014
+}
+
+// applyPageCount maps INFO PAGECOUNT, either "PAGECOUNT=1234" or a bare number
+func applyPageCount(status *snmp.PrinterStatus, response string) {
+	text := strings.TrimSpace(response)
+	if value, ok := values(text)["PAGECOUNT"]; ok {
+		text = value
+	}
+	if pages, err := strconv.Atoi(strings.TrimSpace(text)); err == nil {
+		status.TotalPages = pages
+		status.From("total_pages", InfoPageCount)
+	}
+}
+
+// applyConfig maps INFO CONFIG: installed trays, duplex unit, languages and memory
+func applyConfig(status *snmp.PrinterStatus, response string) {
+	if strings.TrimSpace(response) == "" {
+		return
+	}
+	for _, field := range []string{"memory_size", "paper_trays", "output_bins", "capabilities"} {
+		status.From(field, InfoConfig)
+	}
+
+	lines := strings.Split(response, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if match := enumerated.FindStringSubmatch(line); match != nil {
+			count, _ := strconv.Atoi(match[2])
+			var items []string
+			for ; count > 0 && i+1 < len(lines); count-- {
+				i++
+				if item := strings.TrimSpace(lines[i]); item != "" {
+					items = append(items, item)
+				}
+			}
+			applyEnumeration(status, strings.ToUpper(match[1]), items)
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, "=")
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "DUPLEX":
+			status.Capabilities.Duplex = true
+		case "MEMORY":
+			status.MemorySize = strings.TrimSpace(value)
+		}
+	}
+}
+
+// applyEnumeration maps an enumerated INFO CONFIG entry
+func applyEnumeration(status *snmp.PrinterStatus, name string, items []string) {
+	switch name {
+	case "IN TRAYS":
+		status.PaperTrays = []snmp.PaperTray{}
+		for i, item := range items {
+			tray := snmp.PaperTray{Index: i + 1, Name: item, Status: snmp.SubUnitUnknown, Level: -2} // Level and status unknown
+			tray.Decode()
+			status.PaperTrays = append(status.PaperTrays, tray)
+		}
+	case "OUT TRAYS":
+		status.OutputBins = []snmp.OutputBin{}
+		for i, item := range items {
+			bin := snmp.OutputBin{Index: i + 1, Name: item, Status: snmp.SubUnitUnknown, Remaining: -2, StackingOrder: "unknown"}
+			bin.Decode()
+			status.OutputBins = append(status.OutputBins, bin)
+		}
+	case "LANGUAGES":
+		status.Capabilities.Languages = items
+	}
+}
@@ -0,0 +1,126 @@
+package pjl
+
+import (
+	"testing"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+func TestStatusInfoStatus(t *testing.T) {
+	tests := []struct {
+		response    string
+		state       snmp.PrinterState
+		deviceState snmp.DeviceState
+		alerts      int
+		paperStatus string
+	}{
+		{"CODE=10001\nDISPLAY=\"READY\"\nONLINE=TRUE", snmp.PrinterIdle, snmp.DeviceRunning, 0, ""},
+		{"CODE=10003\nDISPLAY=\"WARMING UP\"\nONLINE=TRUE", snmp.PrinterWarmup, snmp.DeviceRunning, 0, ""},
+		{"CODE=10023\nONLINE=TRUE", snmp.PrinterPrinting, snmp.DeviceRunning, 0, ""},
+		{"CODE=10006\nDISPLAY=\"TONER LOW\"\nONLINE=TRUE", snmp.PrinterIdle, snmp.DeviceWarning, 1, "toner_low"},
+		{"CODE=35078\nONLINE=TRUE", snmp.PrinterIdle, snmp.DeviceWarning, 1, ""},
+		{"CODE=41038\nDISPLAY=\"LOAD TRAY 2\"\nONLINE=TRUE", snmp.PrinterStopped, snmp.DeviceDown, 1, "paper_out"},
+		{"CODE=42005\nONLINE=TRUE", snmp.PrinterStopped, snmp.DeviceDown, 1, "paper_jam"},
+		{"CODE=10001\nONLINE=FALSE", snmp.PrinterStopped, snmp.DeviceRunning, 0, ""},
+		{"  code = 10001 \n online = true ", snmp.PrinterIdle, snmp.DeviceRunning, 0, ""},
+		{"DISPLAY=\"READY\"", snmp.PrinterUnknown, "", 0, ""}, // No code
+		{"", snmp.PrinterUnknown, "", 0, ""},
+	}
+	for _, test := range tests {
+		status := Status("printer", map[string]string{InfoStatus: test.response}, time.Now())
+		if status.Status != test.state || status.DeviceState != test.deviceState {
+			t.Errorf("%q: state %s, device state %q, want %s, %q", test.response, status.Status, status.DeviceState, test.state, test.deviceState)
+		}
+		if len(status.ActiveAlerts) != test.alerts || status.ErrorCount != test.alerts {
+			t.Errorf("%q: alerts %q, error count %d, want %d", test.response, status.ActiveAlerts, status.ErrorCount, test.alerts)
+		}
+		if status.PaperStatus != test.paperStatus {
+			t.Errorf("%q: paper status %q, want %q", test.response, status.PaperStatus, test.paperStatus)
+		}
+	}
+
+	status := Status("printer", map[string]string{InfoStatus: "CODE=40021\nDISPLAY=\"CLOSE DOOR\""}, time.Now())
+	if len(status.Covers) != 1 || !status.Covers[0].Open || status.LastError != "PJL status 40021: CLOSE DOOR" {
+		t.Errorf("door open: covers %+v, last error %q", status.Covers, status.LastError)
+	}
+	if len(status.DisplayLines) != 1 || status.DisplayLines[0] != "CLOSE DOOR" {
+		t.Errorf("display lines = %q", status.DisplayLines)
+	}
+}
+
+func TestStatusInfoID(t *testing.T) {
+	tests := []struct {
+		response     string
+		manufacturer string
+		model        string
+		deviceID     string
+	}{
+		{`"HP LaserJet 4250"`, "", "HP LaserJet 4250", ""},
+		{"LaserJet 1320", "", "LaserJet 1320", ""},
+		{`"MFG:Hewlett-Packard;MDL:HP LaserJet P3015;CMD:PJL,PCL,POSTSCRIPT;"`, "Hewlett-Packard", "HP LaserJet P3015", "MFG:Hewlett-Packard;MDL:HP LaserJet P3015;CMD:PJL,PCL,POSTSCRIPT;"},
+		{"MFG:Acme;CMD:PJL;", "", "MFG:Acme;CMD:PJL;", ""}, // No model: kept as it is
+		{"", "", "", ""},
+	}
+	for _, test := range tests {
+		status := Status("printer", map[string]string{InfoID: test.response}, time.Now())
+		if status.Manufacturer != test.manufacturer || status.Model != test.model || status.DeviceID != test.deviceID {
+			t.Errorf("%q: manufacturer %q, model %q, device ID %q, want %q, %q, %q", test.response,
+				status.Manufacturer, status.Model, status.DeviceID, test.manufacturer, test.model, test.deviceID)
+		}
+	}
+}
+
+func TestStatusInfoPageCount(t *testing.T) {
+	tests := []struct {
+		response string
+		pages    int
+	}{
+		{"PAGECOUNT=184022", 184022},
+		{"pagecount = 12", 12},
+		{"  4711  ", 4711},
+		{"PAGECOUNT=\"99\"", 99},
+		{"?", 0},
+		{"", 0},
+	}
+	for _, test := range tests {
+		status := Status("printer", map[string]string{InfoPageCount: test.response}, time.Now())
+		if status.TotalPages != test.pages {
+			t.Errorf("%q: total pages %d, want %d", test.response, status.TotalPages, test.pages)
+		}
+	}
+}
+
+func TestStatusInfoConfig(t *testing.T) {
+	status := Status("printer", map[string]string{InfoConfig: "IN TRAYS [2 ENUMERATED]\n\tINTRAY1\n\tINTRAY2\n" +
+		"OUT TRAYS [1 ENUMERATED]\n\tNORMAL FACEDOWN\n" +
+		"PAPER SIZES [2 ENUMERATED]\n\tLETTER\n\tA4\n" +
+		"LANGUAGES [3 ENUMERATED]\n\tPCL\n\tPOSTSCRIPT\n\tPDF\n" +
+		"DUPLEX\n" +
+		"MEMORY=65536KB\n" +
+		"DISPLAY LINES=2"}, time.Now())
+
+	if len(status.PaperTrays) != 2 || len(status.OutputBins) != 1 {
+		t.Fatalf("trays %+v, bins %+v", status.PaperTrays, status.OutputBins)
+	}
+	for _, tray := range status.PaperTrays {
+		if tray.Status != snmp.SubUnitUnknown || tray.State.Availability != "unknown" || tray.Level != -2 || tray.Condition != "unknown" {
+			t.Errorf("tray = %+v", tray)
+		}
+	}
+	if bin := status.OutputBins[0]; bin.Name != "NORMAL FACEDOWN" || bin.Status != snmp.SubUnitUnknown || bin.State.Availability != "unknown" {
+		t.Errorf("bin = %+v", bin)
+	}
+	if languages := status.Capabilities.Languages; len(languages) != 3 || languages[2] != "PDF" {
+		t.Errorf("languages = %q", languages)
+	}
+	if !status.Capabilities.Duplex || status.MemorySize != "65536KB" {
+		t.Errorf("duplex %t, memory %q", status.Capabilities.Duplex, status.MemorySize)
+	}
+
+	// A count larger than the listing stops at the end of the response
+	status = Status("printer", map[string]string{InfoConfig: "IN TRAYS [5 ENUMERATED]\n\tINTRAY1"}, time.Now())
+	if len(status.PaperTrays) != 1 {
+		t.Errorf("truncated listing: trays %+v", status.PaperTrays)
+	}
+}
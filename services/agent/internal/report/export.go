@@ -0,0 +1,72 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Formats a report can be exported as
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Write exports the report in the given format
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatCSV:
+		return r.WriteCSV(w)
+	case FormatJSON:
+		return r.WriteJSON(w)
+	default:
+		return fmt.Errorf("unknown format %q, expected csv or json", format)
+	}
+}
+
+// WriteJSON exports the full report, groups and devices, as JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV exports one row per device when grouped by device, otherwise one row per group
+func (r *Report) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	from := r.Period.From.Format("2006-01-02")
+	to := r.Period.To.Format("2006-01-02")
+
+	if r.By == ByDevice {
+		out.Write([]string{"period_from", "period_to", "host", "name", "site", "department", "tags",
+			"model", "serial_number", "start_pages", "end_pages", "pages", "color_pages", "mono_pages",
+			"resets", "wraps", "replacements"})
+		for _, d := range r.Devices {
+			color, mono := splitColumns(d.ColorPages, d.MonoPages)
+			out.Write([]string{from, to, d.Host, d.Name, d.Site, d.Department, strings.Join(d.Tags, ";"),
+				d.Model, d.SerialNumber, strconv.Itoa(d.StartPages), strconv.Itoa(d.EndPages), strconv.Itoa(d.Pages),
+				color, mono, strconv.Itoa(d.Resets), strconv.Itoa(d.Wraps), strconv.Itoa(d.Replacements)})
+		}
+	} else {
+		out.Write([]string{"period_from", "period_to", r.By, "devices", "pages", "color_pages", "mono_pages"})
+		for _, g := range r.Groups {
+			color, mono := splitColumns(g.ColorPages, g.MonoPages)
+			out.Write([]string{from, to, g.Group, strconv.Itoa(g.Devices), strconv.Itoa(g.Pages), color, mono})
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// splitColumns formats the color and mono pages, both empty when the
+// printers didn't split their count
+func splitColumns(color, mono int) (string, string) {
+	if color == 0 && mono == 0 {
+		return "", ""
+	}
+	return strconv.Itoa(color), strconv.Itoa(mono)
+}
@@ -0,0 +1,237 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"lynk/agent/internal/config"
+	"lynk/agent/internal/events"
+	"lynk/agent/internal/snmp"
+	"lynk/agent/internal/store"
+)
+
+// Groupings a usage report can be aggregated by
+const (
+	ByDevice     = "device"
+	BySite       = "site"
+	ByDepartment = "department"
+	ByTag        = "tag"
+)
+
+// unassigned is the group of devices without the grouping's metadata
+const unassigned = "(unassigned)"
+
+// Period is a reporting period, From inclusive and To exclusive
+type Period struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Month returns the calendar month containing t, in t's location
+func Month(t time.Time) Period {
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return Period{From: from, To: from.AddDate(0, 1, 0)}
+}
+
+// ParseMonth parses a month such as "2026-09" in the local time zone
+func ParseMonth(month string) (Period, error) {
+	t, err := time.ParseInLocation("2006-01", month, time.Local)
+	if err != nil {
+		return Period{}, fmt.Errorf("invalid month %q, expected YYYY-MM", month)
+	}
+	return Month(t), nil
+}
+
+// DeviceUsage is the number of pages a single printer printed in a period
+type DeviceUsage struct {
+	Host         string   `json:"host"`
+	Name         string   `json:"name,omitempty"`
+	Site         string   `json:"site,omitempty"`
+	Department   string   `json:"department,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Model        string   `json:"model,omitempty"`
+	SerialNumber string   `json:"serial_number,omitempty"`
+	StartPages   int      `json:"start_pages"` // Counter reading at the start of the period
+	EndPages     int      `json:"end_pages"`   // Counter reading at the end of the period
+	Pages        int      `json:"pages"`
+	ColorPages   int      `json:"color_pages,omitempty"`  // Pages printed in color, for printers that split their count
+	MonoPages    int      `json:"mono_pages,omitempty"`   // Pages printed in black and white
+	Resets       int      `json:"resets,omitempty"`       // Counter went back to zero, e.g. after a board replacement
+	Wraps        int      `json:"wraps,omitempty"`        // Counter32 rolled over
+	Replacements int      `json:"replacements,omitempty"` // Serial number changed, the printer was swapped
+	Samples      int      `json:"samples"`
+}
+
+// GroupUsage is the number of pages a group of printers printed in a period
+type GroupUsage struct {
+	Group      string `json:"group"`
+	Devices    int    `json:"devices"`
+	Pages      int    `json:"pages"`
+	ColorPages int    `json:"color_pages,omitempty"` // Sum over the devices that split their count
+	MonoPages  int    `json:"mono_pages,omitempty"`
+}
+
+// Report is a page usage report for a period
+type Report struct {
+	Period  Period        `json:"period"`
+	By      string        `json:"by"`
+	Groups  []GroupUsage  `json:"groups"`
+	Devices []DeviceUsage `json:"devices"`
+}
+
+// Usage builds a page usage report from the poll history, grouped by one of
+// ByDevice, BySite, ByDepartment or ByTag
+func Usage(history *store.Store, cfg *config.Config, period Period, by string) (*Report, error) {
+	switch by {
+	case ByDevice, BySite, ByDepartment, ByTag:
+	default:
+		return nil, fmt.Errorf("unknown grouping %q, expected device, site, department or tag", by)
+	}
+
+	report := &Report{Period: period, By: by, Devices: []DeviceUsage{}}
+	for _, host := range history.Hosts() {
+		usage, ok := deviceUsage(history.History(host), period)
+		if !ok {
+			continue
+		}
+
+		target := cfg.Target(host)
+		usage.Host = host
+		usage.Name = target.Name
+		usage.Site = target.Site
+		usage.Department = target.Department
+		usage.Tags = target.Tags
+		report.Devices = append(report.Devices, usage)
+	}
+
+	report.Groups = aggregate(report.Devices, by)
+	return report, nil
+}
+
+// deviceUsage sums the page deltas of a printer's samples within period. The
+// last sample before the period is used as the baseline when there is one.
+// The color and mono split is summed over the pairs of samples that both
+// have it; SNMP polls don't, since no vendor counters for it are read yet.
+func deviceUsage(samples []*snmp.PrinterStatus, period Period) (DeviceUsage, bool) {
+	var usage DeviceUsage
+	var previous *snmp.PrinterStatus
+	var counted *snmp.PrinterStatus // Latest sample that read the page counter, the baseline of the next
+
+	for _, sample := range samples {
+		reported := events.PagesReported(sample)
+		if sample.LastSeen.Before(period.From) {
+			previous = sample
+			if reported {
+				counted = sample
+			}
+			continue
+		}
+		if !sample.LastSeen.Before(period.To) {
+			break
+		}
+
+		usage.Samples++
+		usage.Model = sample.Model
+		usage.SerialNumber = sample.SerialNumber
+
+		swapped := false
+		if previous != nil {
+			for _, event := range events.Detect(previous, sample) {
+				if event.Type == events.DeviceSwapped {
+					usage.Replacements++
+					swapped = true
+				}
+			}
+			if split(previous) && split(sample) && !swapped {
+				usage.ColorPages += counterDelta(previous.ColorPages, sample.ColorPages)
+				usage.MonoPages += counterDelta(previous.MonoPages, sample.MonoPages)
+			}
+		}
+		previous = sample
+		if !reported {
+			continue // Nothing to count, the next sample is compared with counted
+		}
+
+		usage.EndPages = sample.TotalPages
+		if counted == nil {
+			usage.StartPages = sample.TotalPages
+			counted = sample
+			continue
+		}
+		if usage.StartPages == 0 {
+			usage.StartPages = counted.TotalPages
+		}
+		delta, event := events.PageDelta(counted, sample)
+		usage.Pages += delta
+		if event != nil {
+			switch event.Type {
+			case events.CounterReset:
+				usage.Resets++
+			case events.CounterWrap:
+				usage.Wraps++
+			}
+		}
+		counted = sample
+	}
+	return usage, usage.Samples > 0
+}
+
+// split reports whether a sample has its page count split by color mode
+func split(sample *snmp.PrinterStatus) bool {
+	return sample.ColorPages > 0 || sample.MonoPages > 0
+}
+
+// counterDelta is the increase of a counter that restarts from zero when it
+// goes backwards. The split counters are IPP integers, which don't wrap.
+func counterDelta(previous, current int) int {
+	if current >= previous {
+		return current - previous
+	}
+	return current
+}
+
+// aggregate sums the device usage into groups
+func aggregate(devices []DeviceUsage, by string) []GroupUsage {
+	totals := make(map[string]*GroupUsage)
+	add := func(group string, usage DeviceUsage) {
+		if group == "" {
+			group = unassigned
+		}
+		if totals[group] == nil {
+			totals[group] = &GroupUsage{Group: group}
+		}
+		totals[group].Devices++
+		totals[group].Pages += usage.Pages
+		totals[group].ColorPages += usage.ColorPages
+		totals[group].MonoPages += usage.MonoPages
+	}
+
+	for _, usage := range devices {
+		switch by {
+		case ByDevice:
+			add(usage.Host, usage)
+		case BySite:
+			add(usage.Site, usage)
+		case ByDepartment:
+			add(usage.Department, usage)
+		case ByTag:
+			// A device counts towards every tag it carries
+			if len(usage.Tags) == 0 {
+				add("", usage)
+			}
+			for _, tag := range usage.Tags {
+				add(tag, usage)
+			}
+		}
+	}
+
+	groups := make([]GroupUsage, 0, len(totals))
+	for _, group := range totals {
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Group < groups[j].Group
+	})
+	return groups
+}
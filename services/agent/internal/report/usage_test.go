@@ -0,0 +1,211 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"lynk/agent/internal/config"
+	"lynk/agent/internal/snmp"
+	"lynk/agent/internal/store"
+)
+
+// march is the period the tests report on
+var march = Month(time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC))
+
+func sample(host, serial string, day, pages int) *snmp.PrinterStatus {
+	return &snmp.PrinterStatus{
+		Host:         host,
+		SerialNumber: serial,
+		Model:        "LaserJet",
+		TotalPages:   pages,
+		LastSeen:     time.Date(2026, 3, day, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func colorSample(host, serial string, day, color, mono int) *snmp.PrinterStatus {
+	s := sample(host, serial, day, color+mono)
+	s.ColorPages, s.MonoPages = color, mono
+	return s
+}
+
+func history() *store.Store {
+	history := store.New(store.DefaultMaxSamples)
+	for _, s := range []*snmp.PrinterStatus{
+		// Reset to zero on the 10th, the sample before March is the baseline
+		sample("a", "A1", 0, 1000), // February 28th
+		sample("a", "A1", 5, 1500),
+		sample("a", "A1", 10, 200),
+		sample("a", "A1", 20, 700),
+		sample("a", "A1", 32, 900), // April 1st
+
+		// Counter32 wrap
+		sample("b", "B1", 1, 4294967000),
+		sample("b", "B1", 2, 204),
+
+		// Swapped for another color printer on the 3rd
+		colorSample("c", "C1", 1, 1000, 4000),
+		colorSample("c", "C1", 2, 1030, 4070),
+		colorSample("c", "C2", 3, 100, 200),
+		colorSample("c", "C2", 4, 110, 290),
+
+		sample("d", "D1", 15, 50),
+		sample("e", "E1", 33, 50), // Only polled in April
+	} {
+		history.Add(s)
+	}
+	return history
+}
+
+var fleet = &config.Config{Targets: []config.Target{
+	{Host: "a", Name: "Reception", Site: "HQ", Department: "Finance"},
+	{Host: "b", Site: "HQ", Department: "Finance"},
+	{Host: "c", Site: "Annex", Department: "Sales", Tags: []string{"color", "floor2"}},
+}}
+
+func TestUsageDevices(t *testing.T) {
+	report, err := Usage(history(), fleet, march, ByDevice)
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+
+	want := map[string]DeviceUsage{
+		"a": {StartPages: 1000, EndPages: 700, Pages: 1200, Resets: 1, Samples: 3},
+		"b": {StartPages: 4294967000, EndPages: 204, Pages: 500, Wraps: 1, Samples: 2},
+		"c": {StartPages: 5000, EndPages: 400, Pages: 200, ColorPages: 40, MonoPages: 160, Replacements: 1, Samples: 4},
+		"d": {StartPages: 50, EndPages: 50, Pages: 0, Samples: 1},
+	}
+	if len(report.Devices) != len(want) {
+		t.Fatalf("devices = %+v", report.Devices)
+	}
+	for _, got := range report.Devices {
+		w, ok := want[got.Host]
+		if !ok {
+			t.Errorf("unexpected device %s", got.Host)
+			continue
+		}
+		if got.StartPages != w.StartPages || got.EndPages != w.EndPages || got.Pages != w.Pages ||
+			got.ColorPages != w.ColorPages || got.MonoPages != w.MonoPages ||
+			got.Resets != w.Resets || got.Wraps != w.Wraps || got.Replacements != w.Replacements || got.Samples != w.Samples {
+			t.Errorf("%s = %+v, want %+v", got.Host, got, w)
+		}
+	}
+	if a := report.Devices[0]; a.Host != "a" || a.Name != "Reception" || a.Department != "Finance" || a.SerialNumber != "A1" {
+		t.Errorf("metadata = %+v", a)
+	}
+}
+
+func TestUsageMissingCount(t *testing.T) {
+	history := store.New(store.DefaultMaxSamples)
+	history.Add(sample("f", "F1", 0, 1000))
+	history.Add(sample("f", "F1", 2, 0)) // The page counter wasn't read
+	history.Add(sample("f", "F1", 4, 1100))
+	history.Add(sample("f", "F1", 6, 0))
+
+	report, err := Usage(history, fleet, march, ByDevice)
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	want := DeviceUsage{StartPages: 1000, EndPages: 1100, Pages: 100, Samples: 3}
+	if len(report.Devices) != 1 {
+		t.Fatalf("devices = %+v", report.Devices)
+	}
+	if got := report.Devices[0]; got.StartPages != want.StartPages || got.EndPages != want.EndPages ||
+		got.Pages != want.Pages || got.Resets != 0 || got.Samples != want.Samples {
+		t.Errorf("f = %+v, want %+v", got, want)
+	}
+}
+
+func TestUsageGroups(t *testing.T) {
+	tests := []struct {
+		by   string
+		want []GroupUsage
+	}{
+		{ByDepartment, []GroupUsage{
+			{Group: "(unassigned)", Devices: 1},
+			{Group: "Finance", Devices: 2, Pages: 1700},
+			{Group: "Sales", Devices: 1, Pages: 200, ColorPages: 40, MonoPages: 160},
+		}},
+		{BySite, []GroupUsage{
+			{Group: "(unassigned)", Devices: 1},
+			{Group: "Annex", Devices: 1, Pages: 200, ColorPages: 40, MonoPages: 160},
+			{Group: "HQ", Devices: 2, Pages: 1700},
+		}},
+		{ByTag, []GroupUsage{
+			{Group: "(unassigned)", Devices: 3, Pages: 1700},
+			{Group: "color", Devices: 1, Pages: 200, ColorPages: 40, MonoPages: 160},
+			{Group: "floor2", Devices: 1, Pages: 200, ColorPages: 40, MonoPages: 160},
+		}},
+	}
+	for _, test := range tests {
+		report, err := Usage(history(), fleet, march, test.by)
+		if err != nil {
+			t.Fatalf("%s: %v", test.by, err)
+		}
+		if len(report.Groups) != len(test.want) {
+			t.Errorf("%s: groups = %+v, want %+v", test.by, report.Groups, test.want)
+			continue
+		}
+		for i := range test.want {
+			if report.Groups[i] != test.want[i] {
+				t.Errorf("%s: group %d = %+v, want %+v", test.by, i, report.Groups[i], test.want[i])
+			}
+		}
+	}
+
+	if _, err := Usage(history(), fleet, march, "building"); err == nil {
+		t.Error("unknown grouping accepted")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	report, _ := Usage(history(), fleet, march, ByDevice)
+	var buf bytes.Buffer
+	if err := report.Write(&buf, FormatCSV); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 5 || strings.Join(rows[0][:4], ",") != "period_from,period_to,host,name" {
+		t.Fatalf("rows = %q", rows)
+	}
+	column := func(name string) int {
+		for i, header := range rows[0] {
+			if header == name {
+				return i
+			}
+		}
+		t.Fatalf("no %s column", name)
+		return -1
+	}
+	color, mono, pages := column("color_pages"), column("mono_pages"), column("pages")
+	if a := rows[1]; a[0] != "2026-03-01" || a[1] != "2026-04-01" || a[pages] != "1200" || a[color] != "" || a[mono] != "" {
+		t.Errorf("device without a split = %q", a)
+	}
+	if c := rows[3]; c[2] != "c" || c[color] != "40" || c[mono] != "160" || c[column("tags")] != "color;floor2" {
+		t.Errorf("device with a split = %q", c)
+	}
+
+	if err := report.Write(&buf, "xlsx"); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
+
+func TestParseMonth(t *testing.T) {
+	period, err := ParseMonth("2026-12")
+	if err != nil {
+		t.Fatalf("ParseMonth: %v", err)
+	}
+	if period.From.Month() != time.December || period.To.Year() != 2027 || period.To.Month() != time.January {
+		t.Errorf("period = %+v", period)
+	}
+	for _, month := range []string{"2026-13", "12/2026", ""} {
+		if _, err := ParseMonth(month); err == nil {
+			t.Errorf("ParseMonth(%q) succeeded", month)
+		}
+	}
+}
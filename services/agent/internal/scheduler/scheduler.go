@@ -1,16 +1,58 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// ErrClosed is returned when a job is submitted after the scheduler was closed
+var ErrClosed = errors.New("scheduler: closed")
+
 // Scheduler manages concurrent job execution with a worker pool
 type Scheduler struct {
 	workers    int
 	jobQueue   chan func()
+	done       chan struct{}  // Closed by Close to release blocked submitters
+	sending    sync.WaitGroup // Submits between the closed check and their send
 	wg         sync.WaitGroup
 	started    bool
-	mu         sync.Mutex
+	closed     bool
+	mu         sync.Mutex // Guards started and closed, never held across a send
+	jobTimeout atomic.Int64
+
+	submitted atomic.Uint64
+	completed atomic.Uint64
+	failed    atomic.Uint64
+	timedOut  atomic.Uint64
+	panicked  atomic.Uint64
+	abandoned atomic.Int64
+}
+
+// Stats counts the jobs a scheduler has seen
+type Stats struct {
+	Submitted uint64 `json:"submitted"`
+	Completed uint64 `json:"completed"` // Finished, successfully or not
+	Failed    uint64 `json:"failed"`    // Typed tasks that returned an error
+	TimedOut  uint64 `json:"timed_out"` // Typed tasks that exceeded the job timeout
+	Panicked  uint64 `json:"panicked"`  // Jobs that panicked
+	Abandoned uint64 `json:"abandoned"` // Timed out typed tasks still running, at most one per worker
+}
+
+// PanicError is the error reported for a job that panicked
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error implements the error interface
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("job panicked: %v", e.Value)
 }
 
 // New creates a new scheduler with the specified number of workers
@@ -18,21 +60,54 @@ func New(workers int) *Scheduler {
 	return &Scheduler{
 		workers:  workers,
 		jobQueue: make(chan func(), workers*2), // Buffer for better performance
+		done:     make(chan struct{}),
 	}
 }
 
-// Submit adds a job to the scheduler
-func (s *Scheduler) Submit(job func()) {
+// SetJobTimeout sets the deadline given to each typed task. Zero means no deadline.
+func (s *Scheduler) SetJobTimeout(timeout time.Duration) {
+	s.jobTimeout.Store(int64(timeout))
+}
+
+// Stats returns a snapshot of the job counters
+func (s *Scheduler) Stats() Stats {
+	return Stats{
+		Submitted: s.submitted.Load(),
+		Completed: s.completed.Load(),
+		Failed:    s.failed.Load(),
+		TimedOut:  s.timedOut.Load(),
+		Panicked:  s.panicked.Load(),
+		Abandoned: uint64(max(s.abandoned.Load(), 0)),
+	}
+}
+
+// Submit adds a job to the scheduler, blocking while the queue is full. It
+// returns ErrClosed once Close has been called, including to a Submit that
+// was still waiting for room in the queue.
+func (s *Scheduler) Submit(job func()) error {
 	s.mu.Lock()
-	if !s.started {
-		s.start()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
 	}
+	s.start()
+	// Close waits for sending before it closes the queue, so the send below
+	// never hits a closed channel even though the lock is released
+	s.sending.Add(1)
+	s.wg.Add(1)
 	s.mu.Unlock()
+	defer s.sending.Done()
 
-	s.wg.Add(1)
-	s.jobQueue <- func() {
+	select {
+	case s.jobQueue <- func() {
 		defer s.wg.Done()
 		job()
+	}:
+		s.submitted.Add(1)
+		return nil
+	case <-s.done:
+		s.wg.Done()
+		return ErrClosed
 	}
 }
 
@@ -51,16 +126,59 @@ func (s *Scheduler) start() {
 // worker processes jobs from the queue
 func (s *Scheduler) worker() {
 	for job := range s.jobQueue {
-		job()
+		s.run(job)
 	}
 }
 
+// run executes a single job, recovering a panic so it can't take the worker down
+func (s *Scheduler) run(job func()) {
+	defer s.completed.Add(1)
+	defer func() {
+		if r := recover(); r != nil {
+			s.panicked.Add(1)
+			log.Printf("scheduler: job panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+	job()
+}
+
 // Wait blocks until all submitted jobs are completed
 func (s *Scheduler) Wait() {
 	s.wg.Wait()
 }
 
-// Close shuts down the scheduler
+// Close stops accepting new jobs. Jobs that were already submitted still run;
+// submits blocked on a full queue return ErrClosed.
 func (s *Scheduler) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.done)
+	s.mu.Unlock()
+
+	// Workers keep draining the queue meanwhile, so this doesn't block for long
+	s.sending.Wait()
 	close(s.jobQueue)
 }
+
+// Shutdown closes the scheduler and waits for submitted jobs to finish.
+// It returns ctx.Err() if the jobs are still running when ctx is done.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.Close()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,200 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// within fails the test if fn doesn't return in time, e.g. on a deadlock
+func within(t *testing.T, d time.Duration, name string, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+	case <-time.After(d):
+		t.Fatalf("%s still blocked after %s", name, d)
+	}
+}
+
+func TestSubmitAfterClose(t *testing.T) {
+	s := New(2)
+	s.Close()
+	s.Close() // Closing twice is fine
+
+	if err := s.Submit(func() {}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit after Close = %v, want ErrClosed", err)
+	}
+	if _, err := SubmitTask(context.Background(), s, func(context.Context) (int, error) { return 1, nil }).Wait(); !errors.Is(err, ErrClosed) {
+		t.Errorf("SubmitTask after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestShutdownFullQueue(t *testing.T) {
+	s := New(1) // One worker and room for two queued jobs
+	s.SetJobTimeout(time.Second)
+
+	release := make(chan struct{})
+	var ran atomic.Int32
+	for i := 0; i < 3; i++ { // One running, two queued
+		if err := s.Submit(func() {
+			<-release
+			ran.Add(1)
+		}); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+
+	// These block on the full queue until Close releases them
+	var blocked sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 2; i++ {
+		blocked.Add(1)
+		go func() {
+			defer blocked.Done()
+			errs <- s.Submit(func() { ran.Add(1) })
+		}()
+		blocked.Add(1)
+		go func() {
+			defer blocked.Done()
+			_, err := SubmitTask(context.Background(), s, func(context.Context) (int, error) {
+				ran.Add(1)
+				return 0, nil
+			}).Wait()
+			errs <- err
+		}()
+	}
+	// Change the timeout while submits are blocked, as a config reload would
+	go s.SetJobTimeout(2 * time.Second)
+	time.Sleep(20 * time.Millisecond)
+
+	shutdown := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdown <- s.Shutdown(ctx)
+	}()
+
+	within(t, 2*time.Second, "blocked Submit", blocked.Wait)
+	close(errs)
+	for err := range errs {
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("blocked Submit = %v, want ErrClosed", err)
+		}
+	}
+
+	close(release)
+	within(t, 2*time.Second, "Shutdown", func() {
+		if err := <-shutdown; err != nil {
+			t.Errorf("Shutdown = %v", err)
+		}
+	})
+	if got := ran.Load(); got != 3 {
+		t.Errorf("%d jobs ran, want the 3 queued before Close", got)
+	}
+	if stats := s.Stats(); stats.Submitted != 3 || stats.Completed != 3 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	s := New(1)
+	release := make(chan struct{})
+	defer close(release)
+	s.Submit(func() { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestSubmitTask(t *testing.T) {
+	s := New(2)
+	defer s.Shutdown(context.Background())
+	s.SetJobTimeout(50 * time.Millisecond)
+
+	value, err := SubmitTask(context.Background(), s, func(context.Context) (string, error) { return "ok", nil }).Wait()
+	if value != "ok" || err != nil {
+		t.Errorf("task = %q, %v", value, err)
+	}
+
+	_, err = SubmitTask(context.Background(), s, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}).Wait()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("slow task = %v, want DeadlineExceeded", err)
+	}
+
+	_, err = SubmitTask(context.Background(), s, func(context.Context) (int, error) { panic("boom") }).Wait()
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+		t.Errorf("panicking task = %v, want a PanicError", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = SubmitTask(ctx, s, func(context.Context) (int, error) { return 1, nil }).Wait()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled task = %v, want Canceled", err)
+	}
+
+	stats := s.Stats()
+	if stats.Failed != 2 || stats.TimedOut != 1 || stats.Panicked != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestAbandonedTasksBounded(t *testing.T) {
+	const workers = 2
+	s := New(workers)
+	s.SetJobTimeout(10 * time.Millisecond)
+
+	// Tasks that ignore their context and hang until released
+	release := make(chan struct{})
+	var futures []*Future[int]
+	for i := 0; i < 2*workers; i++ {
+		futures = append(futures, SubmitTask(context.Background(), s, func(context.Context) (int, error) {
+			<-release
+			return 0, nil
+		}))
+	}
+
+	// The first tasks are abandoned at their deadline; once every worker has
+	// left one behind, the rest hold their worker until they return
+	for _, f := range futures[:workers] {
+		within(t, time.Second, "abandoned task", func() {
+			if _, err := f.Wait(); !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("task = %v, want DeadlineExceeded", err)
+			}
+		})
+	}
+	time.Sleep(50 * time.Millisecond)
+	for _, f := range futures[workers:] {
+		select {
+		case <-f.Done():
+			t.Error("task past the abandon limit resolved before it returned")
+		default:
+		}
+	}
+	if got := s.Stats().Abandoned; got != workers {
+		t.Errorf("abandoned = %d, want %d", got, workers)
+	}
+
+	close(release)
+	within(t, time.Second, "Shutdown", func() { s.Shutdown(context.Background()) })
+	within(t, time.Second, "abandoned count", func() {
+		for s.Stats().Abandoned != 0 {
+			time.Sleep(time.Millisecond)
+		}
+	})
+}
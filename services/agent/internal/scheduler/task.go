@@ -0,0 +1,183 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Task is a unit of work that produces a typed value
+type Task[T any] func(ctx context.Context) (T, error)
+
+// Result holds the outcome and timing of a finished task
+type Result[T any] struct {
+	Name     string        // Name given to Stream.Submit, empty for SubmitTask
+	Value    T             // Value returned by the task
+	Err      error         // Error returned by the task, or the context error if it never ran
+	Started  time.Time     // When a worker picked the task up
+	Duration time.Duration // How long the task ran
+}
+
+// Future is the pending result of a task submitted to a scheduler
+type Future[T any] struct {
+	done   chan struct{}
+	result Result[T]
+}
+
+// Done returns a channel that is closed once the task has finished
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the task has finished and returns its value and error
+func (f *Future[T]) Wait() (T, error) {
+	<-f.done
+	return f.result.Value, f.result.Err
+}
+
+// Result blocks until the task has finished and returns its full result
+func (f *Future[T]) Result() Result[T] {
+	<-f.done
+	return f.result
+}
+
+// SubmitTask schedules a typed task and returns a future for its result.
+// If ctx is done before a worker picks the task up, the task is skipped and
+// the future resolves with the context error. If the scheduler is closed the
+// future resolves with ErrClosed. A task that panics resolves with a *PanicError.
+func SubmitTask[T any](ctx context.Context, s *Scheduler, task Task[T]) *Future[T] {
+	return submitNamed(ctx, s, "", task)
+}
+
+// submitNamed schedules a task whose result carries the given name
+func submitNamed[T any](ctx context.Context, s *Scheduler, name string, task Task[T]) *Future[T] {
+	f := &Future[T]{
+		done:   make(chan struct{}),
+		result: Result[T]{Name: name},
+	}
+
+	err := s.Submit(func() {
+		defer close(f.done)
+
+		f.result.Started = time.Now()
+		if err := ctx.Err(); err != nil {
+			f.result.Err = err
+			return
+		}
+		f.result.Value, f.result.Err = runTask(ctx, s, task)
+		f.result.Duration = time.Since(f.result.Started)
+	})
+	if err != nil {
+		f.result.Err = err
+		close(f.done)
+	}
+	return f
+}
+
+// runTask runs a task under the job timeout. The task runs on its own
+// goroutine so a task that ignores its context only holds the worker until
+// the deadline, not forever. Go can't stop a goroutine, so such a task keeps
+// running after it was abandoned. To bound the leak, at most one abandoned
+// task per worker is left behind; past that the worker waits for the task
+// to return, so hung tasks slow the pool down instead of piling up.
+func runTask[T any](ctx context.Context, s *Scheduler, task Task[T]) (T, error) {
+	if timeout := time.Duration(s.jobTimeout.Load()); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		value T
+		err   error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		var out outcome
+		defer func() {
+			if r := recover(); r != nil {
+				stack := debug.Stack()
+				s.panicked.Add(1)
+				log.Printf("scheduler: task panicked: %v\n%s", r, stack)
+				out.err = &PanicError{Value: r, Stack: stack}
+			}
+			done <- out
+		}()
+		out.value, out.err = task(ctx)
+	}()
+
+	select {
+	case out := <-done:
+		if out.err != nil {
+			s.failed.Add(1)
+			if errors.Is(out.err, context.DeadlineExceeded) && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				s.timedOut.Add(1)
+			}
+		}
+		return out.value, out.err
+	case <-ctx.Done():
+		var zero T
+		s.failed.Add(1)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			s.timedOut.Add(1)
+		}
+		if s.abandoned.Add(1) > int64(s.workers) {
+			s.abandoned.Add(-1)
+			<-done
+			return zero, ctx.Err()
+		}
+		go func() {
+			<-done
+			s.abandoned.Add(-1)
+		}()
+		return zero, ctx.Err()
+	}
+}
+
+// Stream delivers the results of many tasks in completion order
+type Stream[T any] struct {
+	ctx       context.Context
+	scheduler *Scheduler
+	results   chan Result[T]
+	pending   sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewStream creates a result stream that runs its tasks on s
+func NewStream[T any](ctx context.Context, s *Scheduler) *Stream[T] {
+	return &Stream[T]{
+		ctx:       ctx,
+		scheduler: s,
+		results:   make(chan Result[T]),
+	}
+}
+
+// Submit schedules a named task whose result will be delivered on Results
+func (st *Stream[T]) Submit(name string, task Task[T]) {
+	st.pending.Add(1)
+	f := submitNamed(st.ctx, st.scheduler, name, task)
+
+	// Forward from a separate goroutine so a slow reader never blocks a worker
+	go func() {
+		defer st.pending.Done()
+		st.results <- f.Result()
+	}()
+}
+
+// Close marks the end of submissions. Results is closed once every task has been delivered.
+func (st *Stream[T]) Close() {
+	st.closeOnce.Do(func() {
+		go func() {
+			st.pending.Wait()
+			close(st.results)
+		}()
+	})
+}
+
+// Results returns the channel the task results are delivered on
+func (st *Stream[T]) Results() <-chan Result[T] {
+	return st.results
+}
@@ -0,0 +1,37 @@
+package schema
+
+import (
+	_ "embed"
+	"fmt"
+	"strconv"
+)
+
+// Versions of the printer JSON the API can serve
+const (
+	V1      = 1 // The flat PrinterStatus, kept for consumers that haven't moved on
+	V2      = 2 // Nested objects, unknown values omitted
+	Current = V2
+)
+
+//go:embed printer.v2.json
+var document []byte
+
+// Document returns the JSON Schema describing the v2 printer JSON
+func Document() []byte {
+	return document
+}
+
+// ParseVersion parses a schema version such as "2" or "v1". Empty selects fallback.
+func ParseVersion(text string, fallback int) (int, error) {
+	if text == "" {
+		return fallback, nil
+	}
+	if text[0] == 'v' {
+		text = text[1:]
+	}
+	version, err := strconv.Atoi(text)
+	if err != nil || version < V1 || version > Current {
+		return 0, fmt.Errorf("unknown schema version %q, expected 1 or 2", text)
+	}
+	return version, nil
+}
@@ -0,0 +1,373 @@
+package schema
+
+import (
+	"time"
+
+	"lynk/agent/internal/health"
+	"lynk/agent/internal/snmp"
+)
+
+// Printer is a printer snapshot in the v2 schema. Values the printer didn't
+// report are omitted rather than zero.
+type Printer struct {
+	SchemaVersion int            `json:"schema_version"`
+	Host          string         `json:"host"`
+	Protocol      string         `json:"protocol,omitempty"`  // snmp, ipp or pjl
+	LastSeen      *time.Time     `json:"last_seen,omitempty"` // Never polled successfully when omitted
+	Identity      Identity       `json:"identity"`
+	Network       Network        `json:"network"`
+	Status        Status         `json:"status"`
+	Counters      Counters       `json:"counters"`
+	Supplies      []Supply       `json:"supplies"`
+	Trays         []Tray         `json:"trays"`
+	OutputBins    []OutputBin    `json:"output_bins"`
+	Covers        []snmp.Cover   `json:"covers"`
+	Alerts        Alerts         `json:"alerts"`
+	Console       *Console       `json:"console,omitempty"`
+	Capabilities  *Capabilities  `json:"capabilities,omitempty"`
+	Resources     *Resources     `json:"resources,omitempty"`
+	Reachability  *health.Target `json:"reachability,omitempty"`
+}
+
+// Identity is what the printer is and where it stands
+type Identity struct {
+	Manufacturer      string   `json:"manufacturer,omitempty"`
+	Model             string   `json:"model,omitempty"`
+	SerialNumber      string   `json:"serial_number,omitempty"`
+	FirmwareVersion   string   `json:"firmware_version,omitempty"`
+	DeviceName        string   `json:"device_name,omitempty"`
+	PrinterName       string   `json:"printer_name,omitempty"`
+	SystemDescription string   `json:"system_description,omitempty"`
+	Location          string   `json:"location,omitempty"`
+	Contact           string   `json:"contact,omitempty"`
+	ObjectID          string   `json:"object_id,omitempty"`
+	Vendor            string   `json:"vendor,omitempty"`
+	UUID              string   `json:"uuid,omitempty"`
+	DeviceID          string   `json:"device_id,omitempty"`
+	CommandSet        []string `json:"command_set,omitempty"`
+}
+
+// Network is how the printer is attached
+type Network struct {
+	MACAddress string                  `json:"mac_address,omitempty"`
+	Interfaces []snmp.NetworkInterface `json:"interfaces,omitempty"`
+}
+
+// Status is the printer's state and its health rollup
+type Status struct {
+	State         snmp.PrinterState `json:"state"`
+	DeviceState   snmp.DeviceState  `json:"device_state"`
+	Health        snmp.Health       `json:"health"`
+	UptimeSeconds *float64          `json:"uptime_seconds,omitempty"`
+}
+
+// Counters are the printer's usage counters
+type Counters struct {
+	TotalPages     *int   `json:"total_pages,omitempty"`
+	MonotonicPages *int64 `json:"monotonic_pages,omitempty"` // TotalPages corrected for resets, wraps and device swaps
+	ColorPages     *int   `json:"color_pages,omitempty"`     // Full-color impressions, for printers that split their count
+	MonoPages      *int   `json:"mono_pages,omitempty"`      // Monochrome impressions
+	Unit           string `json:"unit,omitempty"`            // What TotalPages counts, e.g. impressions or sheets
+	PaperJams      *int   `json:"paper_jams,omitempty"`
+}
+
+// Supply is a marker supply such as a toner cartridge or drum
+type Supply struct {
+	Index         string `json:"index"`
+	Type          string `json:"type"` // prtMarkerSuppliesType by name, e.g. toner or opc
+	Description   string `json:"description,omitempty"`
+	Color         string `json:"color,omitempty"`
+	Level         *int   `json:"level,omitempty"`
+	MaxCapacity   *int   `json:"max_capacity,omitempty"`
+	Percent       *int   `json:"percent,omitempty"`
+	SomeRemaining bool   `json:"some_remaining,omitempty"` // Level unknown, but not empty
+}
+
+// Tray is a paper input tray
+type Tray struct {
+	Index         int                `json:"index"`
+	Name          string             `json:"name,omitempty"`
+	Description   string             `json:"description,omitempty"`
+	Condition     string             `json:"condition"` // ok, empty, warning, error, offline or unknown
+	State         snmp.SubUnitStatus `json:"state"`
+	Capacity      *int               `json:"capacity,omitempty"`
+	Level         *int               `json:"level,omitempty"`
+	Percent       *int               `json:"percent,omitempty"`
+	SomeRemaining bool               `json:"some_remaining,omitempty"` // Level unknown, but not empty
+	Media         *Media             `json:"media,omitempty"`
+}
+
+// Media is the paper loaded in a tray
+type Media struct {
+	Name  string          `json:"name,omitempty"`
+	Type  string          `json:"type,omitempty"`
+	Color string          `json:"color,omitempty"`
+	Size  *snmp.MediaSize `json:"size,omitempty"`
+}
+
+// OutputBin is an output tray or mailbox
+type OutputBin struct {
+	Index         int                `json:"index"`
+	Name          string             `json:"name,omitempty"`
+	Description   string             `json:"description,omitempty"`
+	Condition     string             `json:"condition"` // ok, full, warning, error, offline or unknown
+	State         snmp.SubUnitStatus `json:"state"`
+	Capacity      *int               `json:"capacity,omitempty"`
+	Remaining     *int               `json:"remaining,omitempty"`
+	PercentFull   *int               `json:"percent_full,omitempty"`
+	StackingOrder string             `json:"stacking_order,omitempty"` // first_to_last or last_to_first
+}
+
+// Alerts are the conditions that need attention
+type Alerts struct {
+	Count       int      `json:"count"`
+	Active      []string `json:"active"`
+	Last        string   `json:"last,omitempty"`
+	PaperStatus string   `json:"paper_status,omitempty"` // paper_out, paper_jam or toner_low
+}
+
+// Console is the front panel
+type Console struct {
+	DisplayLines []string            `json:"display_lines,omitempty"`
+	Lights       []snmp.ConsoleLight `json:"lights,omitempty"`
+}
+
+// Capabilities is what the printer can print
+type Capabilities struct {
+	Duplex       *bool              `json:"duplex,omitempty"` // Omitted when no media paths were reported
+	MaxMediaSize *snmp.MediaSize    `json:"max_media_size,omitempty"`
+	MinMediaSize *snmp.MediaSize    `json:"min_media_size,omitempty"`
+	MediaPaths   []snmp.MediaPath   `json:"media_paths,omitempty"`
+	Languages    []string           `json:"languages,omitempty"`
+	Interpreters []snmp.Interpreter `json:"interpreters,omitempty"`
+}
+
+// Resources is the printer's memory, storage and hardware components
+type Resources struct {
+	MemoryBytes *int64        `json:"memory_bytes,omitempty"` // hrMemorySize
+	Storage     []Storage     `json:"storage,omitempty"`
+	Devices     []snmp.Device `json:"devices,omitempty"`
+}
+
+// Storage is an area of memory or a disk
+type Storage struct {
+	Index              int    `json:"index"`
+	Type               string `json:"type"` // hrStorageType by name, e.g. ram, flash_memory or fixed_disk
+	Description        string `json:"description,omitempty"`
+	SizeBytes          *int64 `json:"size_bytes,omitempty"`
+	UsedBytes          *int64 `json:"used_bytes,omitempty"`
+	Percent            *int   `json:"percent,omitempty"`
+	AllocationFailures int    `json:"allocation_failures,omitempty"`
+	Condition          string `json:"condition"` // ok, warning, full or unknown
+}
+
+// counterUnits names the PrtMarkerCounterUnitTC values
+var counterUnits = map[int]string{
+	3:  "ten_thousandths_of_inches",
+	4:  "micrometers",
+	5:  "characters",
+	6:  "lines",
+	7:  "impressions",
+	8:  "sheets",
+	9:  "dot_row",
+	11: "hours",
+	16: "feet",
+	17: "meters",
+}
+
+// FromStatus converts a snapshot to the v2 schema. rollup overrides the
+// snapshot's own health, e.g. with offline, unless it is empty.
+func FromStatus(p *snmp.PrinterStatus, rollup snmp.Health, reachability *health.Target) Printer {
+	printer := Printer{
+		SchemaVersion: V2,
+		Host:          p.Host,
+		Protocol:      p.Protocol,
+		Identity: Identity{
+			Manufacturer:      p.Manufacturer,
+			Model:             p.Model,
+			SerialNumber:      p.SerialNumber,
+			FirmwareVersion:   p.FirmwareVersion,
+			DeviceName:        p.DeviceName,
+			PrinterName:       p.PrinterName,
+			SystemDescription: p.SystemDescription,
+			Location:          p.Location,
+			Contact:           p.Contact,
+			ObjectID:          p.ObjectID,
+			Vendor:            p.Vendor,
+			UUID:              p.UUID,
+			DeviceID:          p.DeviceID,
+			CommandSet:        p.CommandSet,
+		},
+		Network: Network{MACAddress: p.MACAddress, Interfaces: p.Interfaces},
+		Status: Status{
+			State:       p.Status,
+			DeviceState: p.DeviceState,
+			Health:      p.Health,
+		},
+		Supplies:     []Supply{},
+		Trays:        []Tray{},
+		OutputBins:   []OutputBin{},
+		Covers:       []snmp.Cover{},
+		Alerts:       Alerts{Count: p.ErrorCount, Active: []string{}, Last: p.LastError},
+		Reachability: reachability,
+	}
+	if !p.LastSeen.IsZero() {
+		printer.LastSeen = &p.LastSeen
+	}
+	if printer.Status.State == "" {
+		printer.Status.State = snmp.PrinterUnknown
+	}
+	if printer.Status.DeviceState == "" {
+		printer.Status.DeviceState = snmp.DeviceUnknown
+	}
+	if rollup != "" {
+		printer.Status.Health = rollup
+	} else if printer.Status.Health == "" {
+		printer.Status.Health = p.Rollup()
+	}
+	if reported(p, "uptime", p.Uptime == 0) {
+		seconds := float64(p.Uptime) / 100 // TimeTicks
+		printer.Status.UptimeSeconds = &seconds
+	}
+
+	if reported(p, "total_pages", p.TotalPages == 0) {
+		printer.Counters.TotalPages = intPtr(p.TotalPages)
+	}
+	if p.MonotonicPages > 0 {
+		pages := p.MonotonicPages
+		printer.Counters.MonotonicPages = &pages
+	}
+	if reported(p, "color_pages", p.ColorPages == 0) {
+		printer.Counters.ColorPages = intPtr(p.ColorPages)
+	}
+	if reported(p, "mono_pages", p.MonoPages == 0) {
+		printer.Counters.MonoPages = intPtr(p.MonoPages)
+	}
+	printer.Counters.Unit = counterUnits[p.PageCounterUnit]
+	if reported(p, "total_paper_jams", p.TotalPaperJams == 0) {
+		printer.Counters.PaperJams = intPtr(p.TotalPaperJams)
+	}
+
+	for _, s := range p.Supplies {
+		printer.Supplies = append(printer.Supplies, Supply{
+			Index:         s.Index,
+			Type:          snmp.SupplyTypeName(s.Type),
+			Description:   s.Description,
+			Color:         s.Color,
+			Level:         level(s.Level),
+			MaxCapacity:   capacity(s.MaxCapacity),
+			Percent:       level(s.Percent),
+			SomeRemaining: s.Level == -3,
+		})
+	}
+	for _, t := range p.PaperTrays {
+		tray := Tray{
+			Index:         t.Index,
+			Name:          t.Name,
+			Description:   t.Description,
+			Condition:     t.Condition,
+			State:         t.State,
+			Capacity:      capacity(t.Capacity),
+			Level:         level(t.Level),
+			Percent:       level(t.Percent),
+			SomeRemaining: t.Level == -3,
+		}
+		if t.MediaName != "" || t.MediaType != "" || t.MediaColor != "" || t.MediaSize != nil {
+			tray.Media = &Media{Name: t.MediaName, Type: t.MediaType, Color: t.MediaColor, Size: t.MediaSize}
+		}
+		printer.Trays = append(printer.Trays, tray)
+	}
+	for _, b := range p.OutputBins {
+		bin := OutputBin{
+			Index:         b.Index,
+			Name:          b.Name,
+			Description:   b.Description,
+			Condition:     b.Condition,
+			State:         b.State,
+			Capacity:      capacity(b.Capacity),
+			Remaining:     level(b.Remaining),
+			PercentFull:   level(b.PercentFull),
+			StackingOrder: b.StackingOrder,
+		}
+		if bin.StackingOrder == "unknown" {
+			bin.StackingOrder = ""
+		}
+		printer.OutputBins = append(printer.OutputBins, bin)
+	}
+	printer.Covers = append(printer.Covers, p.Covers...)
+
+	printer.Alerts.Active = append(printer.Alerts.Active, p.ActiveAlerts...)
+	if p.PaperStatus != "ok" {
+		printer.Alerts.PaperStatus = p.PaperStatus
+	}
+
+	if len(p.DisplayLines) > 0 || len(p.Lights) > 0 {
+		printer.Console = &Console{DisplayLines: p.DisplayLines, Lights: p.Lights}
+	}
+
+	c := p.Capabilities
+	if len(c.MediaPaths) > 0 || len(c.Languages) > 0 || len(c.Interpreters) > 0 || c.Duplex {
+		printer.Capabilities = &Capabilities{
+			MaxMediaSize: c.MaxMediaSize,
+			MinMediaSize: c.MinMediaSize,
+			MediaPaths:   c.MediaPaths,
+			Languages:    c.Languages,
+			Interpreters: c.Interpreters,
+		}
+		if len(c.MediaPaths) > 0 || c.Duplex {
+			duplex := c.Duplex
+			printer.Capabilities.Duplex = &duplex
+		}
+	}
+
+	if p.MemoryKB > 0 || len(p.Storage) > 0 || len(p.Devices) > 0 {
+		printer.Resources = &Resources{Devices: p.Devices}
+		if p.MemoryKB > 0 {
+			bytes := int64(p.MemoryKB) * 1024
+			printer.Resources.MemoryBytes = &bytes
+		}
+		for _, s := range p.Storage {
+			storage := Storage{
+				Index:              s.Index,
+				Type:               s.Type,
+				Description:        s.Description,
+				Percent:            level(s.Percent),
+				AllocationFailures: s.AllocationFailures,
+				Condition:          s.Condition,
+			}
+			if s.Percent >= 0 {
+				size, used := s.SizeBytes, s.UsedBytes
+				storage.SizeBytes, storage.UsedBytes = &size, &used
+			}
+			printer.Resources.Storage = append(printer.Resources.Storage, storage)
+		}
+	}
+	return printer
+}
+
+// reported reports whether the printer reported a field. A zero value only
+// counts when the merge recorded it in ReportedZero, which unlike Sources is
+// kept in the history, so polls reloaded from it serialize the same.
+func reported(p *snmp.PrinterStatus, field string, zero bool) bool {
+	return !zero || p.ZeroReported(field)
+}
+
+// level returns a level or percentage, nil for the negative "unknown" values
+func level(value int) *int {
+	if value < 0 {
+		return nil
+	}
+	return intPtr(value)
+}
+
+// capacity returns a capacity, nil when it is unknown or not reported
+func capacity(value int) *int {
+	if value <= 0 {
+		return nil
+	}
+	return intPtr(value)
+}
+
+func intPtr(value int) *int {
+	return &value
+}
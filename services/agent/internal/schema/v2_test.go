@@ -0,0 +1,308 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"lynk/agent/internal/health"
+	"lynk/agent/internal/snmp"
+)
+
+// validator checks a decoded JSON value against printer.v2.json. It knows the
+// keywords the document uses: $ref, type, const, enum, minimum, maximum,
+// required, properties and items. Properties the schema doesn't describe are
+// reported too, so every field the agent serves is documented.
+type validator struct {
+	root   map[string]interface{}
+	errors []string
+}
+
+func newValidator(t *testing.T) *validator {
+	t.Helper()
+	var root map[string]interface{}
+	if err := json.Unmarshal(Document(), &root); err != nil {
+		t.Fatalf("printer.v2.json: %v", err)
+	}
+	return &validator{root: root}
+}
+
+func (v *validator) errorf(path, format string, args ...interface{}) {
+	v.errors = append(v.errors, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) validate(path string, schema map[string]interface{}, value interface{}) {
+	if ref, ok := schema["$ref"].(string); ok {
+		def, _ := v.root["$defs"].(map[string]interface{})[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+		if def == nil {
+			v.errorf(path, "unresolved $ref %s", ref)
+			return
+		}
+		schema = def
+	}
+
+	if want, ok := schema["const"]; ok && !reflect.DeepEqual(value, want) {
+		v.errorf(path, "%v, want %v", value, want)
+	}
+	if values, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range values {
+			found = found || reflect.DeepEqual(value, allowed)
+		}
+		if !found {
+			v.errorf(path, "%v not in %v", value, values)
+		}
+	}
+
+	switch kind, _ := schema["type"].(string); kind {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			v.errorf(path, "%T, want an object", value)
+			return
+		}
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				v.errorf(path, "missing %s", name)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, field := range object {
+			property, ok := properties[name].(map[string]interface{})
+			if !ok {
+				v.errorf(path, "undocumented property %s", name)
+				continue
+			}
+			v.validate(path+"."+name, property, field)
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			v.errorf(path, "%T, want an array", value)
+			return
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range array {
+				v.validate(fmt.Sprintf("%s[%d]", path, i), items, item)
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			v.errorf(path, "%T, want a string", value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.errorf(path, "%T, want a boolean", value)
+		}
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok {
+			v.errorf(path, "%T, want a number", value)
+			return
+		}
+		if kind == "integer" && number != math.Trunc(number) {
+			v.errorf(path, "%v, want an integer", number)
+		}
+		if minimum, ok := schema["minimum"].(float64); ok && number < minimum {
+			v.errorf(path, "%v below the minimum %v", number, minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && number > maximum {
+			v.errorf(path, "%v above the maximum %v", number, maximum)
+		}
+	}
+}
+
+// check serializes a printer and validates it against the schema
+func (v *validator) check(t *testing.T, printer Printer) {
+	t.Helper()
+	data, err := json.Marshal(printer)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	v.errors = nil
+	v.validate("printer", v.root, value)
+	sort.Strings(v.errors)
+	for _, message := range v.errors {
+		t.Error(message)
+	}
+}
+
+// fullStatus is a poll with every section filled in
+func fullStatus() *snmp.PrinterStatus {
+	load := 12
+	status := &snmp.PrinterStatus{
+		Host:            "10.0.0.5",
+		Protocol:        "snmp",
+		LastSeen:        time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Manufacturer:    "Brother",
+		Model:           "HL-L8360CDW",
+		SerialNumber:    "E12345",
+		Location:        "2nd floor",
+		ObjectID:        "1.3.6.1.4.1.2435.2.3.9.1",
+		Vendor:          "Brother",
+		DeviceID:        "MFG:Brother;MDL:HL-L8360CDW;",
+		CommandSet:      []string{"PJL", "PCL"},
+		MACAddress:      "30:05:5c:5b:1b:6a",
+		Interfaces:      []snmp.NetworkInterface{{Index: 1, Description: "eth0", Type: 6, OperStatus: 1, IPAddresses: []string{"10.0.0.5"}}},
+		Status:          snmp.PrinterIdle,
+		DeviceState:     snmp.DeviceWarning,
+		Uptime:          360000,
+		TotalPages:      184022,
+		MonotonicPages:  250000,
+		ColorPages:      4022,
+		MonoPages:       180000,
+		PageCounterUnit: 7,
+		TotalPaperJams:  3,
+		ErrorCount:      1,
+		ActiveAlerts:    []string{"Toner low"},
+		LastError:       "Toner low",
+		PaperStatus:     "toner_low",
+		DisplayLines:    []string{"Ready", "Toner low"},
+		Lights:          []snmp.ConsoleLight{{Index: 1, Description: "Error", Color: "orange", State: "blink", OnTime: 500, OffTime: 500}},
+		Covers:          []snmp.Cover{{Index: 1, Description: "Front Cover", Status: 4, State: "closed"}},
+		Supplies: []snmp.Supply{
+			{Index: "1.1", Type: 3, Description: "Black Toner", MaxCapacity: 100, Level: 8, Percent: 8, Color: "black"},
+			{Index: "1.2", Type: 4, Description: "Waste Toner", MaxCapacity: -2, Level: -3, Percent: -1},
+		},
+		PaperTrays: []snmp.PaperTray{
+			{Index: 1, Name: "MP Tray", Type: 5, Status: 1, Capacity: 50, Level: -3},
+			{Index: 2, Name: "Tray 1", Type: 3, Capacity: 250, Level: 125, MediaName: "A4", MediaSize: &snmp.MediaSize{Width: 210, Length: 297}},
+		},
+		OutputBins: []snmp.OutputBin{{Index: 1, Name: "Face Down", Type: 4, Capacity: 150, Remaining: 100, StackingOrder: "last_to_first"}},
+		Capabilities: snmp.Capabilities{
+			Duplex:       true,
+			MediaPaths:   []snmp.MediaPath{{Index: 1, Description: "Duplex", Type: "long_edge_duplex", Duplex: true}},
+			Languages:    []string{"PCL"},
+			Interpreters: []snmp.Interpreter{{Index: 1, Language: "PCL", Family: 3, Level: "6"}},
+		},
+		MemoryKB: 524288,
+		Storage:  []snmp.Storage{{Index: 1, Type: "fixed_disk", Description: "HDD", AllocationUnits: 4096, Size: 1000, Used: 900}},
+		Devices:  []snmp.Device{{Index: 1, Type: "processor", Description: "CPU", Status: 2, State: snmp.DeviceRunning, Load: &load}},
+	}
+	for i := range status.PaperTrays {
+		status.PaperTrays[i].Decode()
+	}
+	for i := range status.OutputBins {
+		status.OutputBins[i].Decode()
+	}
+	for i := range status.Storage {
+		status.Storage[i].Decode()
+	}
+	status.Health = status.Rollup()
+	return status
+}
+
+func TestFromStatusMatchesSchema(t *testing.T) {
+	v := newValidator(t)
+	reachability := &health.Target{Host: "10.0.0.5", State: health.StateClosed, LastSuccess: time.Now(), Backoff: time.Minute}
+
+	tests := []struct {
+		name   string
+		status *snmp.PrinterStatus
+	}{
+		{"full", fullStatus()},
+		{"never polled", &snmp.PrinterStatus{Host: "10.0.0.6"}},
+		{"ipp", &snmp.PrinterStatus{Host: "10.0.0.7", Protocol: "ipp", Status: snmp.PrinterPrinting, PaperStatus: "ok"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v.check(t, FromStatus(test.status, "", nil))
+			v.check(t, FromStatus(test.status, snmp.HealthOffline, reachability))
+		})
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	printer := FromStatus(fullStatus(), "", nil)
+
+	if printer.SchemaVersion != V2 || printer.LastSeen == nil {
+		t.Errorf("schema version %d, last seen %v", printer.SchemaVersion, printer.LastSeen)
+	}
+	if printer.Status.UptimeSeconds == nil || *printer.Status.UptimeSeconds != 3600 {
+		t.Errorf("uptime = %v, want 3600 s", printer.Status.UptimeSeconds)
+	}
+	if c := printer.Counters; c.TotalPages == nil || *c.TotalPages != 184022 || c.Unit != "impressions" || *c.MonotonicPages != 250000 {
+		t.Errorf("counters = %+v", c)
+	}
+	if s := printer.Supplies[1]; s.Type != "wasteToner" || s.Level != nil || s.MaxCapacity != nil || s.Percent != nil || !s.SomeRemaining {
+		t.Errorf("unknown levels not omitted: %+v", s)
+	}
+	if tray := printer.Trays[1]; tray.Media == nil || tray.Media.Name != "A4" || *tray.Percent != 50 {
+		t.Errorf("tray = %+v", tray)
+	}
+	if r := printer.Resources; r == nil || *r.MemoryBytes != 512<<20 || r.Storage[0].Condition != "warning" {
+		t.Errorf("resources = %+v", r)
+	}
+	if printer.Capabilities == nil || printer.Capabilities.Duplex == nil || !*printer.Capabilities.Duplex {
+		t.Errorf("capabilities = %+v", printer.Capabilities)
+	}
+
+	empty := FromStatus(&snmp.PrinterStatus{Host: "10.0.0.6"}, "", nil)
+	if empty.Status.State != snmp.PrinterUnknown || empty.Status.DeviceState != snmp.DeviceUnknown || empty.LastSeen != nil {
+		t.Errorf("empty status = %+v, last seen %v", empty.Status, empty.LastSeen)
+	}
+	if empty.Counters.TotalPages != nil || empty.Status.UptimeSeconds != nil || empty.Console != nil || empty.Resources != nil {
+		t.Errorf("unreported values not omitted: %+v", empty)
+	}
+}
+
+func TestFromStatusSurvivesHistory(t *testing.T) {
+	// A printer that reports zero jams and pages must not lose them once the
+	// poll comes back from the -state history, where Sources isn't kept
+	poll := &snmp.PrinterStatus{Host: "10.0.0.5", Protocol: "snmp", Uptime: 100, Status: snmp.PrinterIdle}
+	poll.From("total_pages", "1.3.6.1.2.1.43.10.2.1.4.1.1")
+	poll.From("total_paper_jams", "1.3.6.1.4.1.2435.2.3.9.4.2.1.5.5.8.0")
+	merged := snmp.Merge(snmp.DefaultPrecedence, snmp.Result{Collector: "marker-counters", Protocol: "snmp", Status: poll})
+
+	data, err := json.Marshal(merged) // The v1 shape the history keeps
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var reloaded snmp.PrinterStatus
+	if err := json.Unmarshal(data, &reloaded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	fresh, _ := json.Marshal(FromStatus(merged, "", nil))
+	again, _ := json.Marshal(FromStatus(&reloaded, "", nil))
+	if string(fresh) != string(again) {
+		t.Errorf("reloaded poll serializes differently:\n%s\n%s", fresh, again)
+	}
+	counters := FromStatus(&reloaded, "", nil).Counters
+	if counters.TotalPages == nil || *counters.TotalPages != 0 || counters.PaperJams == nil || *counters.PaperJams != 0 {
+		t.Errorf("reported zeros lost: %+v", counters)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+		ok   bool
+	}{
+		{"", V2, true},
+		{"1", V1, true},
+		{"v1", V1, true},
+		{"2", V2, true},
+		{"v2", V2, true},
+		{"3", 0, false},
+		{"0", 0, false},
+		{"two", 0, false},
+	}
+	for _, test := range tests {
+		got, err := ParseVersion(test.text, V2)
+		if got != test.want || (err == nil) != test.ok {
+			t.Errorf("ParseVersion(%q) = %d, %v, want %d", test.text, got, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package snmp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+
+	"lynk/agent/internal/mib"
+)
+
+// Capabilities describes what a printer can print: the media paths it feeds
+// paper through and the page description languages it understands
+type Capabilities struct {
+	Duplex       bool          `json:"duplex"`                   // At least one media path prints both sides
+	MaxMediaSize *MediaSize    `json:"max_media_size,omitempty"` // Largest media any path takes
+	MinMediaSize *MediaSize    `json:"min_media_size,omitempty"` // Smallest media any path takes
+	MediaPaths   []MediaPath   `json:"media_paths"`              // prtMediaPathTable
+	Languages    []string      `json:"languages"`                // Interpreter languages, e.g. PCL, PostScript, PDF
+	Interpreters []Interpreter `json:"interpreters"`             // prtInterpreterTable
+}
+
+// MediaPath is a path media takes through the printer, e.g. the duplex path
+type MediaPath struct {
+	Index          int        `json:"index"`                    // prtMediaPathIndex
+	Description    string     `json:"description"`              // prtMediaPathDescription
+	Type           string     `json:"type"`                     // prtMediaPathType: long_edge_duplex, short_edge_duplex, simplex, other or unknown
+	Duplex         bool       `json:"duplex"`                   // Type is one of the duplex types
+	MaxSpeed       int        `json:"max_speed"`                // prtMediaPathMaxSpeed
+	MaxSpeedUnit   int        `json:"max_speed_unit"`           // prtMediaPathMaxSpeedPrintUnit (7=impressions/hour, 8=sheets/hour, ...)
+	PagesPerMinute int        `json:"pages_per_minute"`         // MaxSpeed in pages per minute, 0 unless it is given per impression or sheet
+	MaxMediaSize   *MediaSize `json:"max_media_size,omitempty"` // prtMediaPathMaxMediaXFeedDir x prtMediaPathMaxMediaFeedDir
+	MinMediaSize   *MediaSize `json:"min_media_size,omitempty"` // prtMediaPathMinMediaXFeedDir x prtMediaPathMinMediaFeedDir
+}
+
+// Interpreter is a page description or control language the printer understands
+type Interpreter struct {
+	Index       int    `json:"index"`       // prtInterpreterIndex
+	Language    string `json:"language"`    // prtInterpreterLangFamily by name, e.g. "PostScript"
+	Family      int    `json:"family"`      // prtInterpreterLangFamily
+	Level       string `json:"level"`       // prtInterpreterLangLevel, e.g. "3" for PostScript 3
+	Version     string `json:"version"`     // prtInterpreterLangVersion
+	Description string `json:"description"` // prtInterpreterDescription
+}
+
+// languageNames are the names of the languages whose PrtInterpreterLangFamilyTC
+// label isn't their usual name. The others are named by their label without
+// "lang", e.g. "PCL" for langPCL.
+var languageNames = map[string]string{
+	"langHPGL":      "HP-GL",
+	"langPS":        "PostScript",
+	"langPSPrinter": "PostScript",
+	"langEscapeP":   "ESC/P",
+	"langPCLXL":     "PCL XL",
+	"langPrescribe": "PRESCRIBE",
+}
+
+// languageName names a prtInterpreterLangFamily value, e.g. "PostScript" for 6
+func languageName(family int) string {
+	label := mib.Decode(mib.MustResolve("prtInterpreterLangFamily"), family)
+	if name, ok := languageNames[label]; ok {
+		return name
+	}
+	if name, ok := strings.CutPrefix(label, "lang"); ok {
+		return name
+	}
+	return fmt.Sprintf("Language %d", family)
+}
+
+// Speed units of prtMediaPathMaxSpeedPrintUnit that count pages
+const (
+	speedImpressionsPerHour = 7
+	speedSheetsPerHour      = 8
+)
+
+// pagesPerMinute converts a prtMediaPathMaxSpeed to pages per minute. It is 0
+// for unknown speeds and units that don't count pages, such as lines per hour.
+func pagesPerMinute(speed, unit int) int {
+	if speed <= 0 || (unit != speedImpressionsPerHour && unit != speedSheetsPerHour) {
+		return 0
+	}
+	return speed / 60
+}
+
+// CanPrint reports whether a standard paper size such as "A3" or "Tabloid"
+// fits the largest media the printer takes. Unknown sizes and printers
+// without media path information never fit.
+func (c Capabilities) CanPrint(size string) bool {
+	if c.MaxMediaSize == nil {
+		return false
+	}
+	for _, standard := range standardSizes {
+		if strings.EqualFold(standard.name, size) {
+			short, long := c.MaxMediaSize.Width, c.MaxMediaSize.Length
+			if short > long {
+				short, long = long, short
+			}
+			// The same tolerance Name allows for rounding
+			return short+2 >= standard.short && long+2 >= standard.long
+		}
+	}
+	return false
+}
+
+// getCapabilities collects the prtMediaPathTable and prtInterpreterTable
+func (c *Client) getCapabilities(g *gosnmp.GoSNMP, status *PrinterStatus) {
+	capabilities := &status.Capabilities
+
+	// prtMediaPathTable, rows are indexed by hrDeviceIndex.prtMediaPathIndex
+	indexes, rows, err := walkTable(g, mib.MustResolve("prtMediaPathEntry"))
+	if err == nil {
+		capabilities.MediaPaths = []MediaPath{}
+		for _, index := range indexes {
+			row := rows[index]
+			// longEdgeBindingDuplex is reported as long_edge_duplex
+			pathType := strings.Replace(enumName("prtMediaPathType", row.number(9), ""), "_binding", "", 1)
+
+			unit := row.number(3) // prtMediaPathMediaSizeUnit
+			path := MediaPath{
+				Description:  row.text(10), // prtMediaPathDescription
+				Type:         pathType,
+				Duplex:       pathType == "long_edge_duplex" || pathType == "short_edge_duplex",
+				MaxSpeed:     row.number(4), // prtMediaPathMaxSpeed
+				MaxSpeedUnit: row.number(2), // prtMediaPathMaxSpeedPrintUnit
+				MaxMediaSize: NewMediaSize(unit, row.number(6), row.number(5)),
+				MinMediaSize: NewMediaSize(unit, row.number(8), row.number(7)),
+			}
+			_, pathIndex, _ := strings.Cut(index, ".")
+			path.Index, _ = strconv.Atoi(pathIndex)
+			path.PagesPerMinute = pagesPerMinute(path.MaxSpeed, path.MaxSpeedUnit)
+			capabilities.MediaPaths = append(capabilities.MediaPaths, path)
+
+			if path.Duplex {
+				capabilities.Duplex = true
+			}
+			if path.MaxMediaSize != nil && (capabilities.MaxMediaSize == nil || path.MaxMediaSize.area() > capabilities.MaxMediaSize.area()) {
+				capabilities.MaxMediaSize = path.MaxMediaSize
+			}
+			if path.MinMediaSize != nil && (capabilities.MinMediaSize == nil || path.MinMediaSize.area() < capabilities.MinMediaSize.area()) {
+				capabilities.MinMediaSize = path.MinMediaSize
+			}
+		}
+	}
+
+	// prtInterpreterTable, rows are indexed by hrDeviceIndex.prtInterpreterIndex
+	indexes, rows, err = walkTable(g, mib.MustResolve("prtInterpreterEntry"))
+	if err != nil {
+		return
+	}
+	capabilities.Interpreters = []Interpreter{}
+	capabilities.Languages = []string{}
+	seen := make(map[string]bool)
+	for _, index := range indexes {
+		row := rows[index]
+		family, ok := row[2].(int) // prtInterpreterLangFamily
+		if !ok {
+			continue
+		}
+
+		language := languageName(family)
+		interpreter := Interpreter{
+			Language:    language,
+			Family:      family,
+			Level:       row.text(3), // prtInterpreterLangLevel
+			Version:     row.text(4), // prtInterpreterLangVersion
+			Description: row.text(5), // prtInterpreterDescription
+		}
+		_, interpreterIndex, _ := strings.Cut(index, ".")
+		interpreter.Index, _ = strconv.Atoi(interpreterIndex)
+		capabilities.Interpreters = append(capabilities.Interpreters, interpreter)
+
+		if !seen[language] {
+			seen[language] = true
+			capabilities.Languages = append(capabilities.Languages, language)
+		}
+	}
+}
+
+// UnmarshalJSON decodes a PrinterStatus. History saved before capabilities
+// were structured holds the raw IEEE 1284 device ID in "capabilities"; it is
+// moved to DeviceID. Older history also lacks the device state and health.
+func (p *PrinterStatus) UnmarshalJSON(data []byte) error {
+	type plain PrinterStatus
+	aux := struct {
+		*plain
+		Capabilities json.RawMessage `json:"capabilities"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	// History saved before the states were typed has no device state or health
+	if p.Status == "" {
+		p.Status = PrinterUnknown
+	}
+	if p.DeviceState == "" {
+		p.DeviceState = DeviceUnknown
+	}
+	if p.Health == "" {
+		p.Health = p.Rollup()
+	}
+
+	if len(aux.Capabilities) == 0 || string(aux.Capabilities) == "null" {
+		return nil
+	}
+	if aux.Capabilities[0] == '"' {
+		if p.DeviceID == "" {
+			return json.Unmarshal(aux.Capabilities, &p.DeviceID)
+		}
+		return nil
+	}
+	return json.Unmarshal(aux.Capabilities, &p.Capabilities)
+}
@@ -0,0 +1,47 @@
+package snmp
+
+import "testing"
+
+func TestCanPrint(t *testing.T) {
+	a4 := Capabilities{MaxMediaSize: &MediaSize{Width: 210, Length: 297}}
+	a3 := Capabilities{MaxMediaSize: &MediaSize{Width: 420, Length: 297}} // Fed landscape
+	tests := []struct {
+		name         string
+		capabilities Capabilities
+		size         string
+		want         bool
+	}{
+		{"the largest size", a4, "A4", true},
+		{"a smaller size", a4, "a5", true},
+		{"Letter is wider than A4", a4, "Letter", false},
+		{"a larger size", a4, "A3", false},
+		{"either orientation", a3, "A3", true},
+		{"Tabloid is longer than A3", a3, "Tabloid", false},
+		{"unknown size", a3, "Poster", false},
+		{"no media paths", Capabilities{}, "A4", false},
+	}
+	for _, test := range tests {
+		if got := test.capabilities.CanPrint(test.size); got != test.want {
+			t.Errorf("%s: CanPrint(%q) = %t, want %t", test.name, test.size, got, test.want)
+		}
+	}
+}
+
+func TestPagesPerMinute(t *testing.T) {
+	tests := []struct {
+		speed, unit int
+		want        int
+	}{
+		{1800, speedImpressionsPerHour, 30},
+		{2400, speedSheetsPerHour, 40},
+		{1800, 6, 0}, // Lines per hour
+		{-1, speedImpressionsPerHour, 0},
+		{-2, speedSheetsPerHour, 0},
+		{0, 0, 0},
+	}
+	for _, test := range tests {
+		if got := pagesPerMinute(test.speed, test.unit); got != test.want {
+			t.Errorf("pagesPerMinute(%d, %d) = %d, want %d", test.speed, test.unit, got, test.want)
+		}
+	}
+}
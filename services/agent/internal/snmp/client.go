@@ -1,32 +1,52 @@
 package snmp
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gosnmp/gosnmp"
+
+	"lynk/agent/internal/deviceid"
+	"lynk/agent/internal/mib"
 )
 
 // PrinterStatus represents the status of a printer (MVP Data Set)
 type PrinterStatus struct {
 	// Device Identity
 	Host           string    `json:"host"`
+	Protocol       string    `json:"protocol"`           // Protocol the status was collected with: snmp or ipp
+	UUID           string    `json:"uuid"`               // IPP printer-uuid
+	Manufacturer   string    `json:"manufacturer"`       // IEEE 1284 MFG
 	Model          string    `json:"model"`
 	SerialNumber   string    `json:"serial_number"`
 	FirmwareVersion string   `json:"firmware_version"`
 	DeviceName     string    `json:"device_name"`        // sysName.0
 	PrinterName    string    `json:"printer_name"`       // prtGeneralPrinterName.1
 	SystemDescription string `json:"system_description"` // sysDescr.0
+	Location       string    `json:"location"`           // sysLocation.0
+	Contact        string    `json:"contact"`            // sysContact.0
+	ObjectID       string    `json:"object_id"`          // sysObjectID.0
+	Vendor         string    `json:"vendor"`             // Enterprise of sysObjectID
+	MACAddress     string    `json:"mac_address"`        // ifPhysAddress of the polled interface
+	Interfaces     []NetworkInterface `json:"interfaces"` // ifTable and ipAddrTable
 	
 	// Device Status
-	Status         string    `json:"status"`             // prtGeneralPrinterStatus.1
+	Status         PrinterState `json:"status"`          // hrPrinterStatus, or the IPP/PJL printer state
 	Uptime         uint32    `json:"uptime"`             // sysUpTime.0 (TimeTicks)
 	DeviceStatus   int       `json:"device_status"`      // hrDeviceStatus
+	DeviceState    DeviceState `json:"device_state"`     // hrDeviceStatus
+	Health         Health    `json:"health"`             // Rollup of all the signals
 	
 	// Page Counters
 	TotalPages     int       `json:"total_pages"`        // prtMarkerLifeCount
+	MonotonicPages int64     `json:"monotonic_pages"`    // TotalPages corrected for resets, wraps and device swaps
 	PageCounterUnit int      `json:"page_counter_unit"`  // prtMarkerCounterUnit
+	ColorPages     int       `json:"color_pages,omitempty"` // Full-color impressions, IPP printer-impressions-completed-col
+	MonoPages      int       `json:"mono_pages,omitempty"`  // Monochrome impressions, IPP printer-impressions-completed-col
 	
 	// Consumables
 	TonerLevel     int       `json:"toner_level"`        // prtMarkerSuppliesLevel (toner)
@@ -42,8 +62,26 @@ type PrinterStatus struct {
 	// Paper Input/Trays
 	PaperTrays     []PaperTray `json:"paper_trays"`      // prtInputTable
 	
+	// Output Bins
+	OutputBins     []OutputBin `json:"output_bins"`      // prtOutputTable
+	
+	// Covers/Doors
+	Covers         []Cover   `json:"covers"`             // prtCoverTable
+	
+	// Front Panel
+	DisplayLines   []string  `json:"display_lines"`      // prtConsoleDisplayBufferTable
+	Lights         []ConsoleLight `json:"lights"`        // prtConsoleLightTable
+	
+	// Marker Supplies
+	Supplies       []Supply  `json:"supplies"`           // prtMarkerSuppliesTable
+	
+	// Host Resources
+	MemoryKB       int       `json:"memory_kb"`          // hrMemorySize
+	Storage        []Storage `json:"storage"`            // hrStorageTable
+	Devices        []Device  `json:"devices"`            // hrDeviceTable and hrProcessorTable
+	
 	// Legacy fields (for backward compatibility)
-	MemorySize     string    `json:"memory_size"`
+	MemorySize     string    `json:"memory_size"`        // hrMemorySize formatted, or PJL INFO CONFIG MEMORY
 	PaperStatus    string    `json:"paper_status"`
 	DrumCount      int       `json:"drum_count"`
 	DrumLifeRemaining int    `json:"drum_life_remaining"`
@@ -52,15 +90,98 @@ type PrinterStatus struct {
 	TonerReplaceCount int    `json:"toner_replace_count"`
 	DrumReplaceCount int     `json:"drum_replace_count"`
 	LastSeen       time.Time `json:"last_seen"`
-	Capabilities   string    `json:"capabilities"`
+	DeviceID       string    `json:"device_id"`          // IEEE 1284 device ID
+	CommandSet     []string  `json:"command_set"`        // IEEE 1284 CMD, e.g. PCL, PJL, POSTSCRIPT
+	Capabilities   Capabilities `json:"capabilities"`    // prtMediaPathTable and prtInterpreterTable
+	ReportedZero   []string  `json:"reported_zero,omitempty"` // Fields a source reported as zero, which Sources would tell but the history doesn't keep
+	Sources        map[string]*Attribution `json:"-"`    // Where each field was read from, not kept in the history
+	refs           map[string]string                    // Fields the collector attributed with From
 }
 
+
 // PaperTray represents a paper input tray
 type PaperTray struct {
-	Index    int    `json:"index"`
-	Name     string `json:"name"`     // prtInputName
-	Status   int    `json:"status"`   // prtInputStatus (1=other, 2=unknown, 3=empty, 4=full, 5=ok)
-	Capacity int    `json:"capacity"` // prtInputCapacity
+	Index        int           `json:"index"`                 // prtInputIndex
+	Name         string        `json:"name"`                  // prtInputName
+	Description  string        `json:"description,omitempty"` // prtInputDescription
+	Type         int           `json:"type"`                  // prtInputType (3=removable tray, 4=fixed tray, 5=manual feed, ...)
+	Status       int           `json:"status"`                // prtInputStatus, a SubUnitStatus bitfield
+	State        SubUnitStatus `json:"state"`                 // Status decoded
+	Condition    string        `json:"condition"`             // ok, empty, warning, error, offline or unknown
+	CapacityUnit int           `json:"capacity_unit"`         // prtInputCapacityUnit (8=sheets, ...)
+	Capacity     int           `json:"capacity"`              // prtInputMaxCapacity
+	Level        int           `json:"level"`                 // prtInputCurrentLevel (-2=unknown, -3=at least one)
+	Percent      int           `json:"percent"`               // Level as a percentage of Capacity, -1 if unknown
+	MediaName    string        `json:"media_name,omitempty"`  // prtInputMediaName
+	MediaType    string        `json:"media_type,omitempty"`  // prtInputMediaType, e.g. "stationery"
+	MediaColor   string        `json:"media_color,omitempty"` // prtInputMediaColor
+	MediaSize    *MediaSize    `json:"media_size,omitempty"`  // prtInputMediaDimXFeedDirDeclared x prtInputMediaDimFeedDirDeclared
+}
+
+// Decode fills State, Percent and Condition from the raw Status, Level and Capacity
+func (t *PaperTray) Decode() {
+	t.State = DecodeSubUnitStatus(t.Status)
+	t.Percent = -1
+	if t.Capacity > 0 && t.Level >= 0 {
+		t.Percent = t.Level * 100 / t.Capacity
+		if t.Percent > 100 {
+			t.Percent = 100
+		}
+	}
+	t.Condition = t.condition()
+}
+
+// condition rolls a tray's status and level up into a single word
+func (t PaperTray) condition() string {
+	switch {
+	case t.State.OffLine:
+		return "offline"
+	case t.State.Availability == "broken":
+		return "error"
+	case t.Level == 0:
+		return "empty"
+	case t.State.CriticalAlert:
+		return "error"
+	case t.State.NonCriticalAlert:
+		return "warning"
+	case t.State.Availability == "unknown":
+		return "unknown"
+	}
+	return "ok"
+}
+// Supply represents a marker supply such as a toner cartridge or drum unit
+type Supply struct {
+	Index       string `json:"index"`           // prtMarkerSuppliesIndex (hrDeviceIndex.supplyIndex)
+	Type        int    `json:"type"`            // prtMarkerSuppliesType (3=toner, 9=opc/drum, ...)
+	Description string `json:"description"`     // prtMarkerSuppliesDescription
+	MaxCapacity int    `json:"max_capacity"`    // prtMarkerSuppliesMaxCapacity
+	Level       int    `json:"level"`           // prtMarkerSuppliesLevel (-2=unknown, -3=some remaining)
+	Percent     int    `json:"percent"`         // Level as a percentage of MaxCapacity, -1 if unknown
+	Color       string `json:"color,omitempty"` // IPP marker-colors, e.g. "#000000"
+}
+
+// supplyTypeNames maps prtMarkerSuppliesType values to their IANA-PRINTER-MIB names
+var supplyTypeNames = mib.PrtMarkerSuppliesTypeTC
+
+// SupplyTypeName returns the IANA-PRINTER-MIB name of a prtMarkerSuppliesType value
+func SupplyTypeName(supplyType int) string {
+	if name, ok := supplyTypeNames[supplyType]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// SupplyType returns the prtMarkerSuppliesType value of a supply type name,
+// e.g. "tonerCartridge" or the IPP spelling "toner-cartridge", 2 (unknown) if
+// the name is not known
+func SupplyType(name string) int {
+	name = strings.ToLower(strings.ReplaceAll(name, "-", ""))
+	for supplyType, typeName := range supplyTypeNames {
+		if strings.ToLower(typeName) == name {
+			return supplyType
+		}
+	}
+	return 2
 }
 
 // Client represents an SNMP client for printer monitoring
@@ -77,101 +198,164 @@ func NewClient(community string) *Client {
 	}
 }
 
+// Probe checks whether a printer answers SNMP at all, using a single short
+// request instead of a full poll
+func (c *Client) Probe(ctx context.Context, host string) error {
+	timeout := c.timeout
+	if timeout > 3*time.Second {
+		timeout = 3 * time.Second
+	}
+
+	g, err := c.connect(ctx, host, timeout, 0)
+	if err != nil {
+		return err
+	}
+	defer g.Conn.Close()
+
+	if _, err := g.Get([]string{mib.MustResolve("sysUpTime.0")}); err != nil {
+		return fmt.Errorf("printer %s not responding: %w", host, err)
+	}
+	return nil
+}
+
+// connect opens an SNMP session to host
+func (c *Client) connect(ctx context.Context, host string, timeout time.Duration, retries int) (*gosnmp.GoSNMP, error) {
+	g := &gosnmp.GoSNMP{
+		Target:             host,
+		Port:               161,
+		Community:          c.community,
+		Timeout:            timeout,
+		Retries:            retries,
+		ExponentialTimeout: true,
+		Version:            gosnmp.Version2c,
+		MaxOids:            gosnmp.MaxOids,
+		Context:            ctx,
+	}
+
+	if err := g.Connect(); err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", host, err)
+	}
+	return g, nil
+}
+
 // Poll queries a printer for its status
 func (c *Client) Poll(host string) (*PrinterStatus, error) {
-	// Create SNMP connection
-	gosnmp.Default.Target = host
-	gosnmp.Default.Community = c.community
-	gosnmp.Default.Timeout = c.timeout
-	gosnmp.Default.Retries = 3
-	gosnmp.Default.Version = gosnmp.Version2c
-
-	err := gosnmp.Default.Connect()
+	return c.PollContext(context.Background(), host)
+}
+
+// PollContext queries a printer for its status. Outstanding SNMP requests are
+// abandoned once ctx is done.
+func (c *Client) PollContext(ctx context.Context, host string) (*PrinterStatus, error) {
+	// Create SNMP connection. Each poll gets its own session so polls can run concurrently.
+	g, err := c.connect(ctx, host, c.timeout, 3)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to %s: %w", host, err)
+		return nil, err
 	}
-	defer gosnmp.Default.Conn.Close()
+	defer g.Conn.Close()
 
-	status := &PrinterStatus{
-		Host:     host,
-		LastSeen: time.Now(),
-		Status:   "unknown",
+	// Make sure the printer answers before running the collectors, so an
+	// unreachable printer fails once instead of timing out on every OID
+	if _, err := g.Get([]string{mib.MustResolve("sysUpTime.0")}); err != nil {
+		return nil, fmt.Errorf("printer %s not responding: %w", host, err)
 	}
 
-	// Get Brother printer information
-	c.getBrotherInfo(status)
-	
-	// Try to get standard printer status
-	c.getStandardPrinterStatus(status)
-	
-	// Try to get toner levels
-	c.getTonerLevels(status)
-	
-	// Try to get page counts
-	c.getPageCounts(status)
-	
-	// Try to get error information
-	c.getErrorInfo(status)
-	
-	// Try to get additional Brother-specific information
-	c.getBrotherMaintenanceInfo(status)
-	
-	// Get MVP Data Set - Device Identity
-	c.getDeviceIdentity(status)
-	
-	// Get MVP Data Set - Device Status
-	c.getDeviceStatus(status)
-	
-	// Get MVP Data Set - Page Counters
-	c.getPageCounters(status)
-	
-	// Get MVP Data Set - Alerts/Errors
-	c.getAlertsAndErrors(status)
-	
-	// Get MVP Data Set - Paper Input/Trays
-	c.getPaperTrays(status)
+	// Every collector fills its own status so Merge can weigh their values
+	// against each other. Later stages come first, they read the more
+	// specific objects.
+	now := time.Now()
+	results := make([]Result, len(pipeline))
+	for i, step := range pipeline {
+		partial := &PrinterStatus{Host: host, Protocol: "snmp", LastSeen: now}
+		step.collect(c, g, partial)
+		results[len(pipeline)-1-i] = Result{Collector: step.name, Protocol: "snmp", Ref: step.ref, Status: partial}
+	}
+	status := Merge(DefaultPrecedence, results...)
+
+	// A poll cut short by its deadline is incomplete, don't report it as a result
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("polling %s: %w", host, err)
+	}
 
 	return status, nil
 }
 
-// getBrotherInfo gets Brother-specific printer information
-func (c *Client) getBrotherInfo(status *PrinterStatus) {
-	// Get the Brother printer model and capabilities
-	result, err := gosnmp.Default.Get([]string{"1.3.6.1.4.1.2435.2.3.9.1.1.7.0"})
-	if err == nil && len(result.Variables) > 0 {
-		if result.Variables[0].Type == gosnmp.OctetString {
-			value := string(result.Variables[0].Value.([]byte))
-			status.Capabilities = value
-			
-			// Extract model from the capabilities string
-			if strings.Contains(value, "MDL:") {
-				parts := strings.Split(value, "MDL:")
-				if len(parts) > 1 {
-					modelPart := strings.Split(parts[1], ";")[0]
-					status.Model = strings.TrimSpace(modelPart)
-				}
+// deviceIDObjects are where vendors expose the IEEE 1284 device ID, tried in order
+var deviceIDObjects = []string{
+	"brotherIEEE1284DeviceId.0",
+	"hpIEEE1284DeviceId.0",
+	"ppmPrinterIEEE1284DeviceId.1", // PWG Port Monitor MIB
+}
+
+// getDeviceID reads the printer's IEEE 1284 device ID and takes the
+// manufacturer, model, command set and serial number from it
+func (c *Client) getDeviceID(g *gosnmp.GoSNMP, status *PrinterStatus) {
+	for _, name := range deviceIDObjects {
+		oid := mib.MustResolve(name)
+		result, err := g.Get([]string{oid})
+		if err != nil || len(result.Variables) == 0 || result.Variables[0].Type != gosnmp.OctetString {
+			continue
+		}
+		value := string(result.Variables[0].Value.([]byte))
+		id := deviceid.Parse(value)
+		if id.Empty() {
+			continue
+		}
+		
+		status.DeviceID = value
+		ApplyDeviceID(status, id)
+		for field, ok := range map[string]bool{
+			"device_id":     true,
+			"manufacturer":  id.Manufacturer != "",
+			"model":         id.Model != "",
+			"serial_number": id.SerialNumber != "",
+			"command_set":   len(id.CommandSet) > 0,
+		} {
+			if ok {
+				status.From(field, oid)
 			}
 		}
+		return
+	}
+}
+
+// ApplyDeviceID fills the identity fields a device ID provides. The model
+// is taken from the device ID; the serial number only when none is known.
+func ApplyDeviceID(status *PrinterStatus, id deviceid.DeviceID) {
+	if id.Manufacturer != "" {
+		status.Manufacturer = id.Manufacturer
+	}
+	if id.Model != "" {
+		status.Model = id.Model
+	}
+	if len(id.CommandSet) > 0 {
+		status.CommandSet = id.CommandSet
+	}
+	if id.SerialNumber != "" && status.SerialNumber == "" {
+		status.SerialNumber = id.SerialNumber
 	}
 }
 
 // getStandardPrinterStatus tries to get printer status using standard OIDs
-func (c *Client) getStandardPrinterStatus(status *PrinterStatus) {
-	statusOIDs := []string{
-		"1.3.6.1.2.1.25.3.5.1.1.1",            // hrPrinterStatus
-		"1.3.6.1.2.1.25.3.5.1.2.1",            // hrPrinterDetectedErrorState
+func (c *Client) getStandardPrinterStatus(g *gosnmp.GoSNMP, status *PrinterStatus) {
+	statusObjects := []string{
+		"hrPrinterStatus.1",
+		"hrPrinterDetectedErrorState.1",
 	}
 
-	for _, oid := range statusOIDs {
-		result, err := gosnmp.Default.Get([]string{oid})
+	for _, name := range statusObjects {
+		oid := mib.MustResolve(name)
+		result, err := g.Get([]string{oid})
 		if err == nil && len(result.Variables) > 0 {
 			if result.Variables[0].Type == gosnmp.Integer {
 				value := int(result.Variables[0].Value.(int))
-				if oid == "1.3.6.1.2.1.25.3.5.1.1.1" {
-					status.Status = c.parsePrinterStatus(value)
+				if name == "hrPrinterStatus.1" {
+					status.Status = HRPrinterState(value)
+					status.From("status", oid)
 				} else {
 					status.ErrorCount = value
 					status.PaperStatus = c.parseErrorState(value)
+					status.From("error_count", oid)
+					status.From("paper_status", oid)
 				}
 			}
 		}
@@ -179,15 +363,15 @@ func (c *Client) getStandardPrinterStatus(status *PrinterStatus) {
 }
 
 // getTonerLevels tries to get toner level information using standard Printer-MIB
-func (c *Client) getTonerLevels(status *PrinterStatus) {
+func (c *Client) getTonerLevels(g *gosnmp.GoSNMP, status *PrinterStatus) {
 	// Walk the prtMarkerSuppliesTable to find toner information
-	baseOID := "1.3.6.1.2.1.43.11.1.1"
+	baseOID := mib.MustResolve("prtMarkerSuppliesEntry")
 	
 	// Store supplies data by index
 	suppliesData := make(map[string]map[string]interface{})
 	
 	// Walk the supplies table
-	err := gosnmp.Default.Walk(baseOID, func(variable gosnmp.SnmpPDU) error {
+	err := g.Walk(baseOID, func(variable gosnmp.SnmpPDU) error {
 		oid := variable.Name
 		parts := strings.Split(oid, ".")
 		if len(parts) >= 4 {
@@ -208,7 +392,7 @@ func (c *Client) getTonerLevels(status *PrinterStatus) {
 			
 			// Store the value based on sub-OID
 			switch subOID {
-			case "5": // prtMarkerSuppliesClass
+			case "5": // prtMarkerSuppliesType
 				if variable.Type == gosnmp.Integer {
 					class := int(variable.Value.(int))
 					suppliesData[index]["class"] = class
@@ -237,10 +421,31 @@ func (c *Client) getTonerLevels(status *PrinterStatus) {
 		return // Skip toner detection if walk fails
 	}
 	
+	// Keep every supply so consumers can show toner and drum levels side by side
+	status.Supplies = []Supply{}
+	for index, data := range suppliesData {
+		supply, ok := newSupply(index, data)
+		if !ok {
+			continue
+		}
+		status.Supplies = append(status.Supplies, supply)
+		
+		// Type 9 = OPC (drum unit)
+		_, hasMax := data["maxCapacity"].(int)
+		_, hasCurrent := data["currentLevel"].(int)
+		if supply.Type == 9 && hasMax && hasCurrent {
+			status.DrumLevel = supply.Level
+			status.DrumMaxCapacity = supply.MaxCapacity
+		}
+	}
+	sort.Slice(status.Supplies, func(i, j int) bool {
+		return indexLess(status.Supplies[i].Index, status.Supplies[j].Index)
+	})
+	
 	// Find toner supplies and calculate percentage
 	for _, data := range suppliesData {
 		class, hasClass := data["class"]
-		if hasClass && class.(int) == 3 { // Class 3 = Toner
+		if hasClass && class.(int) == 3 { // Type 3 = Toner
 			description, _ := data["description"].(string)
 			maxCapacity, hasMax := data["maxCapacity"].(int)
 			currentLevel, hasCurrent := data["currentLevel"].(int)
@@ -271,23 +476,54 @@ func (c *Client) getTonerLevels(status *PrinterStatus) {
 	}
 }
 
+// newSupply builds a supply from the prtMarkerSuppliesTable columns read for
+// it. A level the printer didn't report is -2, unknown, as in the MIB. Rows
+// with neither a level nor a description are skipped.
+func newSupply(index string, data map[string]interface{}) (Supply, bool) {
+	supplyType, _ := data["class"].(int)
+	description, _ := data["description"].(string)
+	maxCapacity, hasMax := data["maxCapacity"].(int)
+	currentLevel, hasCurrent := data["currentLevel"].(int)
+	if !hasCurrent && description == "" {
+		return Supply{}, false
+	}
+
+	supply := Supply{
+		Index:       index,
+		Type:        supplyType,
+		Description: description,
+		MaxCapacity: maxCapacity,
+		Level:       -2,
+		Percent:     -1,
+	}
+	if hasCurrent {
+		supply.Level = currentLevel
+	}
+	if hasMax && maxCapacity > 0 && supply.Level >= 0 {
+		supply.Percent = (supply.Level * 100) / maxCapacity
+	}
+	return supply, true
+}
+
 // getPageCounts tries to get page count information
-func (c *Client) getPageCounts(status *PrinterStatus) {
+func (c *Client) getPageCounts(g *gosnmp.GoSNMP, status *PrinterStatus) {
 	// Standard page count OIDs
-	pageOIDs := []string{
-		"1.3.6.1.2.1.43.10.2.1.4.1.1",         // Standard total pages printed
-		"1.3.6.1.2.1.43.10.2.1.4.1.2",         // Alternative page count
-		"1.3.6.1.4.1.2435.2.3.9.4.2.1.1.1.6.1.4", // Brother specific page count
-		"1.3.6.1.4.1.2435.2.3.9.4.2.1.1.1.6.1.5", // Brother specific page count alt
+	pageObjects := []string{
+		"prtMarkerLifeCount.1.1",          // Standard total pages printed
+		"prtMarkerLifeCount.1.2",          // Alternative page count
+		"brotherPageCounters.1.4",         // Brother specific page count
+		"brotherPageCounters.1.5",         // Brother specific page count alt
 	}
 
-	for _, oid := range pageOIDs {
-		result, err := gosnmp.Default.Get([]string{oid})
+	for _, name := range pageObjects {
+		oid := mib.MustResolve(name)
+		result, err := g.Get([]string{oid})
 		if err == nil && len(result.Variables) > 0 {
 			if result.Variables[0].Type == gosnmp.Integer {
 				pages := int(result.Variables[0].Value.(int))
 				if pages > 0 {
 					status.TotalPages = pages
+					status.From("total_pages", oid)
 					break
 				}
 			} else if result.Variables[0].Type == gosnmp.Counter32 {
@@ -297,18 +533,21 @@ func (c *Client) getPageCounts(status *PrinterStatus) {
 					pages := int(v)
 					if pages > 0 {
 						status.TotalPages = pages
+						status.From("total_pages", oid)
 						break
 					}
 				case uint:
 					pages := int(v)
 					if pages > 0 {
 						status.TotalPages = pages
+						status.From("total_pages", oid)
 						break
 					}
 				case int:
 					pages := v
 					if pages > 0 {
 						status.TotalPages = pages
+						status.From("total_pages", oid)
 						break
 					}
 				}
@@ -318,51 +557,56 @@ func (c *Client) getPageCounts(status *PrinterStatus) {
 }
 
 // getErrorInfo tries to get error information
-func (c *Client) getErrorInfo(status *PrinterStatus) {
+func (c *Client) getErrorInfo(g *gosnmp.GoSNMP, status *PrinterStatus) {
 	// Try to get error descriptions
-	errorOIDs := []string{
-		"1.3.6.1.2.1.25.3.5.1.2.1",            // hrPrinterDetectedErrorState
-		"1.3.6.1.4.1.2435.2.3.9.1.1.2.0",      // Brother error status
-		"1.3.6.1.4.1.2435.2.3.9.1.1.3.0",      // Brother error description
+	errorObjects := []string{
+		"hrPrinterDetectedErrorState.1",
+		"brotherErrorStatus.0",
+		"brotherErrorDescription.0",
 	}
 
-	for _, oid := range errorOIDs {
-		result, err := gosnmp.Default.Get([]string{oid})
+	for _, name := range errorObjects {
+		oid := mib.MustResolve(name)
+		result, err := g.Get([]string{oid})
 		if err == nil && len(result.Variables) > 0 {
 			if result.Variables[0].Type == gosnmp.Integer {
 				errorState := int(result.Variables[0].Value.(int))
 				if errorState != 0 {
 					status.LastError = c.parseErrorDescription(errorState)
+					status.From("last_error", oid)
 				}
 			} else if result.Variables[0].Type == gosnmp.OctetString {
 				errorDesc := string(result.Variables[0].Value.([]byte))
 				if errorDesc != "" {
 					status.LastError = errorDesc
+					status.From("last_error", oid)
 				}
 			}
 		}
 	}
 }
 
-// parsePrinterStatus converts SNMP status to human readable
-func (c *Client) parsePrinterStatus(status int) string {
-	switch status {
-	case 1:
-		return "other"
-	case 2:
-		return "unknown"
-	case 3:
-		return "idle"
-	case 4:
-		return "printing"
-	case 5:
-		return "warmup"
-	default:
-		return "unknown"
+// indexLess orders dotted table indexes such as "1.2" and "1.10" numerically
+func indexLess(a, b string) bool {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.Atoi(aParts[i])
+		bNum, bErr := strconv.Atoi(bParts[i])
+		if aErr != nil || bErr != nil {
+			if aParts[i] != bParts[i] {
+				return aParts[i] < bParts[i]
+			}
+			continue
+		}
+		if aNum != bNum {
+			return aNum < bNum
+		}
 	}
+	return len(aParts) < len(bParts)
 }
 
-// parseErrorState converts error state to paper status
+// parsePrinterStatus converts SNMP status to human readable
 func (c *Client) parseErrorState(errorState int) string {
 	if errorState == 0 {
 		return "ok"
@@ -414,29 +658,29 @@ func (c *Client) parseErrorDescription(errorState int) string {
 }
 
 	// getBrotherMaintenanceInfo tries to get Brother-specific maintenance information
-	func (c *Client) getBrotherMaintenanceInfo(status *PrinterStatus) {
+	func (c *Client) getBrotherMaintenanceInfo(g *gosnmp.GoSNMP, status *PrinterStatus) {
 		// Brother-specific OIDs for maintenance information (from verified mapping table)
-		maintenanceOIDs := []string{
-			"1.3.6.1.4.1.2435.2.4.3.99.3.1.6.1.2.1",  // Model Name: MODEL="HL-L2360D series"
-			"1.3.6.1.4.1.2435.2.4.3.99.3.1.6.1.2.2",  // Serial Number: SERIAL="U63883E4N132987"
-			"1.3.6.1.2.1.1.5.0",                       // Device Name: BRN30055C465129
-			"1.3.6.1.4.1.2435.2.4.3.99.3.1.6.1.2.7",  // Main Firmware: FIRMVER="1.38"
-			"1.3.6.1.4.1.2435.2.4.3.99.3.1.6.1.2.8",  // Sub1 Firmware ID: FIRMID="SUB1"
-			"1.3.6.1.4.1.2435.2.4.3.99.3.1.6.1.2.9",  // Sub1 Firmware: FIRMVER="1.03"
-			"1.3.6.1.2.1.1.3.0",                       // Uptime (TimeTicks)
-			"1.3.6.1.2.1.25.3.5.1.1.1",               // Device Status (1=unknown, 2=running, 3=warning, 4=testing, 5=down)
-			"1.3.6.1.2.1.43.10.2.1.4.1.1",            // Page Counter: 1536 (matches web interface!)
-			"1.3.6.1.4.1.2435.2.3.9.2.1.2.9.0",       // Brother paper jams (discovered: value 2)
-		}
-
-	for _, oid := range maintenanceOIDs {
-		result, err := gosnmp.Default.Get([]string{oid})
+		maintenanceObjects := []string{
+			"brotherMaintenanceInfo.1",       // Model Name: MODEL="HL-L2360D series"
+			"brotherMaintenanceInfo.2",       // Serial Number: SERIAL="U63883E4N132987"
+			"brotherMaintenanceInfo.7",       // Main Firmware: FIRMVER="1.38"
+			"brotherMaintenanceInfo.8",       // Sub1 Firmware ID: FIRMID="SUB1"
+			"brotherMaintenanceInfo.9",       // Sub1 Firmware: FIRMVER="1.03"
+			"sysUpTime.0",                    // Uptime (TimeTicks)
+			"hrPrinterStatus.1",              // (1=other, 2=unknown, 3=idle, 4=printing, 5=warmup)
+			"prtMarkerLifeCount.1.1",         // Page Counter: 1536 (matches web interface!)
+			"brotherPaperJams.0",             // Brother paper jams (discovered: value 2)
+		}
+
+	for _, name := range maintenanceObjects {
+		oid := mib.MustResolve(name)
+		result, err := g.Get([]string{oid})
 		if err == nil && len(result.Variables) > 0 {
 			variable := result.Variables[0]
 			
-			// Try to extract information based on OID
-			switch oid {
-			case "1.3.6.1.4.1.2435.2.4.3.99.3.1.6.1.2.1": // Model Name
+			// Try to extract information based on the object
+			switch name {
+			case "brotherMaintenanceInfo.1": // Model Name
 				if variable.Type == gosnmp.OctetString {
 					value := string(variable.Value.([]byte))
 					// Extract model from MODEL="HL-L2360D series"
@@ -444,10 +688,11 @@ func (c *Client) parseErrorDescription(errorState int) string {
 						parts := strings.Split(value, "=")
 						if len(parts) > 1 {
 							status.Model = strings.Trim(parts[1], "\"")
+							status.From("model", oid)
 						}
 					}
 				}
-			case "1.3.6.1.4.1.2435.2.4.3.99.3.1.6.1.2.2": // Serial Number
+			case "brotherMaintenanceInfo.2": // Serial Number
 				if variable.Type == gosnmp.OctetString {
 					value := string(variable.Value.([]byte))
 					// Extract serial from SERIAL="U63883E4N132987"
@@ -455,18 +700,11 @@ func (c *Client) parseErrorDescription(errorState int) string {
 						parts := strings.Split(value, "=")
 						if len(parts) > 1 {
 							status.SerialNumber = strings.Trim(parts[1], "\"")
+							status.From("serial_number", oid)
 						}
 					}
 				}
-			case "1.3.6.1.2.1.1.5.0": // Device Name
-				if variable.Type == gosnmp.OctetString {
-					value := string(variable.Value.([]byte))
-					if value != "" && status.SerialNumber == "" {
-						// Use as fallback serial number if we don't have the proper one
-						status.SerialNumber = value
-					}
-				}
-			case "1.3.6.1.4.1.2435.2.4.3.99.3.1.6.1.2.7": // Main Firmware
+			case "brotherMaintenanceInfo.7": // Main Firmware
 				if variable.Type == gosnmp.OctetString {
 					value := string(variable.Value.([]byte))
 					// Extract firmware from FIRMVER="1.38"
@@ -474,10 +712,11 @@ func (c *Client) parseErrorDescription(errorState int) string {
 						parts := strings.Split(value, "=")
 						if len(parts) > 1 {
 							status.FirmwareVersion = strings.Trim(parts[1], "\"")
+							status.From("firmware_version", oid)
 						}
 					}
 				}
-			case "1.3.6.1.4.1.2435.2.4.3.99.3.1.6.1.2.9": // Sub1 Firmware
+			case "brotherMaintenanceInfo.9": // Sub1 Firmware
 				if variable.Type == gosnmp.OctetString {
 					value := string(variable.Value.([]byte))
 					// Extract sub firmware from FIRMVER="1.03"
@@ -489,25 +728,12 @@ func (c *Client) parseErrorDescription(errorState int) string {
 						}
 					}
 				}
-			case "1.3.6.1.2.1.25.3.5.1.1.1": // Device Status
+			case "hrPrinterStatus.1":
 				if variable.Type == gosnmp.Integer {
-					statusCode := int(variable.Value.(int))
-					switch statusCode {
-					case 1:
-						status.Status = "Unknown"
-					case 2:
-						status.Status = "Running"
-					case 3:
-						status.Status = "Warning"
-					case 4:
-						status.Status = "Testing"
-					case 5:
-						status.Status = "Down"
-					default:
-						status.Status = fmt.Sprintf("Status %d", statusCode)
-					}
+					status.Status = HRPrinterState(variable.Value.(int))
+					status.From("status", oid)
 				}
-			case "1.3.6.1.2.1.43.10.2.1.4.1.1": // Page Counter (matches web interface!)
+			case "prtMarkerLifeCount.1.1": // Page Counter (matches web interface!)
 				if variable.Type == gosnmp.Counter32 {
 					switch v := variable.Value.(type) {
 					case uint32:
@@ -517,8 +743,9 @@ func (c *Client) parseErrorDescription(errorState int) string {
 					case int:
 						status.TotalPages = v
 					}
+					status.From("total_pages", oid)
 				}
-			case "1.3.6.1.4.1.2435.2.3.9.2.1.2.9.0": // Brother paper jams
+			case "brotherPaperJams.0": // Brother paper jams
 				if variable.Type == gosnmp.Integer {
 					status.TotalPaperJams = int(variable.Value.(int))
 				} else if variable.Type == gosnmp.Counter32 {
@@ -531,6 +758,7 @@ func (c *Client) parseErrorDescription(errorState int) string {
 						status.TotalPaperJams = v
 					}
 				}
+				status.From("total_paper_jams", oid)
 			}
 		}
 	}
@@ -546,6 +774,10 @@ func (p *PrinterStatus) String() string {
 	output.WriteString("   === DEVICE IDENTITY ===\n")
 	output.WriteString(fmt.Sprintf("   Model: %s\n", p.Model))
 	
+	if p.Manufacturer != "" {
+		output.WriteString(fmt.Sprintf("   Manufacturer: %s\n", p.Manufacturer))
+	}
+	
 	if p.SerialNumber != "" {
 		output.WriteString(fmt.Sprintf("   Serial Number: %s\n", p.SerialNumber))
 	}
@@ -566,9 +798,36 @@ func (p *PrinterStatus) String() string {
 		output.WriteString(fmt.Sprintf("   System Description: %s\n", p.SystemDescription))
 	}
 	
+	if p.Location != "" {
+		output.WriteString(fmt.Sprintf("   Location: %s\n", p.Location))
+	}
+	
+	if p.Contact != "" {
+		output.WriteString(fmt.Sprintf("   Contact: %s\n", p.Contact))
+	}
+	
+	if p.ObjectID != "" {
+		output.WriteString(fmt.Sprintf("   Object ID: %s", p.ObjectID))
+		if p.Vendor != "" {
+			output.WriteString(fmt.Sprintf(" (%s)", p.Vendor))
+		}
+		output.WriteString("\n")
+	}
+	
+	if p.MACAddress != "" {
+		output.WriteString(fmt.Sprintf("   MAC Address: %s\n", p.MACAddress))
+	}
+	
+	for _, iface := range p.Interfaces {
+		if iface.MACAddress != "" || len(iface.IPAddresses) > 0 {
+			output.WriteString(fmt.Sprintf("   Interface: %s\n", iface))
+		}
+	}
+	
 	// Device Status
 	output.WriteString("   === DEVICE STATUS ===\n")
 	output.WriteString(fmt.Sprintf("   Status: %s\n", p.Status))
+	output.WriteString(fmt.Sprintf("   Health: %s\n", p.Health))
 	
 	if p.Uptime > 0 {
 		// Convert TimeTicks to hours (TimeTicks are in hundredths of a second)
@@ -577,7 +836,7 @@ func (p *PrinterStatus) String() string {
 	}
 	
 	if p.DeviceStatus > 0 {
-		output.WriteString(fmt.Sprintf("   Device Status Code: %d\n", p.DeviceStatus))
+		output.WriteString(fmt.Sprintf("   Device Status: %s (%d)\n", p.DeviceState, p.DeviceStatus))
 	}
 	
 	if p.TonerLevel > 0 {
@@ -628,6 +887,20 @@ func (p *PrinterStatus) String() string {
 		output.WriteString(fmt.Sprintf("   Drum Replace Count: %d\n", p.DrumReplaceCount))
 	}
 	
+	// Host Resources
+	if p.MemorySize != "" || len(p.Storage) > 0 || len(p.Devices) > 0 {
+		output.WriteString("   === HOST RESOURCES ===\n")
+		if p.MemorySize != "" {
+			output.WriteString(fmt.Sprintf("   Memory: %s\n", p.MemorySize))
+		}
+		for _, storage := range p.Storage {
+			output.WriteString(fmt.Sprintf("   Storage %s\n", storage))
+		}
+		for _, device := range p.Devices {
+			output.WriteString(fmt.Sprintf("   Device %s\n", device))
+		}
+	}
+	
 	// Alerts/Errors
 	output.WriteString("   === ALERTS / ERRORS ===\n")
 	output.WriteString(fmt.Sprintf("   Error Count: %d\n", p.ErrorCount))
@@ -643,71 +916,142 @@ func (p *PrinterStatus) String() string {
 		}
 	}
 	
+	// Covers/Doors
+	if len(p.Covers) > 0 {
+		output.WriteString("   === COVERS ===\n")
+		for _, cover := range p.Covers {
+			output.WriteString(fmt.Sprintf("   %s\n", cover))
+		}
+	}
+	
+	// Front Panel
+	if len(p.DisplayLines) > 0 || len(p.Lights) > 0 {
+		output.WriteString("   === FRONT PANEL ===\n")
+		for _, line := range p.DisplayLines {
+			output.WriteString(fmt.Sprintf("   | %s\n", line))
+		}
+		for _, light := range p.Lights {
+			output.WriteString(fmt.Sprintf("   Light %s\n", light))
+		}
+	}
+	
 	// Paper Input/Trays
 	if len(p.PaperTrays) > 0 {
 		output.WriteString("   === PAPER TRAYS ===\n")
 		for _, tray := range p.PaperTrays {
-			var statusText string
-			switch tray.Status {
-			case 1:
-				statusText = "Other"
-			case 2:
-				statusText = "Unknown"
-			case 3:
-				statusText = "Empty"
-			case 4:
-				statusText = "Full"
-			case 5:
-				statusText = "OK"
-			default:
-				statusText = fmt.Sprintf("Status %d", tray.Status)
+			level := "level unknown"
+			switch {
+			case tray.Percent >= 0:
+				level = fmt.Sprintf("%d%% (%d/%d)", tray.Percent, tray.Level, tray.Capacity)
+			case tray.Level == -3:
+				level = "some remaining"
+			}
+			output.WriteString(fmt.Sprintf("   %s: %s, %s, %s", tray.Name, tray.Condition, level, tray.State))
+			if tray.MediaSize != nil {
+				output.WriteString(fmt.Sprintf(", %s", tray.MediaSize))
 			}
-			output.WriteString(fmt.Sprintf("   %s: %s (Capacity: %d)\n", tray.Name, statusText, tray.Capacity))
+			if tray.MediaName != "" {
+				output.WriteString(fmt.Sprintf(", %s", tray.MediaName))
+			}
+			output.WriteString("\n")
+		}
+	}
+	
+	// Output Bins
+	if len(p.OutputBins) > 0 {
+		output.WriteString("   === OUTPUT BINS ===\n")
+		for _, bin := range p.OutputBins {
+			output.WriteString(fmt.Sprintf("   %s\n", bin))
 		}
 	}
 	
 	output.WriteString(fmt.Sprintf("   Last Checked: %s\n", p.LastSeen.Format("2006-01-02 15:04:05")))
 	
-	// Show capabilities in a cleaner format
-	if p.Capabilities != "" {
+	// Capabilities
+	capabilities := p.Capabilities
+	if len(capabilities.MediaPaths) > 0 || len(capabilities.Languages) > 0 {
 		output.WriteString("   Capabilities:\n")
-		capabilities := strings.Split(p.Capabilities, ";")
-		for _, cap := range capabilities {
-			if cap != "" {
-				parts := strings.Split(cap, ":")
-				if len(parts) == 2 {
-					output.WriteString(fmt.Sprintf("     %s: %s\n", parts[0], parts[1]))
-				}
+		if len(capabilities.MediaPaths) > 0 {
+			output.WriteString(fmt.Sprintf("     Duplex: %t\n", capabilities.Duplex))
+		}
+		if capabilities.MaxMediaSize != nil {
+			output.WriteString(fmt.Sprintf("     Largest Media: %s\n", capabilities.MaxMediaSize))
+		}
+		if capabilities.MinMediaSize != nil {
+			output.WriteString(fmt.Sprintf("     Smallest Media: %s\n", capabilities.MinMediaSize))
+		}
+		for _, path := range capabilities.MediaPaths {
+			if path.PagesPerMinute > 0 {
+				output.WriteString(fmt.Sprintf("     Media Path %s: %s, %d ppm\n", path.Description, path.Type, path.PagesPerMinute))
+			} else {
+				output.WriteString(fmt.Sprintf("     Media Path %s: %s\n", path.Description, path.Type))
 			}
 		}
+		if len(capabilities.Languages) > 0 {
+			output.WriteString(fmt.Sprintf("     Languages: %s\n", strings.Join(capabilities.Languages, ", ")))
+		}
+	}
+	
+	// Show the device ID in a cleaner format
+	if p.DeviceID != "" {
+		output.WriteString("   Device ID:\n")
+		id := deviceid.Parse(p.DeviceID)
+		keys := make([]string, 0, len(id.Fields))
+		for key := range id.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			output.WriteString(fmt.Sprintf("     %s: %s\n", key, id.Fields[key]))
+		}
 	}
 	
 	return output.String()
 }
 
 // getDeviceIdentity collects device identity information (MVP Data Set)
-func (c *Client) getDeviceIdentity(status *PrinterStatus) {
-	identityOIDs := []string{
-		"1.3.6.1.2.1.1.1.0",                    // sysDescr.0 - general description
-		"1.3.6.1.2.1.1.5.0",                    // sysName.0 - device hostname
-		"1.3.6.1.2.1.43.5.1.1.16.1",           // prtGeneralPrinterName.1 - friendly printer name
+func (c *Client) getDeviceIdentity(g *gosnmp.GoSNMP, status *PrinterStatus) {
+	identityObjects := []string{
+		"sysDescr.0",                  // general description
+		"sysName.0",                   // device hostname
+		"prtGeneralPrinterName.1",     // friendly printer name
+		"sysLocation.0",               // physical location
+		"sysContact.0",                // responsible person
+		"sysObjectID.0",               // vendor's product OID
 	}
 
-	for _, oid := range identityOIDs {
-		result, err := gosnmp.Default.Get([]string{oid})
+	for _, name := range identityObjects {
+		oid := mib.MustResolve(name)
+		result, err := g.Get([]string{oid})
 		if err == nil && len(result.Variables) > 0 {
 			variable := result.Variables[0]
+			if variable.Type == gosnmp.ObjectIdentifier && name == "sysObjectID.0" {
+				status.ObjectID = strings.TrimPrefix(variable.Value.(string), ".")
+				status.Vendor = Vendor(status.ObjectID)
+				status.From("object_id", oid)
+				status.From("vendor", oid)
+			}
 			if variable.Type == gosnmp.OctetString {
 				value := string(variable.Value.([]byte))
-				switch oid {
-				case "1.3.6.1.2.1.1.1.0": // sysDescr.0
+				switch name {
+				case "sysDescr.0":
 					status.SystemDescription = value
-				case "1.3.6.1.2.1.1.5.0": // sysName.0
+					status.From("system_description", oid)
+				case "sysName.0":
+					// Only a name; a hostname is no identity to detect swaps or dedupe on
 					status.DeviceName = value
-				case "1.3.6.1.2.1.43.5.1.1.16.1": // prtGeneralPrinterName.1
+					status.From("device_name", oid)
+				case "prtGeneralPrinterName.1":
 					if value != "" {
 						status.PrinterName = value
+						status.From("printer_name", oid)
 					}
+				case "sysLocation.0":
+					status.Location = value
+					status.From("location", oid)
+				case "sysContact.0":
+					status.Contact = value
+					status.From("contact", oid)
 				}
 			}
 		}
@@ -715,41 +1059,29 @@ func (c *Client) getDeviceIdentity(status *PrinterStatus) {
 }
 
 // getDeviceStatus collects device status information (MVP Data Set)
-func (c *Client) getDeviceStatus(status *PrinterStatus) {
-	statusOIDs := []string{
-		"1.3.6.1.2.1.43.5.1.1.1.1",            // prtGeneralPrinterStatus.1
-		"1.3.6.1.2.1.1.3.0",                    // sysUpTime.0
-		"1.3.6.1.2.1.25.3.5.1.1.1",            // hrDeviceStatus
+func (c *Client) getDeviceStatus(g *gosnmp.GoSNMP, status *PrinterStatus) {
+	statusObjects := []string{
+		"sysUpTime.0",
+		"hrDeviceStatus.1",
 	}
 
-	for _, oid := range statusOIDs {
-		result, err := gosnmp.Default.Get([]string{oid})
+	for _, name := range statusObjects {
+		oid := mib.MustResolve(name)
+		result, err := g.Get([]string{oid})
 		if err == nil && len(result.Variables) > 0 {
 			variable := result.Variables[0]
-			switch oid {
-			case "1.3.6.1.2.1.43.5.1.1.1.1": // prtGeneralPrinterStatus.1
-				if variable.Type == gosnmp.Integer {
-					statusCode := int(variable.Value.(int))
-					switch statusCode {
-					case 0:
-						status.Status = "Idle"
-					case 3:
-						status.Status = "Idle"
-					case 4:
-						status.Status = "Printing"
-					case 5:
-						status.Status = "Warmup"
-					default:
-						status.Status = fmt.Sprintf("Status %d", statusCode)
-					}
-				}
-			case "1.3.6.1.2.1.1.3.0": // sysUpTime.0
+			switch name {
+			case "sysUpTime.0":
 				if variable.Type == gosnmp.TimeTicks {
 					status.Uptime = variable.Value.(uint32)
+					status.From("uptime", oid)
 				}
-			case "1.3.6.1.2.1.25.3.5.1.1.1": // hrDeviceStatus
+			case "hrDeviceStatus.1":
 				if variable.Type == gosnmp.Integer {
 					status.DeviceStatus = int(variable.Value.(int))
+					status.DeviceState = HRDeviceState(status.DeviceStatus)
+					status.From("device_status", oid)
+					status.From("device_state", oid)
 				}
 			}
 		}
@@ -757,18 +1089,19 @@ func (c *Client) getDeviceStatus(status *PrinterStatus) {
 }
 
 // getPageCounters collects page counter information (MVP Data Set)
-func (c *Client) getPageCounters(status *PrinterStatus) {
-	pageOIDs := []string{
-		"1.3.6.1.2.1.43.10.2.1.4.1.1",         // prtMarkerLifeCount.1.1
-		"1.3.6.1.2.1.43.10.2.1.3.1.1",         // prtMarkerCounterUnit.1.1
+func (c *Client) getPageCounters(g *gosnmp.GoSNMP, status *PrinterStatus) {
+	pageObjects := []string{
+		"prtMarkerLifeCount.1.1",
+		"prtMarkerCounterUnit.1.1",
 	}
 
-	for _, oid := range pageOIDs {
-		result, err := gosnmp.Default.Get([]string{oid})
+	for _, name := range pageObjects {
+		oid := mib.MustResolve(name)
+		result, err := g.Get([]string{oid})
 		if err == nil && len(result.Variables) > 0 {
 			variable := result.Variables[0]
-			switch oid {
-			case "1.3.6.1.2.1.43.10.2.1.4.1.1": // prtMarkerLifeCount.1.1
+			switch name {
+			case "prtMarkerLifeCount.1.1":
 				if variable.Type == gosnmp.Counter32 {
 					switch v := variable.Value.(type) {
 					case uint32:
@@ -778,10 +1111,12 @@ func (c *Client) getPageCounters(status *PrinterStatus) {
 					case int:
 						status.TotalPages = v
 					}
+					status.From("total_pages", oid)
 				}
-			case "1.3.6.1.2.1.43.10.2.1.3.1.1": // prtMarkerCounterUnit.1.1
+			case "prtMarkerCounterUnit.1.1":
 				if variable.Type == gosnmp.Integer {
 					status.PageCounterUnit = int(variable.Value.(int))
+					status.From("page_counter_unit", oid)
 				}
 			}
 		}
@@ -789,26 +1124,33 @@ func (c *Client) getPageCounters(status *PrinterStatus) {
 }
 
 // getAlertsAndErrors collects alert and error information (MVP Data Set)
-func (c *Client) getAlertsAndErrors(status *PrinterStatus) {
+func (c *Client) getAlertsAndErrors(g *gosnmp.GoSNMP, status *PrinterStatus) {
 	// Walk the prtAlertTable
 	status.ActiveAlerts = []string{}
 	alertCount := 0
 	
-	err := gosnmp.Default.Walk("1.3.6.1.2.1.43.18.1.1", func(variable gosnmp.SnmpPDU) error {
+	alertEntry := mib.MustResolve("prtAlertEntry")
+	err := g.Walk(alertEntry, func(variable gosnmp.SnmpPDU) error {
 		alertCount++
-		oid := variable.Name
+		oid := strings.TrimPrefix(variable.Name, ".")
 		var valueStr string
 		
 		if variable.Type == gosnmp.OctetString {
 			valueStr = string(variable.Value.([]byte))
 		} else if variable.Type == gosnmp.Integer {
 			valueStr = fmt.Sprintf("%d", variable.Value.(int))
+			if label := mib.Decode(oid, variable.Value.(int)); label != "" && valueStr != "0" {
+				valueStr = label // e.g. prtAlertCode 8 is "jam"
+			}
 		} else {
 			valueStr = fmt.Sprintf("%v", variable.Value)
 		}
 		
-		// Only include non-zero alerts
+		// Only include non-zero alerts, named e.g. "prtAlertDescription.1.5"
 		if valueStr != "0" && valueStr != "" {
+			if name := mib.Name(oid); name != "" {
+				oid = name
+			}
 			status.ActiveAlerts = append(status.ActiveAlerts, fmt.Sprintf("%s: %s", oid, valueStr))
 		}
 		
@@ -817,68 +1159,59 @@ func (c *Client) getAlertsAndErrors(status *PrinterStatus) {
 	
 	if err == nil {
 		status.ErrorCount = len(status.ActiveAlerts)
+		status.From("error_count", alertEntry) // Reported even when there are no alerts
 		if len(status.ActiveAlerts) > 0 {
 			status.LastError = status.ActiveAlerts[0] // First active alert
 		}
 	}
 }
 
+
 // getPaperTrays collects paper input/tray information (MVP Data Set)
-func (c *Client) getPaperTrays(status *PrinterStatus) {
+func (c *Client) getPaperTrays(g *gosnmp.GoSNMP, status *PrinterStatus) {
 	status.PaperTrays = []PaperTray{}
-	
-	// Walk the prtInputTable to collect tray information
-	trayData := make(map[string]map[string]interface{})
-	
-	err := gosnmp.Default.Walk("1.3.6.1.2.1.43.8.2.1", func(variable gosnmp.SnmpPDU) error {
-		oid := variable.Name
-		parts := strings.Split(oid, ".")
-		if len(parts) >= 4 {
-			// For OIDs like .1.3.6.1.2.1.43.8.2.1.13.1.1
-			// The structure is: baseOID.subOID.index1.index2
-			subOID := parts[len(parts)-3]
-			index1 := parts[len(parts)-2]
-			index2 := parts[len(parts)-1]
-			index := index1 + "." + index2
-			
-			if trayData[index] == nil {
-				trayData[index] = make(map[string]interface{})
-			}
-			
-			switch subOID {
-			case "13": // prtInputName
-				if variable.Type == gosnmp.OctetString {
-					trayData[index]["name"] = string(variable.Value.([]byte))
-				}
-			case "18": // prtInputStatus
-				if variable.Type == gosnmp.Integer {
-					trayData[index]["status"] = int(variable.Value.(int))
-				}
-			case "9": // prtInputCapacity
-				if variable.Type == gosnmp.Integer {
-					trayData[index]["capacity"] = int(variable.Value.(int))
-				}
-			}
+
+	// Walk the prtInputTable, rows are indexed by hrDeviceIndex.prtInputIndex
+	indexes, rows, err := walkTable(g, mib.MustResolve("prtInputEntry"))
+	if err != nil {
+		return
+	}
+
+	for _, index := range indexes {
+		row := rows[index]
+		trayStatus, hasStatus := row[11].(int) // prtInputStatus
+		name := row.text(13)                   // prtInputName
+		if !hasStatus && name == "" {
+			continue
 		}
-		return nil
-	})
-	
-	if err == nil {
-		// Convert tray data to PaperTray structs
-		for _, data := range trayData {
-			name, hasName := data["name"].(string)
-			trayStatus, hasStatus := data["status"].(int)
-			capacity, _ := data["capacity"].(int)
-			
-			if hasName && name != "" && hasStatus {
-				tray := PaperTray{
-					Index:    1, // We'll use a simple index
-					Name:     name,
-					Status:   trayStatus,
-					Capacity: capacity,
-				}
-				status.PaperTrays = append(status.PaperTrays, tray)
-			}
+
+		if !hasStatus {
+			trayStatus = SubUnitUnknown
+		}
+		
+		_, inputIndex, _ := strings.Cut(index, ".")
+		tray := PaperTray{
+			Name:         name,
+			Description:  row.text(18),  // prtInputDescription
+			Type:         row.number(2), // prtInputType
+			Status:       trayStatus,
+			CapacityUnit: row.number(8), // prtInputCapacityUnit
+			Capacity:     row.number(9), // prtInputMaxCapacity
+			Level:        -2,
+			MediaName:    row.text(12), // prtInputMediaName
+			MediaType:    row.text(21), // prtInputMediaType
+			MediaColor:   row.text(22), // prtInputMediaColor
+			// prtInputDimUnit, prtInputMediaDimXFeedDirDeclared and prtInputMediaDimFeedDirDeclared
+			MediaSize: NewMediaSize(row.number(3), row.number(5), row.number(4)),
+		}
+		tray.Index, _ = strconv.Atoi(inputIndex)
+		if level, ok := row[10].(int); ok { // prtInputCurrentLevel
+			tray.Level = level
+		}
+		if tray.Name == "" {
+			tray.Name = fmt.Sprintf("Tray %d", tray.Index)
 		}
+		tray.Decode()
+		status.PaperTrays = append(status.PaperTrays, tray)
 	}
 }
@@ -0,0 +1,32 @@
+package snmp
+
+import "testing"
+
+func TestNewSupply(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]interface{}
+		want    Supply
+		skipped bool
+	}{
+		{"level", map[string]interface{}{"class": 3, "description": "Black Toner", "maxCapacity": 2600, "currentLevel": 1300},
+			Supply{Index: "1.1", Type: 3, Description: "Black Toner", MaxCapacity: 2600, Level: 1300, Percent: 50}, false},
+		{"no level", map[string]interface{}{"class": 3, "description": "Black Toner", "maxCapacity": 2600},
+			Supply{Index: "1.1", Type: 3, Description: "Black Toner", MaxCapacity: 2600, Level: -2, Percent: -1}, false},
+		{"some remaining", map[string]interface{}{"class": 9, "description": "Drum", "maxCapacity": 12000, "currentLevel": -3},
+			Supply{Index: "1.1", Type: 9, Description: "Drum", MaxCapacity: 12000, Level: -3, Percent: -1}, false},
+		{"no capacity", map[string]interface{}{"class": 3, "currentLevel": 40},
+			Supply{Index: "1.1", Type: 3, Level: 40, Percent: -1}, false},
+		{"nothing to show", map[string]interface{}{"class": 3, "maxCapacity": 2600}, Supply{}, true},
+	}
+	for _, test := range tests {
+		got, ok := newSupply("1.1", test.data)
+		if ok == test.skipped {
+			t.Errorf("%s: kept = %t", test.name, ok)
+			continue
+		}
+		if ok && got != test.want {
+			t.Errorf("%s: supply = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
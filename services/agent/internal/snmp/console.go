@@ -0,0 +1,81 @@
+package snmp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+
+	"lynk/agent/internal/mib"
+)
+
+// ConsoleLight is an indicator light on the printer's front panel
+type ConsoleLight struct {
+	Index       int    `json:"index"`       // prtConsoleLightIndex
+	Description string `json:"description"` // prtConsoleDescription, e.g. "Error"
+	Color       string `json:"color"`       // prtConsoleColor: white, red, green, blue, cyan, magenta, yellow, orange, other or unknown
+	State       string `json:"state"`       // on, off or blink
+	OnTime      int    `json:"on_time"`     // prtConsoleOnTime in milliseconds
+	OffTime     int    `json:"off_time"`    // prtConsoleOffTime in milliseconds
+}
+
+// String formats the light for the console, e.g. "Error: blink (orange)"
+func (l ConsoleLight) String() string {
+	return fmt.Sprintf("%s: %s (%s)", l.Description, l.State, l.Color)
+}
+
+// lightState derives a light's state from its on and off times. A light that
+// is on for a time and then off for a time is blinking.
+func lightState(onTime, offTime int) string {
+	switch {
+	case onTime > 0 && offTime > 0:
+		return "blink"
+	case onTime != 0:
+		return "on"
+	}
+	return "off"
+}
+
+// getConsole collects the front panel's display text and indicator lights
+func (c *Client) getConsole(g *gosnmp.GoSNMP, status *PrinterStatus) {
+	// prtConsoleDisplayBufferTable, one row per display line
+	indexes, rows, err := walkTable(g, mib.MustResolve("prtConsoleDisplayBufferEntry"))
+	if err == nil {
+		status.DisplayLines = []string{}
+		for _, index := range indexes {
+			if text, ok := rows[index][2].(string); ok { // prtConsoleDisplayBufferText
+				status.DisplayLines = append(status.DisplayLines, strings.TrimRight(text, " "))
+			}
+		}
+	}
+
+	// prtConsoleLightTable
+	indexes, rows, err = walkTable(g, mib.MustResolve("prtConsoleLightEntry"))
+	if err != nil {
+		return
+	}
+	status.Lights = []ConsoleLight{}
+	for _, index := range indexes {
+		row := rows[index]
+		onTime, hasOn := row[2].(int) // prtConsoleOnTime
+		offTime := row.number(3)      // prtConsoleOffTime
+		if !hasOn {
+			continue
+		}
+
+		light := ConsoleLight{
+			Description: row.text(5), // prtConsoleDescription
+			Color:       enumName("prtConsoleColor", row.number(4), ""),
+			State:       lightState(onTime, offTime),
+			OnTime:      onTime,
+			OffTime:     offTime,
+		}
+		_, lightIndex, _ := strings.Cut(index, ".")
+		light.Index, _ = strconv.Atoi(lightIndex)
+		if light.Description == "" {
+			light.Description = fmt.Sprintf("Light %d", light.Index)
+		}
+		status.Lights = append(status.Lights, light)
+	}
+}
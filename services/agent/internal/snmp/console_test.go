@@ -0,0 +1,30 @@
+package snmp
+
+import "testing"
+
+func TestLightState(t *testing.T) {
+	tests := []struct {
+		onTime  int
+		offTime int
+		want    string
+	}{
+		{0, 0, "off"},
+		{0, 500, "off"},
+		{1000, 0, "on"},
+		{-1, 0, "on"}, // On indefinitely
+		{500, 500, "blink"},
+		{-1, 500, "on"},
+	}
+	for _, test := range tests {
+		if got := lightState(test.onTime, test.offTime); got != test.want {
+			t.Errorf("lightState(%d, %d) = %q, want %q", test.onTime, test.offTime, got, test.want)
+		}
+	}
+}
+
+func TestConsoleLightString(t *testing.T) {
+	light := ConsoleLight{Description: "Error", Color: "orange", State: lightState(500, 500)}
+	if got := light.String(); got != "Error: blink (orange)" {
+		t.Errorf("String = %q", got)
+	}
+}
@@ -0,0 +1,57 @@
+package snmp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+
+	"lynk/agent/internal/mib"
+)
+
+// Cover is a door or cover of the printer
+type Cover struct {
+	Index       int    `json:"index"`       // prtCoverIndex
+	Description string `json:"description"` // prtCoverDescription, e.g. "Front Cover"
+	Status      int    `json:"status"`      // prtCoverStatus
+	State       string `json:"state"`       // open, closed, interlock_open, interlock_closed, other or unknown
+	Open        bool   `json:"open"`        // The cover or its interlock is open
+}
+
+// String formats the cover for the console, e.g. "Front Cover: open"
+func (c Cover) String() string {
+	return fmt.Sprintf("%s: %s", c.Description, strings.ReplaceAll(c.State, "_", " "))
+}
+
+// getCovers collects the prtCoverTable
+func (c *Client) getCovers(g *gosnmp.GoSNMP, status *PrinterStatus) {
+	// Rows are indexed by hrDeviceIndex.prtCoverIndex
+	indexes, rows, err := walkTable(g, mib.MustResolve("prtCoverEntry"))
+	if err != nil {
+		return // Skip covers if the walk fails
+	}
+
+	status.Covers = []Cover{}
+	for _, index := range indexes {
+		row := rows[index]
+		coverStatus, ok := row[3].(int) // prtCoverStatus
+		if !ok {
+			continue
+		}
+
+		state := enumName("prtCoverStatus", coverStatus, "cover")
+		cover := Cover{
+			Description: row.text(2), // prtCoverDescription
+			Status:      coverStatus,
+			State:       state,
+			Open:        state == "open" || state == "interlock_open",
+		}
+		_, coverIndex, _ := strings.Cut(index, ".")
+		cover.Index, _ = strconv.Atoi(coverIndex)
+		if cover.Description == "" {
+			cover.Description = fmt.Sprintf("Cover %d", cover.Index)
+		}
+		status.Covers = append(status.Covers, cover)
+	}
+}
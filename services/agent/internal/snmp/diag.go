@@ -0,0 +1,188 @@
+package snmp
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gosnmp/gosnmp"
+
+	"lynk/agent/internal/mib"
+)
+
+// Varbind is one OID and its value as a printer returned it
+type Varbind struct {
+	OID   string      `json:"oid"`
+	Name  string      `json:"name,omitempty"`  // MIB name, e.g. prtMarkerSuppliesLevel.1.1, if the OID is known
+	Type  string      `json:"type"`            // ASN.1 type, e.g. OctetString, Counter32 or NoSuchObject
+	Value interface{} `json:"value"`           // Strings that aren't printable are hex encoded
+	Label string      `json:"label,omitempty"` // Enumeration label of the value, or the name of an OID value
+}
+
+// String formats the varbind like snmpget does, e.g.
+// "1.3.6.1.2.1.1.5.0 (sysName.0) = OctetString: "BRN3C2AF4""
+func (v Varbind) String() string {
+	name := v.OID
+	if v.Name != "" {
+		name = fmt.Sprintf("%s (%s)", v.OID, v.Name)
+	}
+	switch value := v.Value.(type) {
+	case nil:
+		return fmt.Sprintf("%s = %s", name, v.Type)
+	case string:
+		if v.Type == gosnmp.OctetString.String() {
+			return fmt.Sprintf("%s = %s: %q", name, v.Type, value)
+		}
+	}
+	if v.Label != "" {
+		return fmt.Sprintf("%s = %s: %s(%v)", name, v.Type, v.Label, v.Value)
+	}
+	return fmt.Sprintf("%s = %s: %v", name, v.Type, v.Value)
+}
+
+// Response is the varbinds a single SNMP request returned and how long it took
+type Response struct {
+	Request  int           `json:"request"` // 1-based, in the order the requests were sent
+	Elapsed  time.Duration `json:"elapsed"`
+	Varbinds []Varbind     `json:"varbinds"`
+}
+
+// Get reads the given OIDs from host in a single request
+func (c *Client) Get(ctx context.Context, host string, oids []string) (*Response, error) {
+	g, err := c.connect(ctx, host, c.timeout, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer g.Conn.Close()
+
+	start := time.Now()
+	result, err := g.Get(oids)
+	if err != nil {
+		return nil, fmt.Errorf("get from %s: %w", host, err)
+	}
+	return &Response{Request: 1, Elapsed: time.Since(start), Varbinds: varbinds(result.Variables)}, nil
+}
+
+// Walk walks the subtree under root on host with GETBULK requests, calling fn
+// with the varbinds of each request as it arrives. An empty root walks the
+// whole MIB-2 tree.
+func (c *Client) Walk(ctx context.Context, host, root string, fn func(*Response) error) error {
+	if root == "" {
+		root = mib.MustResolve("mib-2")
+	}
+	root = strings.TrimPrefix(root, ".")
+
+	g, err := c.connect(ctx, host, c.timeout, 1)
+	if err != nil {
+		return err
+	}
+	defer g.Conn.Close()
+
+	return walk(g, host, root, fn)
+}
+
+// bulkGetter sends GETBULK requests, a gosnmp session outside of tests
+type bulkGetter interface {
+	GetBulk(oids []string, nonRepeaters uint8, maxRepetitions uint32) (*gosnmp.SnmpPacket, error)
+}
+
+// walk is Walk over an open session
+func walk(g bulkGetter, host, root string, fn func(*Response) error) error {
+	next := root
+	for request := 1; ; request++ {
+		start := time.Now()
+		result, err := g.GetBulk([]string{next}, 0, 25)
+		if err != nil {
+			return fmt.Errorf("walk %s on %s: %w", root, host, err)
+		}
+		elapsed := time.Since(start)
+
+		// Keep the varbinds inside the subtree, the last ones may run past it
+		var inside []gosnmp.SnmpPDU
+		done := len(result.Variables) == 0
+		for _, variable := range result.Variables {
+			oid := strings.TrimPrefix(variable.Name, ".")
+			if variable.Type == gosnmp.EndOfMibView || !strings.HasPrefix(oid, root+".") {
+				done = true
+				break
+			}
+			if oid == next {
+				done = true // The agent isn't advancing, stop instead of looping
+				break
+			}
+			inside = append(inside, variable)
+		}
+
+		if len(inside) > 0 || request == 1 {
+			if err := fn(&Response{Request: request, Elapsed: elapsed, Varbinds: varbinds(inside)}); err != nil {
+				return err
+			}
+		}
+		if done || len(inside) == 0 {
+			return nil
+		}
+		next = strings.TrimPrefix(inside[len(inside)-1].Name, ".")
+	}
+}
+
+// varbinds converts the variables of a response
+func varbinds(variables []gosnmp.SnmpPDU) []Varbind {
+	list := make([]Varbind, 0, len(variables))
+	for _, variable := range variables {
+		oid := strings.TrimPrefix(variable.Name, ".")
+		varbind := Varbind{
+			OID:   oid,
+			Name:  mib.Name(oid),
+			Type:  variable.Type.String(),
+			Value: varbindValue(variable),
+		}
+		switch value := varbind.Value.(type) {
+		case int:
+			varbind.Label = mib.Decode(oid, value)
+		case string:
+			if variable.Type == gosnmp.ObjectIdentifier {
+				varbind.Label = mib.Name(value)
+			}
+		}
+		list = append(list, varbind)
+	}
+	return list
+}
+
+// varbindValue converts a variable's value for printing. Octet strings are
+// kept as text when printable and hex encoded otherwise, OIDs lose their
+// leading dot.
+func varbindValue(variable gosnmp.SnmpPDU) interface{} {
+	switch variable.Type {
+	case gosnmp.OctetString, gosnmp.Opaque, gosnmp.BitString:
+		data, _ := variable.Value.([]byte)
+		text := strings.TrimRight(string(data), "\x00")
+		if printable(text) {
+			return text
+		}
+		return hex.EncodeToString(data)
+	case gosnmp.ObjectIdentifier:
+		oid, _ := variable.Value.(string)
+		return strings.TrimPrefix(oid, ".")
+	case gosnmp.Null, gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+		return nil
+	}
+	return variable.Value
+}
+
+// printable reports whether an octet string reads as text
+func printable(text string) bool {
+	if !utf8.ValidString(text) {
+		return false
+	}
+	for _, r := range text {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package snmp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// fakeWalker answers GETBULK requests from an ordered list of variables, at
+// most perRequest at a time
+type fakeWalker struct {
+	variables  []gosnmp.SnmpPDU
+	perRequest int
+	requests   []string
+	err        error
+}
+
+func (f *fakeWalker) GetBulk(oids []string, nonRepeaters uint8, maxRepetitions uint32) (*gosnmp.SnmpPacket, error) {
+	f.requests = append(f.requests, oids[0])
+	if f.err != nil {
+		return nil, f.err
+	}
+	start := 0
+	for i, variable := range f.variables {
+		if strings.TrimPrefix(variable.Name, ".") == oids[0] {
+			start = i + 1
+			break
+		}
+	}
+	end := min(start+f.perRequest, len(f.variables))
+	result := &gosnmp.SnmpPacket{Variables: append([]gosnmp.SnmpPDU{}, f.variables[start:end]...)}
+	if end == len(f.variables) {
+		result.Variables = append(result.Variables, gosnmp.SnmpPDU{Name: ".1.3.6.1.9", Type: gosnmp.EndOfMibView})
+	}
+	return result, nil
+}
+
+// system is the system group of a printer followed by the first object after it
+var system = []gosnmp.SnmpPDU{
+	{Name: ".1.3.6.1.2.1.1.1.0", Type: gosnmp.OctetString, Value: []byte("Brother HL-L2360D series")},
+	{Name: ".1.3.6.1.2.1.1.2.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.2435.2.3.9.1"},
+	{Name: ".1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(12345)},
+	{Name: ".1.3.6.1.2.1.1.5.0", Type: gosnmp.OctetString, Value: []byte("BRN3C2AF4\x00")},
+	{Name: ".1.3.6.1.2.1.1.6.0", Type: gosnmp.OctetString, Value: []byte{0xff, 0x00, 0x01}},
+	{Name: ".1.3.6.1.2.1.2.1.0", Type: gosnmp.Integer, Value: 2}, // ifNumber, past the subtree
+}
+
+func TestWalk(t *testing.T) {
+	walker := &fakeWalker{variables: system, perRequest: 2}
+	var lines []string
+	var sizes []int
+	err := walk(walker, "10.0.0.5", "1.3.6.1.2.1.1", func(response *Response) error {
+		sizes = append(sizes, len(response.Varbinds))
+		if response.Request != len(sizes) {
+			t.Errorf("request %d numbered %d", len(sizes), response.Request)
+		}
+		for _, varbind := range response.Varbinds {
+			lines = append(lines, varbind.String())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk: %v", err)
+	}
+
+	want := []string{
+		`1.3.6.1.2.1.1.1.0 (sysDescr.0) = OctetString: "Brother HL-L2360D series"`,
+		`1.3.6.1.2.1.1.2.0 (sysObjectID.0) = ObjectIdentifier: brother.2.3.9.1(1.3.6.1.4.1.2435.2.3.9.1)`,
+		`1.3.6.1.2.1.1.3.0 (sysUpTime.0) = TimeTicks: 12345`,
+		`1.3.6.1.2.1.1.5.0 (sysName.0) = OctetString: "BRN3C2AF4"`,
+		`1.3.6.1.2.1.1.6.0 (sysLocation.0) = OctetString: "ff0001"`,
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("walk =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+		t.Errorf("varbinds per request = %v, want [2 2 1]", sizes)
+	}
+	if walker.requests[1] != "1.3.6.1.2.1.1.2.0" {
+		t.Errorf("second request from %s, want the last OID returned", walker.requests[1])
+	}
+}
+
+func TestWalkStops(t *testing.T) {
+	// An empty subtree still reports the request
+	var responses []*Response
+	err := walk(&fakeWalker{variables: system, perRequest: 25}, "10.0.0.5", "1.3.6.1.2.1.43", func(response *Response) error {
+		responses = append(responses, response)
+		return nil
+	})
+	if err != nil || len(responses) != 1 || len(responses[0].Varbinds) != 0 {
+		t.Errorf("walk of an empty subtree = %+v, %v", responses, err)
+	}
+
+	// An agent that returns the OID it was asked for isn't asked again
+	stuck := &fakeWalker{variables: system[:1], perRequest: 1}
+	stuck.variables = append(stuck.variables, system[0])
+	var count int
+	walk(stuck, "10.0.0.5", "1.3.6.1.2.1.1", func(response *Response) error {
+		count += len(response.Varbinds)
+		return nil
+	})
+	if len(stuck.requests) != 2 || count != 1 {
+		t.Errorf("%d requests and %d varbinds from an agent that isn't advancing", len(stuck.requests), count)
+	}
+
+	// Errors of the callback and the agent end the walk
+	stop := errors.New("stop")
+	walker := &fakeWalker{variables: system, perRequest: 1}
+	if err := walk(walker, "10.0.0.5", "1.3.6.1.2.1.1", func(*Response) error { return stop }); err != stop || len(walker.requests) != 1 {
+		t.Errorf("callback error = %v after %d requests", err, len(walker.requests))
+	}
+	err = walk(&fakeWalker{err: errors.New("request timeout")}, "10.0.0.5", "1.3.6.1.2.1.1", func(*Response) error { return nil })
+	if err == nil || err.Error() != "walk 1.3.6.1.2.1.1 on 10.0.0.5: request timeout" {
+		t.Errorf("agent error = %v", err)
+	}
+}
+
+func TestVarbinds(t *testing.T) {
+	got := varbinds([]gosnmp.SnmpPDU{
+		{Name: ".1.3.6.1.2.1.25.3.5.1.1.1", Type: gosnmp.Integer, Value: 3},
+		{Name: ".1.3.6.1.2.1.43.11.1.1.9.1.1", Type: gosnmp.Integer, Value: -3},
+		{Name: ".1.3.6.1.2.1.43.5.1.1.17.1", Type: gosnmp.NoSuchInstance},
+		{Name: ".1.3.6.1.4.1.99999.1.0", Type: gosnmp.Counter32, Value: uint(42)},
+	})
+	want := []string{
+		"1.3.6.1.2.1.25.3.5.1.1.1 (hrPrinterStatus.1) = Integer: idle(3)",
+		"1.3.6.1.2.1.43.11.1.1.9.1.1 (prtMarkerSuppliesLevel.1.1) = Integer: -3",
+		"1.3.6.1.2.1.43.5.1.1.17.1 (prtGeneralSerialNumber.1) = NoSuchInstance",
+		"1.3.6.1.4.1.99999.1.0 (enterprises.99999.1.0) = Counter32: 42",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("varbinds = %+v", got)
+	}
+	for i, varbind := range got {
+		if varbind.String() != want[i] {
+			t.Errorf("varbind %d = %q, want %q", i, varbind.String(), want[i])
+		}
+	}
+	if got[0].Label != "idle" || got[2].Value != nil {
+		t.Errorf("label, value = %q, %v", got[0].Label, got[2].Value)
+	}
+
+	// Without a name or label the OID and value stand alone
+	plain := Varbind{OID: "1.3.6.1.2.1.1.5.0", Type: "OctetString", Value: "BRN3C2AF4"}
+	if got := plain.String(); got != `1.3.6.1.2.1.1.5.0 = OctetString: "BRN3C2AF4"` {
+		t.Errorf("String = %q", got)
+	}
+}
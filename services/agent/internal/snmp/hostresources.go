@@ -0,0 +1,226 @@
+package snmp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gosnmp/gosnmp"
+
+	"lynk/agent/internal/mib"
+)
+
+// Storage is an area of the printer's memory or disks, e.g. the RAM, the
+// flash holding the firmware or the hard disk scans and stored jobs go to
+type Storage struct {
+	Index              int    `json:"index"`               // hrStorageIndex
+	Type               string `json:"type"`                // hrStorageType, e.g. ram, flash_memory or fixed_disk
+	Description        string `json:"description"`         // hrStorageDescr
+	AllocationUnits    int    `json:"allocation_units"`    // hrStorageAllocationUnits, bytes
+	Size               int    `json:"size"`                // hrStorageSize, allocation units
+	Used               int    `json:"used"`                // hrStorageUsed, allocation units
+	SizeBytes          int64  `json:"size_bytes"`          // Size in bytes
+	UsedBytes          int64  `json:"used_bytes"`          // Used in bytes
+	Percent            int    `json:"percent"`             // Used as a percentage of Size, -1 if unknown
+	AllocationFailures int    `json:"allocation_failures"` // hrStorageAllocationFailures
+	Condition          string `json:"condition"`           // ok, warning, full or unknown
+}
+
+// Thresholds of the used percentage at which a disk is filling up and full.
+// Memory is always in use, so only disks and flash get a warning.
+const (
+	storageWarningPercent = 85
+	storageFullPercent    = 95
+)
+
+// Decode fills the byte counts, Percent and Condition from the raw units
+func (s *Storage) Decode() {
+	s.SizeBytes = int64(s.Size) * int64(s.AllocationUnits)
+	s.UsedBytes = int64(s.Used) * int64(s.AllocationUnits)
+	s.Percent = -1
+	if s.Size > 0 && s.Used >= 0 {
+		s.Percent = int(int64(s.Used) * 100 / int64(s.Size))
+		if s.Percent > 100 {
+			s.Percent = 100
+		}
+	}
+	s.Condition = s.condition()
+}
+
+// condition rolls the fill level of a disk up into a single word
+func (s Storage) condition() string {
+	switch {
+	case s.Percent < 0:
+		return "unknown"
+	case !s.Disk():
+		return "ok"
+	case s.Percent >= storageFullPercent:
+		return "full"
+	case s.Percent >= storageWarningPercent:
+		return "warning"
+	}
+	return "ok"
+}
+
+// Disk reports whether the storage keeps files, as opposed to working memory
+func (s Storage) Disk() bool {
+	switch s.Type {
+	case "fixed_disk", "removable_disk", "flash_memory", "network_disk":
+		return true
+	}
+	return false
+}
+
+// String formats the storage for the console, e.g.
+// "HDD (fixed_disk): 12.4 GB of 80.0 GB used (15%), ok"
+func (s Storage) String() string {
+	if s.Percent < 0 {
+		return fmt.Sprintf("%s (%s): size unknown", s.Description, s.Type)
+	}
+	return fmt.Sprintf("%s (%s): %s of %s used (%d%%), %s", s.Description, s.Type, formatBytes(s.UsedBytes), formatBytes(s.SizeBytes), s.Percent, s.Condition)
+}
+
+// Device is a component of the printer listed in the hrDeviceTable, e.g. the
+// print engine, a hard disk, a network interface or a processor
+type Device struct {
+	Index       int         `json:"index"`          // hrDeviceIndex
+	Type        string      `json:"type"`           // hrDeviceType, e.g. printer, disk_storage, network or processor
+	Description string      `json:"description"`    // hrDeviceDescr
+	Status      int         `json:"status"`         // hrDeviceStatus
+	State       DeviceState `json:"state"`          // Status decoded
+	Errors      int         `json:"errors"`         // hrDeviceErrors
+	Load        *int        `json:"load,omitempty"` // hrProcessorLoad in percent, processors only
+}
+
+// Failing reports whether the device is a disk that is down or reports an
+// error condition
+func (d Device) Failing() bool {
+	return d.Type == "disk_storage" && (d.State == DeviceDown || d.State == DeviceWarning)
+}
+
+// String formats the device for the console, e.g. "CPU (processor): running, load 12%"
+func (d Device) String() string {
+	text := fmt.Sprintf("%s (%s): %s", d.Description, d.Type, d.State)
+	if d.Errors > 0 {
+		text += fmt.Sprintf(", %d errors", d.Errors)
+	}
+	if d.Load != nil {
+		text += fmt.Sprintf(", load %d%%", *d.Load)
+	}
+	return text
+}
+
+// getStorage reads hrMemorySize and the hrStorageTable
+func (c *Client) getStorage(g *gosnmp.GoSNMP, status *PrinterStatus) {
+	oid := mib.MustResolve("hrMemorySize.0")
+	result, err := g.Get([]string{oid})
+	if err == nil && len(result.Variables) > 0 && result.Variables[0].Type == gosnmp.Integer {
+		if kilobytes := result.Variables[0].Value.(int); kilobytes > 0 {
+			status.MemoryKB = kilobytes
+			status.MemorySize = formatBytes(int64(kilobytes) * 1024)
+			status.From("memory_kb", oid)
+			status.From("memory_size", oid)
+		}
+	}
+
+	indexes, rows, err := walkTable(g, mib.MustResolve("hrStorageEntry"))
+	if err != nil {
+		return // Skip storage if the walk fails
+	}
+
+	status.Storage = []Storage{}
+	for _, index := range indexes {
+		row := rows[index]
+		storage := Storage{
+			Type:               hrTypeName(row.text(2), "hrStorage"), // hrStorageType
+			Description:        row.text(3),                          // hrStorageDescr
+			AllocationUnits:    row.number(4),                        // hrStorageAllocationUnits
+			Size:               row.number(5),                        // hrStorageSize
+			Used:               row.number(6),                        // hrStorageUsed
+			AllocationFailures: row.number(7),                        // hrStorageAllocationFailures
+		}
+		storage.Index, _ = strconv.Atoi(index)
+		if storage.Description == "" {
+			storage.Description = fmt.Sprintf("Storage %d", storage.Index)
+		}
+		storage.Decode()
+		status.Storage = append(status.Storage, storage)
+	}
+}
+
+// getDevices reads the hrDeviceTable and the load of the processors in it
+func (c *Client) getDevices(g *gosnmp.GoSNMP, status *PrinterStatus) {
+	indexes, rows, err := walkTable(g, mib.MustResolve("hrDeviceEntry"))
+	if err != nil {
+		return // Skip devices if the walk fails
+	}
+
+	// hrProcessorTable rows are indexed by the processor's hrDeviceIndex
+	_, processors, err := walkTable(g, mib.MustResolve("hrProcessorEntry"))
+	if err != nil {
+		processors = nil
+	}
+
+	status.Devices = []Device{}
+	for _, index := range indexes {
+		row := rows[index]
+		device := Device{
+			Type:        hrTypeName(row.text(2), "hrDevice"), // hrDeviceType
+			Description: row.text(3),                         // hrDeviceDescr
+			Status:      row.number(5),                       // hrDeviceStatus
+			Errors:      row.number(6),                       // hrDeviceErrors
+		}
+		device.Index, _ = strconv.Atoi(index)
+		device.State = HRDeviceState(device.Status)
+		if device.Description == "" {
+			device.Description = fmt.Sprintf("Device %d", device.Index)
+		}
+		if load, ok := processors[index][2].(int); ok { // hrProcessorLoad
+			device.Load = &load
+		}
+		status.Devices = append(status.Devices, device)
+	}
+}
+
+// hrTypeName names an hrStorageType or hrDeviceType OID after its
+// HOST-RESOURCES-TYPES object without the prefix, e.g. hrStorageFixedDisk
+// as fixed_disk. Types the MIB doesn't define, such as vendor ones, are other.
+func hrTypeName(oid, prefix string) string {
+	if oid == "" {
+		return "unknown"
+	}
+	name, ok := strings.CutPrefix(mib.Name(oid), prefix)
+	if !ok || name == "" || strings.Contains(name, ".") {
+		return "other"
+	}
+
+	var words strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			words.WriteByte('_')
+		}
+		words.WriteRune(unicode.ToLower(r))
+	}
+	return words.String()
+}
+
+// formatBytes formats a byte count in binary units, e.g. "512 MB" or "1.5 GB"
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value, suffix := float64(bytes)/unit, "KB"
+	for _, next := range []string{"MB", "GB", "TB"} {
+		if math.Round(value*10)/10 < unit { // Not "1024.0 KB"
+			break
+		}
+		value, suffix = value/unit, next
+	}
+	if value == float64(int64(value)) {
+		return fmt.Sprintf("%d %s", int64(value), suffix)
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
@@ -0,0 +1,95 @@
+package snmp
+
+import "testing"
+
+func TestHRTypeName(t *testing.T) {
+	tests := []struct {
+		oid    string
+		prefix string
+		want   string
+	}{
+		{"1.3.6.1.2.1.25.2.1.2", "hrStorage", "ram"},
+		{"1.3.6.1.2.1.25.2.1.4", "hrStorage", "fixed_disk"},
+		{".1.3.6.1.2.1.25.2.1.9", "hrStorage", "flash_memory"},
+		{"1.3.6.1.2.1.25.3.1.6", "hrDevice", "disk_storage"},
+		{"1.3.6.1.2.1.25.3.1.21", "hrDevice", "non_volatile_memory"},
+		{"1.3.6.1.2.1.25.2.1.99", "hrStorage", "other"}, // Not defined, hrStorageTypes.99
+		{"1.3.6.1.2.1.25.2", "hrStorage", "other"},      // The group, not a type
+		{"1.3.6.1.2.1.25.2.1.4", "hrDevice", "other"},   // A storage type as a device type
+		{"1.3.6.1.4.1.11.2.3.9.1", "hrDevice", "other"}, // Vendor type
+		{"0.0", "hrStorage", "other"},
+		{"", "hrStorage", "unknown"},
+	}
+	for _, test := range tests {
+		if got := hrTypeName(test.oid, test.prefix); got != test.want {
+			t.Errorf("hrTypeName(%q, %s) = %q, want %q", test.oid, test.prefix, got, test.want)
+		}
+	}
+}
+
+func TestStorageDecode(t *testing.T) {
+	tests := []struct {
+		name      string
+		storage   Storage
+		sizeBytes int64
+		usedBytes int64
+		percent   int
+		condition string
+	}{
+		{"memory is never full", Storage{Type: "ram", AllocationUnits: 1024, Size: 65536, Used: 65000}, 67108864, 66560000, 99, "ok"},
+		{"disk", Storage{Type: "fixed_disk", AllocationUnits: 4096, Size: 1000, Used: 500}, 4096000, 2048000, 50, "ok"},
+		{"disk filling up", Storage{Type: "fixed_disk", AllocationUnits: 4096, Size: 1000, Used: 850}, 4096000, 3481600, 85, "warning"},
+		{"disk full", Storage{Type: "flash_memory", AllocationUnits: 512, Size: 1000, Used: 950}, 512000, 486400, 95, "full"},
+		{"used past the size", Storage{Type: "network_disk", AllocationUnits: 1, Size: 100, Used: 120}, 100, 120, 100, "full"},
+		{"no size", Storage{Type: "fixed_disk", AllocationUnits: 4096}, 0, 0, -1, "unknown"},
+		{"used unknown", Storage{Type: "fixed_disk", AllocationUnits: 4096, Size: 1000, Used: -1}, 4096000, -4096, -1, "unknown"},
+		{"units past 32 bits", Storage{Type: "fixed_disk", AllocationUnits: 65536, Size: 2000000000, Used: 1000000000}, 131072000000000, 65536000000000, 50, "ok"},
+		{"large counts", Storage{Type: "fixed_disk", AllocationUnits: 1, Size: 2147483647, Used: 2147483647}, 2147483647, 2147483647, 100, "full"},
+	}
+	for _, test := range tests {
+		storage := test.storage
+		storage.Decode()
+		if storage.SizeBytes != test.sizeBytes || storage.UsedBytes != test.usedBytes ||
+			storage.Percent != test.percent || storage.Condition != test.condition {
+			t.Errorf("%s: bytes %d of %d, %d%%, %s; want %d of %d, %d%%, %s", test.name,
+				storage.UsedBytes, storage.SizeBytes, storage.Percent, storage.Condition,
+				test.usedBytes, test.sizeBytes, test.percent, test.condition)
+		}
+	}
+}
+
+func TestStorageString(t *testing.T) {
+	disk := Storage{Type: "fixed_disk", Description: "HDD", AllocationUnits: 4096, Size: 262144, Used: 39322}
+	disk.Decode()
+	if got := disk.String(); got != "HDD (fixed_disk): 153.6 MB of 1 GB used (15%), ok" {
+		t.Errorf("String = %q", got)
+	}
+	unknown := Storage{Type: "ram", Description: "RAM"}
+	unknown.Decode()
+	if got := unknown.String(); got != "RAM (ram): size unknown" {
+		t.Errorf("String without a size = %q", got)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1 KB"},
+		{1536, "1.5 KB"},
+		{1048575, "1.0 MB"}, // Rounds up to the next unit
+		{1048000, "1023.4 KB"},
+		{512 << 20, "512 MB"},
+		{3 << 29, "1.5 GB"},
+		{1 << 40, "1 TB"},
+		{2048 << 40, "2048 TB"}, // No unit past terabytes
+	}
+	for _, test := range tests {
+		if got := formatBytes(test.bytes); got != test.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", test.bytes, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package snmp
+
+import (
+	"math"
+	"strconv"
+)
+
+// MediaSize is the size of a sheet of media in millimetres
+type MediaSize struct {
+	Width  float64 `json:"width_mm"`  // Across the feed direction
+	Length float64 `json:"length_mm"` // Along the feed direction
+}
+
+// Printer-MIB dimension units (PrtMediaUnitTC)
+const (
+	unitTenThousandthsOfInches = 3
+	unitMicrometers            = 4
+)
+
+// NewMediaSize converts a pair of Printer-MIB dimensions in a PrtMediaUnitTC
+// unit to millimetres. Unknown dimensions (negative values) or units return nil.
+func NewMediaSize(unit, width, length int) *MediaSize {
+	if width <= 0 || length <= 0 {
+		return nil
+	}
+
+	var scale float64
+	switch unit {
+	case unitTenThousandthsOfInches:
+		scale = 25.4 / 10000
+	case unitMicrometers:
+		scale = 1.0 / 1000
+	default:
+		return nil
+	}
+	round := func(v float64) float64 { return math.Round(v*10) / 10 }
+	return &MediaSize{Width: round(float64(width) * scale), Length: round(float64(length) * scale)}
+}
+
+// standardSizes are common paper sizes in millimetres, short edge first
+var standardSizes = []struct {
+	name        string
+	short, long float64
+}{
+	{"A3", 297, 420},
+	{"A4", 210, 297},
+	{"A5", 148, 210},
+	{"A6", 105, 148},
+	{"B4", 257, 364},
+	{"B5", 182, 257},
+	{"Letter", 215.9, 279.4},
+	{"Legal", 215.9, 355.6},
+	{"Tabloid", 279.4, 431.8},
+	{"Executive", 184.2, 266.7},
+	{"SRA3", 320, 450},
+}
+
+// Name returns the standard name of the size in either orientation, e.g. "A4",
+// or "" for a custom size
+func (m MediaSize) Name() string {
+	short, long := math.Min(m.Width, m.Length), math.Max(m.Width, m.Length)
+	for _, size := range standardSizes {
+		if math.Abs(short-size.short) <= 2 && math.Abs(long-size.long) <= 2 {
+			return size.name
+		}
+	}
+	return ""
+}
+
+// area is the size's area in square millimetres, to compare sizes
+func (m MediaSize) area() float64 {
+	return m.Width * m.Length
+}
+
+// String formats the size, e.g. "A4 (210 x 297 mm)"
+func (m MediaSize) String() string {
+	dimensions := strconv.FormatFloat(m.Width, 'f', -1, 64) + " x " + strconv.FormatFloat(m.Length, 'f', -1, 64) + " mm"
+	if name := m.Name(); name != "" {
+		return name + " (" + dimensions + ")"
+	}
+	return dimensions
+}
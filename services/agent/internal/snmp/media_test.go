@@ -0,0 +1,45 @@
+package snmp
+
+import "testing"
+
+func TestNewMediaSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		unit          int
+		width, length int
+		want          *MediaSize
+	}{
+		{"A4 in micrometers", unitMicrometers, 210000, 297000, &MediaSize{Width: 210, Length: 297}},
+		{"Letter in ten thousandths of inches", unitTenThousandthsOfInches, 85000, 110000, &MediaSize{Width: 215.9, Length: 279.4}},
+		{"rounded to a tenth", unitTenThousandthsOfInches, 82677, 116929, &MediaSize{Width: 210, Length: 297}},
+		{"unknown width", unitMicrometers, -2, 297000, nil},
+		{"other dimension", unitMicrometers, 210000, -1, nil},
+		{"zero", unitMicrometers, 0, 0, nil},
+		{"unknown unit", 5, 210000, 297000, nil},
+	}
+	for _, test := range tests {
+		got := NewMediaSize(test.unit, test.width, test.length)
+		switch {
+		case got == nil && test.want == nil:
+		case got == nil || test.want == nil || *got != *test.want:
+			t.Errorf("%s: NewMediaSize = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestMediaSizeString(t *testing.T) {
+	tests := []struct {
+		size MediaSize
+		want string
+	}{
+		{MediaSize{Width: 210, Length: 297}, "A4 (210 x 297 mm)"},
+		{MediaSize{Width: 297, Length: 210}, "A4 (297 x 210 mm)"}, // Landscape
+		{MediaSize{Width: 215.9, Length: 279.4}, "Letter (215.9 x 279.4 mm)"},
+		{MediaSize{Width: 100, Length: 200}, "100 x 200 mm"},
+	}
+	for _, test := range tests {
+		if got := test.size.String(); got != test.want {
+			t.Errorf("String = %q, want %q", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package snmp
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+
+	"lynk/agent/internal/mib"
+)
+
+// NetworkInterface is a network interface of the printer
+type NetworkInterface struct {
+	Index       int      `json:"index"`                  // ifIndex
+	Description string   `json:"description"`            // ifDescr
+	Type        int      `json:"type"`                   // ifType (6=ethernetCsmacd, 24=softwareLoopback, 71=ieee80211)
+	MACAddress  string   `json:"mac_address,omitempty"`  // ifPhysAddress
+	OperStatus  int      `json:"oper_status"`            // ifOperStatus (1=up, 2=down)
+	IPAddresses []string `json:"ip_addresses,omitempty"` // ipAdEntAddr of the entries with this ipAdEntIfIndex
+}
+
+// vendors maps IANA private enterprise numbers to printer vendors
+var vendors = map[string]string{
+	"11":    "HP",
+	"253":   "Xerox",
+	"367":   "Ricoh",
+	"641":   "Lexmark",
+	"1248":  "Epson",
+	"1347":  "Kyocera",
+	"1602":  "Canon",
+	"2385":  "Sharp",
+	"2435":  "Brother",
+	"18334": "Konica Minolta",
+}
+
+// Vendor returns the vendor of a sysObjectID such as "1.3.6.1.4.1.2435.2.3.9.1",
+// or "" when the enterprise is unknown
+func Vendor(objectID string) string {
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(objectID, "."), mib.MustResolve("enterprises")+".")
+	if !ok {
+		return ""
+	}
+	enterprise, _, _ := strings.Cut(rest, ".")
+	return vendors[enterprise]
+}
+
+// getNetworkInterfaces walks the ifTable and ipAddrTable for the printer's
+// MAC and IP addresses
+func (c *Client) getNetworkInterfaces(g *gosnmp.GoSNMP, status *PrinterStatus) {
+	ifEntry := mib.MustResolve("ifEntry")
+	ipAddrEntry := mib.MustResolve("ipAddrEntry")
+
+	interfaces := make(map[int]*NetworkInterface)
+	err := g.Walk(ifEntry, func(variable gosnmp.SnmpPDU) error {
+		column, index, ok := tableColumn(variable.Name, ifEntry)
+		if !ok {
+			return nil
+		}
+		ifIndex, err := strconv.Atoi(index)
+		if err != nil {
+			return nil
+		}
+		if interfaces[ifIndex] == nil {
+			interfaces[ifIndex] = &NetworkInterface{Index: ifIndex}
+		}
+		iface := interfaces[ifIndex]
+
+		switch column {
+		case 2: // ifDescr
+			if variable.Type == gosnmp.OctetString {
+				iface.Description = strings.TrimRight(string(variable.Value.([]byte)), "\x00")
+			}
+		case 3: // ifType
+			if variable.Type == gosnmp.Integer {
+				iface.Type = variable.Value.(int)
+			}
+		case 6: // ifPhysAddress
+			if variable.Type == gosnmp.OctetString {
+				iface.MACAddress = macAddress(variable.Value.([]byte))
+			}
+		case 8: // ifOperStatus
+			if variable.Type == gosnmp.Integer {
+				iface.OperStatus = variable.Value.(int)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return // Skip interfaces if the walk fails
+	}
+
+	// ipAddrTable rows are indexed by the address itself
+	g.Walk(ipAddrEntry, func(variable gosnmp.SnmpPDU) error {
+		column, address, ok := tableColumn(variable.Name, ipAddrEntry)
+		if !ok || column != 2 { // ipAdEntIfIndex
+			return nil
+		}
+		if variable.Type != gosnmp.Integer {
+			return nil
+		}
+		if iface := interfaces[variable.Value.(int)]; iface != nil {
+			iface.IPAddresses = append(iface.IPAddresses, address)
+		}
+		return nil
+	})
+
+	status.Interfaces = []NetworkInterface{}
+	for _, iface := range interfaces {
+		sort.Strings(iface.IPAddresses)
+		status.Interfaces = append(status.Interfaces, *iface)
+	}
+	sort.Slice(status.Interfaces, func(i, j int) bool {
+		return status.Interfaces[i].Index < status.Interfaces[j].Index
+	})
+	status.MACAddress = primaryMAC(status.Interfaces, status.Host)
+}
+
+// macAddress formats an ifPhysAddress, returning "" for interfaces without one
+func macAddress(raw []byte) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	zero := true
+	for _, b := range raw {
+		if b != 0 {
+			zero = false
+		}
+	}
+	if zero {
+		return ""
+	}
+	return net.HardwareAddr(raw).String()
+}
+
+// primaryMAC picks the MAC address that identifies the printer: the one of the
+// interface the agent polls, otherwise the first interface that has one
+func primaryMAC(interfaces []NetworkInterface, host string) string {
+	for _, iface := range interfaces {
+		for _, address := range iface.IPAddresses {
+			if address == host && iface.MACAddress != "" {
+				return iface.MACAddress
+			}
+		}
+	}
+	for _, iface := range interfaces {
+		if iface.MACAddress != "" {
+			return iface.MACAddress
+		}
+	}
+	return ""
+}
+
+// String formats the interface for the console
+func (i NetworkInterface) String() string {
+	text := i.Description
+	if text == "" {
+		text = fmt.Sprintf("Interface %d", i.Index)
+	}
+	if i.MACAddress != "" {
+		text += " " + i.MACAddress
+	}
+	if len(i.IPAddresses) > 0 {
+		text += " (" + strings.Join(i.IPAddresses, ", ") + ")"
+	}
+	return text
+}
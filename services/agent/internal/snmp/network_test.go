@@ -0,0 +1,64 @@
+package snmp
+
+import "testing"
+
+func TestPrimaryMAC(t *testing.T) {
+	loopback := NetworkInterface{Index: 1, Description: "lo", IPAddresses: []string{"127.0.0.1"}}
+	wired := NetworkInterface{Index: 2, Description: "eth0", MACAddress: "00:80:77:31:01:07", IPAddresses: []string{"10.0.0.5"}}
+	wireless := NetworkInterface{Index: 3, Description: "wlan0", MACAddress: "3c:2a:f4:00:00:01", IPAddresses: []string{"10.0.1.5"}}
+	unaddressed := NetworkInterface{Index: 4, Description: "eth1", IPAddresses: []string{"10.0.2.5"}}
+
+	tests := []struct {
+		name       string
+		interfaces []NetworkInterface
+		host       string
+		want       string
+	}{
+		{"polled interface", []NetworkInterface{loopback, wired, wireless}, "10.0.1.5", "3c:2a:f4:00:00:01"},
+		{"polled by name", []NetworkInterface{loopback, wired, wireless}, "printer.example.com", "00:80:77:31:01:07"},
+		{"polled interface without a MAC", []NetworkInterface{unaddressed, wireless}, "10.0.2.5", "3c:2a:f4:00:00:01"},
+		{"no MAC at all", []NetworkInterface{loopback, unaddressed}, "10.0.2.5", ""},
+		{"no interfaces", nil, "10.0.0.5", ""},
+	}
+	for _, test := range tests {
+		if got := primaryMAC(test.interfaces, test.host); got != test.want {
+			t.Errorf("%s: primaryMAC = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestMACAddress(t *testing.T) {
+	tests := []struct {
+		raw  []byte
+		want string
+	}{
+		{[]byte{0x00, 0x80, 0x77, 0x31, 0x01, 0x07}, "00:80:77:31:01:07"},
+		{[]byte{0, 0, 0, 0, 0, 0}, ""},
+		{nil, ""},
+	}
+	for _, test := range tests {
+		if got := macAddress(test.raw); got != test.want {
+			t.Errorf("macAddress(%x) = %q, want %q", test.raw, got, test.want)
+		}
+	}
+}
+
+func TestVendor(t *testing.T) {
+	tests := []struct {
+		objectID string
+		want     string
+	}{
+		{"1.3.6.1.4.1.2435.2.3.9.1", "Brother"},
+		{".1.3.6.1.4.1.11.2.3.9.1", "HP"},
+		{"1.3.6.1.4.1.18334.1.1.1.2.1.52.3.3", "Konica Minolta"},
+		{"1.3.6.1.4.1.24350.1", ""}, // Not Brother: the enterprise is matched whole
+		{"1.3.6.1.4.1.99999.1", ""},
+		{"1.3.6.1.2.1.1", ""},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := Vendor(test.objectID); got != test.want {
+			t.Errorf("Vendor(%q) = %q, want %q", test.objectID, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package snmp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+
+	"lynk/agent/internal/mib"
+)
+
+// OutputBin represents an output bin that printed pages are delivered to
+type OutputBin struct {
+	Index         int           `json:"index"`                 // prtOutputIndex
+	Name          string        `json:"name"`                  // prtOutputName
+	Description   string        `json:"description,omitempty"` // prtOutputDescription
+	Type          int           `json:"type"`                  // prtOutputType (3=removable bin, 4=fixed bin, 6=mailbox, ...)
+	Status        int           `json:"status"`                // prtOutputStatus, a SubUnitStatus bitfield
+	State         SubUnitStatus `json:"state"`                 // Status decoded
+	Condition     string        `json:"condition"`             // ok, full, warning, error, offline or unknown
+	CapacityUnit  int           `json:"capacity_unit"`         // prtOutputCapacityUnit (8=sheets, ...)
+	Capacity      int           `json:"capacity"`              // prtOutputMaxCapacity
+	Remaining     int           `json:"remaining"`             // prtOutputRemainingCapacity (-2=unknown, -3=at least one)
+	PercentFull   int           `json:"percent_full"`          // Used capacity as a percentage of Capacity, -1 if unknown
+	StackingOrder string        `json:"stacking_order"`        // prtOutputStackingOrder: first_to_last, last_to_first or unknown
+}
+
+// Decode fills State, PercentFull and Condition from the raw Status, Remaining and Capacity
+func (o *OutputBin) Decode() {
+	o.State = DecodeSubUnitStatus(o.Status)
+	o.PercentFull = -1
+	if o.Capacity > 0 && o.Remaining >= 0 && o.Remaining <= o.Capacity {
+		o.PercentFull = (o.Capacity - o.Remaining) * 100 / o.Capacity
+	}
+	o.Condition = o.condition()
+}
+
+// condition rolls an output bin's status and remaining capacity up into a single word
+func (o OutputBin) condition() string {
+	switch {
+	case o.State.OffLine:
+		return "offline"
+	case o.State.Availability == "broken":
+		return "error"
+	case o.Remaining == 0:
+		return "full"
+	case o.State.CriticalAlert:
+		return "error"
+	case o.State.NonCriticalAlert:
+		return "warning"
+	case o.State.Availability == "unknown":
+		return "unknown"
+	}
+	return "ok"
+}
+
+// String formats the output bin for the console
+func (o OutputBin) String() string {
+	level := "level unknown"
+	switch {
+	case o.PercentFull >= 0:
+		level = fmt.Sprintf("%d%% full (%d of %d free)", o.PercentFull, o.Remaining, o.Capacity)
+	case o.Remaining == -3:
+		level = "room for at least one sheet"
+	}
+	return fmt.Sprintf("%s: %s, %s, %s", o.Name, o.Condition, level, o.State)
+}
+
+// getOutputBins collects the prtOutputTable
+func (c *Client) getOutputBins(g *gosnmp.GoSNMP, status *PrinterStatus) {
+	// Rows are indexed by hrDeviceIndex.prtOutputIndex
+	indexes, rows, err := walkTable(g, mib.MustResolve("prtOutputEntry"))
+	if err != nil {
+		return // Skip output bins if the walk fails
+	}
+
+	status.OutputBins = []OutputBin{}
+	for _, index := range indexes {
+		row := rows[index]
+		binStatus, hasStatus := row[6].(int) // prtOutputStatus
+		name := row.text(7)                  // prtOutputName
+		if !hasStatus && name == "" {
+			continue
+		}
+
+		_, outputIndex, _ := strings.Cut(index, ".")
+		bin := OutputBin{
+			Name:          name,
+			Description:   row.text(12),  // prtOutputDescription
+			Type:          row.number(2), // prtOutputType
+			Status:        binStatus,
+			CapacityUnit:  row.number(3), // prtOutputCapacityUnit
+			Capacity:      row.number(4), // prtOutputMaxCapacity
+			Remaining:     -2,
+			StackingOrder: enumName("prtOutputStackingOrder", row.number(19), ""),
+		}
+		bin.Index, _ = strconv.Atoi(outputIndex)
+		if !hasStatus {
+			bin.Status = SubUnitUnknown
+		}
+		if remaining, ok := row[5].(int); ok { // prtOutputRemainingCapacity
+			bin.Remaining = remaining
+		}
+		if bin.Name == "" {
+			bin.Name = fmt.Sprintf("Output %d", bin.Index)
+		}
+		bin.Decode()
+		status.OutputBins = append(status.OutputBins, bin)
+	}
+}
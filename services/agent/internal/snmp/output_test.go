@@ -0,0 +1,29 @@
+package snmp
+
+import "testing"
+
+func TestOutputBinCondition(t *testing.T) {
+	tests := []struct {
+		name        string
+		bin         OutputBin
+		want        string
+		percentFull int
+	}{
+		{"unknown status", OutputBin{Status: SubUnitUnknown, Remaining: -2}, "unknown", -1},
+		{"idle with room", OutputBin{Status: 0, Capacity: 200, Remaining: 150}, "ok", 25},
+		{"full", OutputBin{Status: 0, Capacity: 200, Remaining: 0}, "full", 100},
+		{"broken", OutputBin{Status: 3, Capacity: 200, Remaining: 150}, "error", 25},
+		{"critical alert", OutputBin{Status: 16, Capacity: 200, Remaining: 10}, "error", 95},
+		{"off-line", OutputBin{Status: 32 | 4, Capacity: 200, Remaining: 10}, "offline", 95},
+		{"at least one sheet", OutputBin{Status: 6, Remaining: -3}, "ok", -1},
+	}
+	for _, test := range tests {
+		test.bin.Decode()
+		if test.bin.Condition != test.want {
+			t.Errorf("%s: condition = %q, want %q", test.name, test.bin.Condition, test.want)
+		}
+		if test.bin.PercentFull != test.percentFull {
+			t.Errorf("%s: percent full = %d, want %d", test.name, test.bin.PercentFull, test.percentFull)
+		}
+	}
+}
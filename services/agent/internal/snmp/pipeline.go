@@ -0,0 +1,386 @@
+package snmp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gosnmp/gosnmp"
+
+	"lynk/agent/internal/mib"
+)
+
+// Source is where the value of a status field was read from
+type Source struct {
+	Collector string `json:"collector"`     // Pipeline stage, e.g. printer-general, brother or ipp
+	Protocol  string `json:"protocol"`      // snmp, ipp or pjl
+	Ref       string `json:"ref,omitempty"` // OID, IPP attribute or PJL command
+}
+
+// String formats the source for the explanation, naming OIDs the MIB
+// registry knows, e.g. "system sysName.0 (snmp)"
+func (s Source) String() string {
+	ref := s.Ref
+	if name := mib.Name(ref); name != "" {
+		ref = name
+	}
+	if ref == "" {
+		return fmt.Sprintf("%s (%s)", s.Collector, s.Protocol)
+	}
+	return fmt.Sprintf("%s %s (%s)", s.Collector, ref, s.Protocol)
+}
+
+// Candidate is the value one source reported for a field
+type Candidate struct {
+	Source
+	Value string `json:"value"`
+}
+
+// Attribution records which source a field's value was kept from
+type Attribution struct {
+	Source
+	Value       string      `json:"value"`
+	Conflicting bool        `json:"conflicting"` // The sources reported different values
+	Candidates  []Candidate `json:"candidates"`  // Every reported value, the kept one first
+}
+
+// Result is the status a single collector or protocol produced
+type Result struct {
+	Collector string
+	Protocol  string
+	Ref       string // Source of the fields the collector didn't attribute itself
+	Status    *PrinterStatus
+}
+
+// Precedence lists, per field, the collectors whose values are preferred,
+// best first. Collectors not listed rank after the listed ones, in the
+// order of the results passed to Merge.
+type Precedence map[string][]string
+
+// DefaultPrecedence settles the fields several collectors report
+var DefaultPrecedence = Precedence{
+	// hrPrinterStatus is the only SNMP object with the printer's state;
+	// prtGeneralConfigChanges, which some models were once read as, is a
+	// counter of configuration changes
+	"status":        {"host-resources", "ipp", "pjl", "brother"},
+	"serial_number": {"brother", "device-id", "ipp", "pjl"},
+	"total_pages":   {"marker-counters", "brother", "page-counts", "ipp", "pjl"},
+	// hrPrinterDetectedErrorState is a bitmask, not a count
+	"error_count": {"alerts", "ipp", "pjl", "host-resources"},
+	"last_error":  {"alerts", "error-state", "ipp", "pjl"},
+}
+
+// collectorStep is one stage of the SNMP collector pipeline
+type collectorStep struct {
+	name    string
+	ref     string // Table or group the stage reads, for fields it doesn't attribute itself
+	collect func(c *Client, g *gosnmp.GoSNMP, status *PrinterStatus)
+}
+
+// pipeline is the SNMP collectors in the order they run. Each fills its own
+// status, which Merge combines; fields no precedence is defined for are taken
+// from the later stages, which read the more specific objects.
+var pipeline = []collectorStep{
+	{"device-id", "", (*Client).getDeviceID},
+	{"host-resources", mib.MustResolve("hrPrinterEntry"), (*Client).getStandardPrinterStatus},
+	{"host-storage", mib.MustResolve("hrStorage"), (*Client).getStorage},
+	{"host-devices", mib.MustResolve("hrDeviceEntry"), (*Client).getDevices},
+	{"marker-supplies", mib.MustResolve("prtMarkerSuppliesEntry"), (*Client).getTonerLevels},
+	{"page-counts", "", (*Client).getPageCounts},
+	{"error-state", "", (*Client).getErrorInfo},
+	{"brother", mib.MustResolve("brotherMaintenanceInfo"), (*Client).getBrotherMaintenanceInfo},
+	{"system", mib.MustResolve("system"), (*Client).getDeviceIdentity},
+	{"interfaces", mib.MustResolve("ifEntry"), (*Client).getNetworkInterfaces},
+	{"printer-general", mib.MustResolve("prtGeneralEntry"), (*Client).getDeviceStatus},
+	{"marker-counters", mib.MustResolve("prtMarkerEntry"), (*Client).getPageCounters},
+	{"alerts", mib.MustResolve("prtAlertEntry"), (*Client).getAlertsAndErrors},
+	{"input", mib.MustResolve("prtInputEntry"), (*Client).getPaperTrays},
+	{"output", mib.MustResolve("prtOutputEntry"), (*Client).getOutputBins},
+	{"console", mib.MustResolve("prtConsoleDisplayBuffer"), (*Client).getConsole},
+	{"covers", mib.MustResolve("prtCoverEntry"), (*Client).getCovers},
+	{"capabilities", mib.MustResolve("prtMediaPathEntry"), (*Client).getCapabilities},
+}
+
+// statusField is a PrinterStatus field that is merged from its sources
+type statusField struct {
+	index int
+	name  string // JSON name, also used in Precedence and Sources
+}
+
+// statusFields are the merged fields in declaration order. The poll's own
+// host, protocol and time, the agent's derived counters and the health rollup
+// aren't merged.
+var statusFields = func() []statusField {
+	skip := map[string]bool{"host": true, "protocol": true, "last_seen": true, "monotonic_pages": true, "health": true, "reported_zero": true}
+	var fields []statusField
+	t := reflect.TypeOf(PrinterStatus{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if !t.Field(i).IsExported() || name == "" || name == "-" || skip[name] {
+			continue
+		}
+		fields = append(fields, statusField{index: i, name: name})
+	}
+	return fields
+}()
+
+// placeholders are the values a status starts out with before anything is known
+var placeholders = map[string]interface{}{
+	"status":       PrinterUnknown,
+	"device_state": DeviceUnknown,
+}
+
+// From records that a field was read from ref, an OID, IPP attribute or PJL
+// command. A field attributed this way counts as reported even when its value
+// is zero.
+func (p *PrinterStatus) From(field, ref string) {
+	if p.refs == nil {
+		p.refs = make(map[string]string)
+	}
+	p.refs[field] = ref
+}
+
+// ZeroReported reports whether a numeric field that is zero was reported as
+// zero by a source, rather than not read at all
+func (p *PrinterStatus) ZeroReported(field string) bool {
+	for _, name := range p.ReportedZero {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
+
+// candidate is a Candidate together with its value and ranking
+type candidate struct {
+	Candidate
+	value       reflect.Value // Invalid for values only known from an earlier merge's attribution
+	empty       bool
+	rank        int
+	conflicting bool // An earlier merge already found the sources disagreeing
+}
+
+// Merge combines the statuses of several sources field by field. A field is
+// taken from the best ranked source that reported it, where empty strings and
+// lists only win when no source has more. The host, protocol and poll time
+// are those of the first result, and Sources records where every field came
+// from. Results that were merged before keep their attribution.
+// ReportedZero lists the numeric fields whose kept value is zero, so a
+// reported zero can still be told from a missing one once Sources is gone.
+func Merge(precedence Precedence, results ...Result) *PrinterStatus {
+	merged := &PrinterStatus{Sources: make(map[string]*Attribution)}
+	if len(results) > 0 {
+		first := results[0].Status
+		merged.Host, merged.Protocol, merged.LastSeen = first.Host, first.Protocol, first.LastSeen
+	}
+
+	out := reflect.ValueOf(merged).Elem()
+	for _, field := range statusFields {
+		var candidates []candidate
+		for i, result := range results {
+			candidates = append(candidates, reported(result, i, field, precedence[field.name])...)
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].empty != candidates[j].empty {
+				return !candidates[i].empty
+			}
+			return candidates[i].rank < candidates[j].rank
+		})
+
+		// The best ranked value wins, the others are kept for the explanation
+		kept := -1
+		for i, c := range candidates {
+			if c.value.IsValid() {
+				kept = i
+				break
+			}
+		}
+		if kept < 0 {
+			continue
+		}
+		out.Field(field.index).Set(candidates[kept].value)
+		ordered := append([]candidate{candidates[kept]}, candidates[:kept]...)
+		candidates = append(ordered, candidates[kept+1:]...)
+
+		attribution := &Attribution{Source: candidates[0].Source, Value: candidates[0].Value}
+		for _, c := range candidates {
+			if c.empty && !candidates[0].empty {
+				continue // Reporting nothing doesn't contradict a value
+			}
+			attribution.Candidates = append(attribution.Candidates, c.Candidate)
+			switch {
+			case c.conflicting:
+				attribution.Conflicting = true
+			case c.value.IsValid():
+				attribution.Conflicting = attribution.Conflicting || !reflect.DeepEqual(c.value.Interface(), candidates[0].value.Interface())
+			default:
+				attribution.Conflicting = attribution.Conflicting || c.Value != attribution.Value
+			}
+		}
+		merged.Sources[field.name] = attribution
+		if isNumber(candidates[0].value) && candidates[0].value.IsZero() {
+			merged.ReportedZero = append(merged.ReportedZero, field.name)
+		}
+	}
+
+	if merged.Status == "" {
+		merged.Status = PrinterUnknown
+	}
+	if merged.DeviceState == "" {
+		merged.DeviceState = DeviceUnknown
+	}
+	merged.Health = merged.Rollup()
+	return merged
+}
+
+// reported returns the values a result has for a field, ranked by precedence
+func reported(result Result, index int, field statusField, precedence []string) []candidate {
+	rank := func(collector string) int {
+		for i, name := range precedence {
+			if name == collector {
+				return i
+			}
+		}
+		return len(precedence) + index
+	}
+	value := reflect.ValueOf(result.Status).Elem().Field(field.index)
+
+	// A merged status knows where its fields came from
+	if result.Status.Sources != nil {
+		attribution := result.Status.Sources[field.name]
+		if attribution == nil {
+			return nil
+		}
+		var candidates []candidate
+		for i, c := range attribution.Candidates {
+			kept := candidate{Candidate: c, rank: rank(c.Collector)}
+			if i == 0 {
+				kept.value, kept.empty = value, isEmpty(field.name, value)
+				kept.conflicting = attribution.Conflicting
+			}
+			candidates = append(candidates, kept)
+		}
+		return candidates
+	}
+
+	ref, attributed := result.Status.refs[field.name]
+	if !attributed {
+		if value.IsZero() || isPlaceholder(field.name, value) {
+			return nil
+		}
+		ref = result.Ref
+	}
+	return []candidate{{
+		Candidate: Candidate{
+			Source: Source{Collector: result.Collector, Protocol: result.Protocol, Ref: ref},
+			Value:  describe(value),
+		},
+		value: value,
+		empty: isEmpty(field.name, value),
+		rank:  rank(result.Collector),
+	}}
+}
+
+// isEmpty reports whether a value carries no information: an empty string,
+// list or struct, or the field's placeholder
+func isEmpty(field string, value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice:
+		if value.Len() == 0 {
+			return true
+		}
+	case reflect.Struct:
+		if value.IsZero() {
+			return true
+		}
+	}
+	return isPlaceholder(field, value)
+}
+
+// isNumber reports whether a value is an integer or float
+func isNumber(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isPlaceholder reports whether a value is the one the field starts out with
+func isPlaceholder(field string, value reflect.Value) bool {
+	placeholder, ok := placeholders[field]
+	return ok && reflect.DeepEqual(value.Interface(), placeholder)
+}
+
+// describe formats a field value for the explanation
+func describe(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return strconv.Quote(value.String())
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.String {
+			return fmt.Sprintf("%q", value.Interface())
+		}
+		switch value.Len() {
+		case 0:
+			return "none"
+		case 1:
+			return "1 entry"
+		}
+		return fmt.Sprintf("%d entries", value.Len())
+	case reflect.Struct:
+		if data, err := json.Marshal(value.Interface()); err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// Explain lists where every field of the status was read from. Fields the
+// sources disagreed on are followed by the values that were passed over.
+func (p *PrinterStatus) Explain() string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Printer: %s\n", p.Host))
+	if len(p.Sources) == 0 {
+		output.WriteString("   No source attribution recorded\n")
+		return output.String()
+	}
+
+	conflicts := 0
+	w := tabwriter.NewWriter(&output, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "   FIELD\tVALUE\tSOURCE\n")
+	for _, field := range statusFields {
+		attribution := p.Sources[field.name]
+		if attribution == nil {
+			continue
+		}
+		fmt.Fprintf(w, "   %s\t%s\t%s\n", field.name, shorten(attribution.Value), attribution.Source)
+		if !attribution.Conflicting {
+			continue
+		}
+		conflicts++
+		for _, c := range attribution.Candidates[1:] {
+			fmt.Fprintf(w, "     also reported\t%s\t%s\n", shorten(c.Value), c.Source)
+		}
+	}
+	w.Flush()
+
+	output.WriteString(fmt.Sprintf("   %d fields, %d with conflicting values\n", len(p.Sources), conflicts))
+	return output.String()
+}
+
+// shorten truncates long values so the explanation stays readable
+func shorten(value string) string {
+	const max = 48
+	if len(value) <= max {
+		return value
+	}
+	return value[:max-3] + "..."
+}
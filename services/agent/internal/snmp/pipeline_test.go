@@ -0,0 +1,160 @@
+package snmp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	polled := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	results := []Result{
+		{Collector: "system", Protocol: "snmp", Ref: "1.3.6.1.2.1.1", Status: &PrinterStatus{
+			Host: "10.0.0.5", Protocol: "snmp", LastSeen: polled, Model: "LaserJet 4250",
+		}},
+		{Collector: "device-id", Protocol: "snmp", Status: &PrinterStatus{SerialNumber: "printer-5"}},
+		{Collector: "brother", Protocol: "snmp", Status: &PrinterStatus{
+			SerialNumber: "E12345", TotalPages: 1200,
+		}},
+		{Collector: "marker-counters", Protocol: "snmp", Status: &PrinterStatus{
+			TotalPages: 1250, Status: PrinterUnknown,
+		}},
+		{Collector: "alerts", Protocol: "snmp", Status: &PrinterStatus{ActiveAlerts: []string{}}},
+		{Collector: "error-state", Protocol: "snmp", Status: &PrinterStatus{ActiveAlerts: []string{"Paper jam"}}},
+		{Collector: "host-resources", Protocol: "snmp", Status: &PrinterStatus{Status: PrinterIdle}},
+	}
+	// The alert table was read and is empty: zero is what it reported
+	results[4].Status.From("error_count", "1.3.6.1.2.1.43.18.1.1")
+
+	merged := Merge(DefaultPrecedence, results...)
+
+	if merged.Host != "10.0.0.5" || merged.Protocol != "snmp" || !merged.LastSeen.Equal(polled) {
+		t.Errorf("host, protocol, time = %s, %s, %s", merged.Host, merged.Protocol, merged.LastSeen)
+	}
+	if merged.SerialNumber != "E12345" || merged.TotalPages != 1250 || merged.Status != PrinterIdle || merged.Model != "LaserJet 4250" {
+		t.Errorf("serial, pages, status, model = %q, %d, %s, %q", merged.SerialNumber, merged.TotalPages, merged.Status, merged.Model)
+	}
+	if len(merged.ActiveAlerts) != 1 {
+		t.Errorf("an empty list won over a reported one: %q", merged.ActiveAlerts)
+	}
+
+	serial := merged.Sources["serial_number"]
+	if serial == nil || serial.Collector != "brother" || !serial.Conflicting || len(serial.Candidates) != 2 ||
+		serial.Candidates[1].Collector != "device-id" || serial.Candidates[1].Value != `"printer-5"` {
+		t.Errorf("serial_number attribution = %+v", serial)
+	}
+	if model := merged.Sources["model"]; model == nil || model.Conflicting || model.Ref != "1.3.6.1.2.1.1" {
+		t.Errorf("model attribution = %+v", model)
+	}
+	if status := merged.Sources["status"]; status == nil || status.Collector != "host-resources" || status.Conflicting {
+		t.Errorf("the unknown placeholder counted as a status: %+v", status)
+	}
+	if alerts := merged.Sources["active_alerts"]; alerts == nil || alerts.Conflicting || len(alerts.Candidates) != 1 {
+		t.Errorf("an empty list contradicted a reported one: %+v", alerts)
+	}
+	errors := merged.Sources["error_count"]
+	if errors == nil || errors.Ref != "1.3.6.1.2.1.43.18.1.1" || errors.Value != "0" {
+		t.Errorf("error_count attribution = %+v", errors)
+	}
+	if strings.Join(merged.ReportedZero, ",") != "error_count" {
+		t.Errorf("reported zero = %q", merged.ReportedZero)
+	}
+	if _, ok := merged.Sources["page_counter_unit"]; ok {
+		t.Error("a field nobody reported was attributed")
+	}
+	if merged.Health != merged.Rollup() {
+		t.Errorf("health = %s, want %s", merged.Health, merged.Rollup())
+	}
+}
+
+func TestMergeUnlisted(t *testing.T) {
+	// Collectors without precedence rank in the order of the results
+	merged := Merge(Precedence{"model": {"ipp"}},
+		Result{Collector: "first", Protocol: "snmp", Status: &PrinterStatus{Model: "A", Location: "Lobby"}},
+		Result{Collector: "second", Protocol: "snmp", Status: &PrinterStatus{Model: "B", Location: "Office"}},
+		Result{Collector: "ipp", Protocol: "ipp", Status: &PrinterStatus{Model: "C"}},
+	)
+	if merged.Model != "C" || merged.Location != "Lobby" {
+		t.Errorf("model, location = %q, %q", merged.Model, merged.Location)
+	}
+
+	empty := Merge(DefaultPrecedence)
+	if empty.Status != PrinterUnknown || empty.DeviceState != DeviceUnknown || len(empty.Sources) != 0 {
+		t.Errorf("merge of nothing = %+v", empty)
+	}
+}
+
+func TestMergeMerged(t *testing.T) {
+	snmp := Merge(DefaultPrecedence,
+		Result{Collector: "pjl", Protocol: "pjl", Status: &PrinterStatus{Host: "10.0.0.5", SerialNumber: "printer-5"}},
+		Result{Collector: "marker-counters", Protocol: "snmp", Status: &PrinterStatus{TotalPages: 1250}},
+		Result{Collector: "page-counts", Protocol: "snmp", Status: &PrinterStatus{TotalPages: 1240}},
+	)
+	ipp := &PrinterStatus{Host: "10.0.0.5", Protocol: "ipp", SerialNumber: "E12345", TotalPages: 1250}
+
+	merged := Merge(DefaultPrecedence,
+		Result{Collector: "snmp", Protocol: "snmp", Status: snmp},
+		Result{Collector: "ipp", Protocol: "ipp", Status: ipp},
+	)
+	if merged.SerialNumber != "E12345" || merged.TotalPages != 1250 {
+		t.Errorf("serial, pages = %q, %d", merged.SerialNumber, merged.TotalPages)
+	}
+
+	// The earlier merge's sources are ranked, not the merged status as a whole
+	pages := merged.Sources["total_pages"]
+	if pages.Collector != "marker-counters" || !pages.Conflicting || len(pages.Candidates) != 3 {
+		t.Errorf("total_pages attribution = %+v", pages)
+	}
+	serial := merged.Sources["serial_number"]
+	if serial.Collector != "ipp" || serial.Candidates[1].Collector != "pjl" {
+		t.Errorf("serial_number attribution = %+v", serial)
+	}
+}
+
+func TestSourcesNotSerialized(t *testing.T) {
+	merged := Merge(DefaultPrecedence, Result{Collector: "system", Protocol: "snmp", Status: &PrinterStatus{TotalPages: 0, Model: "X"}})
+	data, err := json.Marshal(merged)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Contains(string(data), "candidates") {
+		t.Errorf("sources serialized: %s", data)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	merged := Merge(DefaultPrecedence,
+		Result{Collector: "system", Protocol: "snmp", Ref: "1.3.6.1.2.1.1", Status: &PrinterStatus{Host: "10.0.0.5", Model: "LaserJet"}},
+		Result{Collector: "pjl", Protocol: "pjl", Status: &PrinterStatus{SerialNumber: "printer-5"}},
+		Result{Collector: "brother", Protocol: "snmp", Status: &PrinterStatus{SerialNumber: "E12345"}},
+	)
+	merged.Sources["model"].Ref = "1.3.6.1.2.1.1.5.0"
+
+	explanation := merged.Explain()
+	for _, want := range []string{
+		"Printer: 10.0.0.5",
+		"brother (snmp)",
+		`also reported  "printer-5"`,
+		"system sysName.0 (snmp)",
+		"2 fields, 1 with conflicting values",
+	} {
+		if !strings.Contains(explanation, want) {
+			t.Errorf("explanation lacks %q:\n%s", want, explanation)
+		}
+	}
+
+	none := (&PrinterStatus{Host: "10.0.0.6"}).Explain()
+	if !strings.Contains(none, "No source attribution recorded") {
+		t.Errorf("explanation without sources = %q", none)
+	}
+}
+
+func TestShorten(t *testing.T) {
+	if got := shorten("short"); got != "short" {
+		t.Errorf("shorten = %q", got)
+	}
+	if got := shorten(strings.Repeat("x", 60)); len(got) != 48 || !strings.HasSuffix(got, "...") {
+		t.Errorf("shorten = %q", got)
+	}
+}
@@ -0,0 +1,211 @@
+package snmp
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// PrinterState is the normalized state of the print engine, whichever of
+// hrPrinterStatus, IPP printer-state or PJL INFO STATUS it was read from
+type PrinterState string
+
+// Printer states
+const (
+	PrinterOther    PrinterState = "other"
+	PrinterUnknown  PrinterState = "unknown"
+	PrinterIdle     PrinterState = "idle"
+	PrinterPrinting PrinterState = "printing"
+	PrinterWarmup   PrinterState = "warmup"
+	PrinterStopped  PrinterState = "stopped" // Needs attention before it prints again
+)
+
+// hrPrinterStates maps hrPrinterStatus values (RFC 2790)
+var hrPrinterStates = map[int]PrinterState{
+	1: PrinterOther,
+	2: PrinterUnknown,
+	3: PrinterIdle,
+	4: PrinterPrinting,
+	5: PrinterWarmup,
+}
+
+// HRPrinterState maps an hrPrinterStatus value. Values outside the MIB are other.
+func HRPrinterState(value int) PrinterState {
+	if state, ok := hrPrinterStates[value]; ok {
+		return state
+	}
+	return PrinterOther
+}
+
+// legacyPrinterStates maps the free-form status strings of older history.
+// The Brother collector labelled hrPrinterStatus with the hrDeviceStatus names.
+var legacyPrinterStates = map[string]PrinterState{
+	"Unknown": PrinterOther,
+	"Running": PrinterUnknown,
+	"Warning": PrinterIdle,
+	"Testing": PrinterPrinting,
+	"Down":    PrinterWarmup,
+}
+
+// UnmarshalJSON decodes a printer state, normalizing the status strings
+// history saved before the states were typed holds, e.g. "Idle" or "Status 7"
+func (s *PrinterState) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	if state, ok := legacyPrinterStates[text]; ok {
+		*s = state
+		return nil
+	}
+	switch state := PrinterState(strings.ToLower(text)); state {
+	case PrinterOther, PrinterUnknown, PrinterIdle, PrinterPrinting, PrinterWarmup, PrinterStopped:
+		*s = state
+	case "":
+		*s = PrinterUnknown
+	default:
+		*s = PrinterOther
+	}
+	return nil
+}
+
+// DeviceState is the state of the printer as a whole, hrDeviceStatus
+type DeviceState string
+
+// Device states
+const (
+	DeviceUnknown DeviceState = "unknown"
+	DeviceRunning DeviceState = "running"
+	DeviceWarning DeviceState = "warning" // Running, but an error condition was detected
+	DeviceTesting DeviceState = "testing"
+	DeviceDown    DeviceState = "down"
+)
+
+// hrDeviceStates maps hrDeviceStatus values (RFC 2790)
+var hrDeviceStates = map[int]DeviceState{
+	1: DeviceUnknown,
+	2: DeviceRunning,
+	3: DeviceWarning,
+	4: DeviceTesting,
+	5: DeviceDown,
+}
+
+// HRDeviceState maps an hrDeviceStatus value. Values outside the MIB are unknown.
+func HRDeviceState(value int) DeviceState {
+	if state, ok := hrDeviceStates[value]; ok {
+		return state
+	}
+	return DeviceUnknown
+}
+
+// Health is the overall condition of a printer, rolled up from all its signals
+type Health string
+
+// Health levels
+const (
+	HealthUnknown  Health = "unknown"
+	HealthOK       Health = "ok"
+	HealthWarning  Health = "warning"
+	HealthCritical Health = "critical"
+	HealthOffline  Health = "offline" // Not reachable, only known to whoever tracks the polls
+)
+
+// Rollup rolls the printer's states, alerts, supplies, trays, bins, covers
+// and disks up into ok, warning or critical. It is unknown when the printer
+// reported neither a state nor anything that needs attention.
+func (p *PrinterStatus) Rollup() Health {
+	switch p.PaperStatus {
+	case "paper_out", "paper_jam":
+		return HealthCritical
+	}
+	if p.DeviceState == DeviceDown || p.Status == PrinterStopped {
+		return HealthCritical
+	}
+	for _, tray := range p.PaperTrays {
+		if tray.Condition == "error" {
+			return HealthCritical
+		}
+	}
+	if p.outOfPaper() {
+		return HealthCritical // Nothing left to print on
+	}
+	for _, bin := range p.OutputBins {
+		if bin.Condition == "full" || bin.Condition == "error" {
+			return HealthCritical // The printer stops until the bin is emptied
+		}
+	}
+	for _, cover := range p.Covers {
+		if cover.Open {
+			return HealthCritical
+		}
+	}
+	for _, storage := range p.Storage {
+		if storage.Condition == "full" {
+			return HealthCritical // Scanning to the disk and stored jobs fail
+		}
+	}
+	for _, device := range p.Devices {
+		if device.Failing() && device.State == DeviceDown {
+			return HealthCritical
+		}
+	}
+
+	if len(p.ActiveAlerts) > 0 || p.DeviceState == DeviceWarning || p.PaperStatus == "toner_low" {
+		return HealthWarning
+	}
+	for _, supply := range p.Supplies {
+		if supply.Percent >= 0 && supply.Percent <= 10 {
+			return HealthWarning
+		}
+	}
+	for _, tray := range p.PaperTrays {
+		if tray.Condition == "empty" || tray.Condition == "warning" || tray.Condition == "offline" {
+			return HealthWarning // Jobs are fed from the other trays meanwhile
+		}
+	}
+	for _, bin := range p.OutputBins {
+		if bin.Condition == "warning" || bin.Condition == "offline" {
+			return HealthWarning
+		}
+	}
+	for _, storage := range p.Storage {
+		if storage.Condition == "warning" {
+			return HealthWarning
+		}
+	}
+	for _, device := range p.Devices {
+		if device.Failing() {
+			return HealthWarning
+		}
+	}
+
+	if (p.Status == "" || p.Status == PrinterUnknown) && (p.DeviceState == "" || p.DeviceState == DeviceUnknown) {
+		return HealthUnknown
+	}
+	return HealthOK
+}
+
+// inputSheetFeedManual is the PrtInputTypeTC of a manual feed slot
+const inputSheetFeedManual = 5
+
+// outOfPaper reports whether every tray feeding the default media path is
+// empty. The Printer-MIB doesn't tell which inputs feed which path, but
+// printers switch between their automatic trays on their own, so every tray
+// except manual feed slots counts. Without such trays, all of them do.
+func (p *PrinterStatus) outOfPaper() bool {
+	var feeding []PaperTray
+	for _, tray := range p.PaperTrays {
+		if tray.Type != inputSheetFeedManual && tray.Condition != "offline" {
+			feeding = append(feeding, tray)
+		}
+	}
+	if len(feeding) == 0 {
+		feeding = p.PaperTrays
+	}
+	for _, tray := range feeding {
+		if tray.Condition != "empty" {
+			return false
+		}
+	}
+	return len(feeding) > 0
+}
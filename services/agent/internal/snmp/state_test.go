@@ -0,0 +1,61 @@
+package snmp
+
+import "testing"
+
+func TestRollup(t *testing.T) {
+	tray := func(index, trayType, level int) PaperTray {
+		p := PaperTray{Index: index, Type: trayType, Capacity: 250, Level: level}
+		p.Decode()
+		return p
+	}
+	idle := func(status PrinterStatus) PrinterStatus {
+		status.Status, status.DeviceState = PrinterIdle, DeviceRunning
+		return status
+	}
+
+	tests := []struct {
+		name   string
+		status PrinterStatus
+		want   Health
+	}{
+		{"nothing reported", PrinterStatus{}, HealthUnknown},
+		{"idle", idle(PrinterStatus{}), HealthOK},
+		{"stopped", PrinterStatus{Status: PrinterStopped}, HealthCritical},
+		{"device down", PrinterStatus{DeviceState: DeviceDown}, HealthCritical},
+		{"device warning", PrinterStatus{Status: PrinterIdle, DeviceState: DeviceWarning}, HealthWarning},
+		{"paper jam", idle(PrinterStatus{PaperStatus: "paper_jam"}), HealthCritical},
+		{"toner low", idle(PrinterStatus{PaperStatus: "toner_low"}), HealthWarning},
+		{"active alert", idle(PrinterStatus{ActiveAlerts: []string{"Toner low"}}), HealthWarning},
+		{"supply at 10%", idle(PrinterStatus{Supplies: []Supply{{Percent: 10}}}), HealthWarning},
+		{"supply unknown", idle(PrinterStatus{Supplies: []Supply{{Percent: -1}}}), HealthOK},
+
+		{"one of two trays empty", idle(PrinterStatus{PaperTrays: []PaperTray{tray(1, 3, 0), tray(2, 3, 100)}}), HealthWarning},
+		{"all trays empty", idle(PrinterStatus{PaperTrays: []PaperTray{tray(1, 3, 0), tray(2, 3, 0)}}), HealthCritical},
+		{"only the manual feed has paper", idle(PrinterStatus{PaperTrays: []PaperTray{tray(1, 5, 50), tray(2, 3, 0)}}), HealthCritical},
+		{"manual feed empty", idle(PrinterStatus{PaperTrays: []PaperTray{tray(1, 5, 0), tray(2, 3, 100)}}), HealthWarning},
+		{"only a manual feed, empty", idle(PrinterStatus{PaperTrays: []PaperTray{tray(1, 5, 0)}}), HealthCritical},
+		{"tray level unknown", idle(PrinterStatus{PaperTrays: []PaperTray{tray(1, 3, -2)}}), HealthOK},
+		{"tray broken", idle(PrinterStatus{PaperTrays: []PaperTray{{Status: 3, Level: 100}}}), HealthCritical},
+
+		{"bin full", idle(PrinterStatus{OutputBins: []OutputBin{{Capacity: 100, Remaining: 0}}}), HealthCritical},
+		{"cover open", idle(PrinterStatus{Covers: []Cover{{Open: true}}}), HealthCritical},
+		{"disk full", idle(PrinterStatus{Storage: []Storage{{Type: "fixed_disk", Percent: 97, Condition: "full"}}}), HealthCritical},
+		{"disk filling", idle(PrinterStatus{Storage: []Storage{{Type: "fixed_disk", Percent: 90, Condition: "warning"}}}), HealthWarning},
+		{"disk down", idle(PrinterStatus{Devices: []Device{{Type: "disk_storage", State: DeviceDown}}}), HealthCritical},
+		{"disk warning", idle(PrinterStatus{Devices: []Device{{Type: "disk_storage", State: DeviceWarning}}}), HealthWarning},
+		{"network down", idle(PrinterStatus{Devices: []Device{{Type: "network", State: DeviceDown}}}), HealthOK},
+	}
+	for _, test := range tests {
+		for i := range test.status.OutputBins {
+			test.status.OutputBins[i].Decode()
+		}
+		for i := range test.status.PaperTrays {
+			if test.status.PaperTrays[i].Condition == "" {
+				test.status.PaperTrays[i].Decode()
+			}
+		}
+		if got := test.status.Rollup(); got != test.want {
+			t.Errorf("%s: %s, want %s", test.name, got, test.want)
+		}
+	}
+}
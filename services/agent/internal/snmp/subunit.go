@@ -0,0 +1,70 @@
+package snmp
+
+import "strings"
+
+// SubUnitStatus is a decoded PrtSubUnitStatusTC, the status bitfield shared by
+// the input, output and other sub-unit tables of the Printer-MIB (RFC 3805)
+type SubUnitStatus struct {
+	Availability     string `json:"availability"` // idle, standby, active, busy, on_request, broken or unknown
+	NonCriticalAlert bool   `json:"non_critical_alert,omitempty"`
+	CriticalAlert    bool   `json:"critical_alert,omitempty"`
+	OffLine          bool   `json:"off_line,omitempty"`
+	Transitioning    bool   `json:"transitioning,omitempty"` // Moving towards its intended state, e.g. a tray lifting
+}
+
+// availabilities are the values of the low three bits of PrtSubUnitStatusTC.
+// Even values are available, odd ones unavailable; 7 is undefined.
+var availabilities = map[int]string{
+	0: "idle",
+	1: "on_request", // Unavailable until requested, e.g. a manual feed slot
+	2: "standby",
+	3: "broken",
+	4: "active",
+	5: "unknown",
+	6: "busy",
+}
+
+// SubUnitUnknown is the PrtSubUnitStatusTC value of a sub-unit whose status
+// is unknown, without alerts
+const SubUnitUnknown = 5
+
+// DecodeSubUnitStatus decodes a PrtSubUnitStatusTC value
+func DecodeSubUnitStatus(value int) SubUnitStatus {
+	availability, ok := availabilities[value&7]
+	if !ok {
+		availability = "unknown"
+	}
+	return SubUnitStatus{
+		Availability:     availability,
+		NonCriticalAlert: value&8 != 0,
+		CriticalAlert:    value&16 != 0,
+		OffLine:          value&32 != 0,
+		Transitioning:    value&64 != 0,
+	}
+}
+
+// Available reports whether the sub-unit can be used
+func (s SubUnitStatus) Available() bool {
+	switch s.Availability {
+	case "idle", "standby", "active", "busy":
+		return !s.OffLine
+	}
+	return false
+}
+
+// String describes the status, e.g. "idle, critical alert"
+func (s SubUnitStatus) String() string {
+	parts := []string{strings.ReplaceAll(s.Availability, "_", " ")}
+	if s.CriticalAlert {
+		parts = append(parts, "critical alert")
+	} else if s.NonCriticalAlert {
+		parts = append(parts, "non-critical alert")
+	}
+	if s.OffLine {
+		parts = append(parts, "off-line")
+	}
+	if s.Transitioning {
+		parts = append(parts, "transitioning")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,54 @@
+package snmp
+
+import "testing"
+
+func TestDecodeSubUnitStatus(t *testing.T) {
+	tests := []struct {
+		value     int
+		want      SubUnitStatus
+		available bool
+	}{
+		{0, SubUnitStatus{Availability: "idle"}, true},
+		{1, SubUnitStatus{Availability: "on_request"}, false},
+		{2, SubUnitStatus{Availability: "standby"}, true},
+		{3, SubUnitStatus{Availability: "broken"}, false},
+		{4, SubUnitStatus{Availability: "active"}, true},
+		{5, SubUnitStatus{Availability: "unknown"}, false},
+		{6, SubUnitStatus{Availability: "busy"}, true},
+		{7, SubUnitStatus{Availability: "unknown"}, false},
+		{8, SubUnitStatus{Availability: "idle", NonCriticalAlert: true}, true},
+		{16 | 3, SubUnitStatus{Availability: "broken", CriticalAlert: true}, false},
+		{32 | 4, SubUnitStatus{Availability: "active", OffLine: true}, false},
+		{64 | 2, SubUnitStatus{Availability: "standby", Transitioning: true}, true},
+	}
+	for _, test := range tests {
+		got := DecodeSubUnitStatus(test.value)
+		if got != test.want {
+			t.Errorf("DecodeSubUnitStatus(%d) = %+v, want %+v", test.value, got, test.want)
+		}
+		if got.Available() != test.available {
+			t.Errorf("DecodeSubUnitStatus(%d).Available() = %t, want %t", test.value, got.Available(), test.available)
+		}
+	}
+}
+
+func TestPaperTrayCondition(t *testing.T) {
+	tests := []struct {
+		name string
+		tray PaperTray
+		want string
+	}{
+		{"idle with paper", PaperTray{Status: 0, Level: 100, Capacity: 250}, "ok"},
+		{"unknown status", PaperTray{Status: SubUnitUnknown, Level: -2}, "unknown"},
+		{"broken", PaperTray{Status: 3, Level: 100, Capacity: 250}, "error"},
+		{"empty", PaperTray{Status: 0, Level: 0, Capacity: 250}, "empty"},
+		{"non-critical alert", PaperTray{Status: 8, Level: 10, Capacity: 250}, "warning"},
+		{"off-line", PaperTray{Status: 32, Level: 10, Capacity: 250}, "offline"},
+	}
+	for _, test := range tests {
+		test.tray.Decode()
+		if test.tray.Condition != test.want {
+			t.Errorf("%s: condition = %q, want %q", test.name, test.tray.Condition, test.want)
+		}
+	}
+}
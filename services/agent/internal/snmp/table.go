@@ -0,0 +1,109 @@
+package snmp
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gosnmp/gosnmp"
+
+	"lynk/agent/internal/mib"
+)
+
+// tableColumn splits the OID of a table cell into its column and row index,
+// e.g. ".1.3.6.1.2.1.2.2.1.6.3" under "1.3.6.1.2.1.2.2.1" into 6 and "3"
+func tableColumn(oid, entry string) (int, string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(oid, "."), entry+".")
+	if !ok {
+		return 0, "", false
+	}
+	column, index, ok := strings.Cut(rest, ".")
+	if !ok {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(column)
+	if err != nil {
+		return 0, "", false
+	}
+	return n, index, true
+}
+
+// enumName decodes a value of an enumerated built-in object to the snake_case
+// name the status reports, with prefix cut from the MIB label: prtCoverStatus
+// 5 (interlockOpen) is "interlock_open", 3 (coverOpen) with prefix "cover" is
+// "open". It is "unknown" for values the MIB doesn't define.
+func enumName(object string, value int, prefix string) string {
+	label := strings.TrimPrefix(mib.Decode(mib.MustResolve(object), value), prefix)
+	if label == "" {
+		return "unknown"
+	}
+
+	var name strings.Builder
+	for i, r := range label {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				name.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		name.WriteRune(r)
+	}
+	return name.String()
+}
+
+// tableRow is the cells of one table row by column number, string or int.
+// OID cells are strings without the leading dot.
+type tableRow map[int]interface{}
+
+// text returns a string cell, "" if it is missing
+func (r tableRow) text(column int) string {
+	v, _ := r[column].(string)
+	return v
+}
+
+// number returns an integer cell, 0 if it is missing
+func (r tableRow) number(column int) int {
+	v, _ := r[column].(int)
+	return v
+}
+
+// walkTable walks a table entry OID and returns its rows by index, sorted
+// numerically. Strings have trailing NULs removed, counters and gauges are
+// read as integers.
+func walkTable(g *gosnmp.GoSNMP, entry string) ([]string, map[string]tableRow, error) {
+	rows := make(map[string]tableRow)
+	err := g.Walk(entry, func(variable gosnmp.SnmpPDU) error {
+		column, index, ok := tableColumn(variable.Name, entry)
+		if !ok {
+			return nil
+		}
+		if rows[index] == nil {
+			rows[index] = make(tableRow)
+		}
+
+		switch variable.Type {
+		case gosnmp.OctetString:
+			rows[index][column] = strings.TrimRight(string(variable.Value.([]byte)), "\x00")
+		case gosnmp.Integer:
+			rows[index][column] = variable.Value.(int)
+		case gosnmp.Counter32, gosnmp.Gauge32:
+			rows[index][column] = int(variable.Value.(uint))
+		case gosnmp.ObjectIdentifier:
+			rows[index][column] = strings.TrimPrefix(variable.Value.(string), ".")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indexes := make([]string, 0, len(rows))
+	for index := range rows {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexLess(indexes[i], indexes[j])
+	})
+	return indexes, rows, nil
+}
@@ -0,0 +1,49 @@
+package snmp
+
+import "testing"
+
+func TestEnumName(t *testing.T) {
+	tests := []struct {
+		object string
+		value  int
+		prefix string
+		want   string
+	}{
+		{"prtCoverStatus", 3, "cover", "open"},
+		{"prtCoverStatus", 5, "cover", "interlock_open"},
+		{"prtCoverStatus", 1, "cover", "other"},
+		{"prtCoverStatus", 2, "cover", "unknown"}, // Not defined by the MIB
+		{"prtConsoleColor", 10, "", "orange"},
+		{"prtConsoleColor", 11, "", "unknown"},
+		{"prtOutputStackingOrder", 3, "", "first_to_last"},
+		{"prtOutputStackingOrder", 0, "", "unknown"},
+		{"prtMediaPathType", 4, "", "short_edge_binding_duplex"},
+	}
+	for _, test := range tests {
+		if got := enumName(test.object, test.value, test.prefix); got != test.want {
+			t.Errorf("enumName(%s, %d) = %q, want %q", test.object, test.value, got, test.want)
+		}
+	}
+}
+
+func TestLanguageName(t *testing.T) {
+	tests := []struct {
+		family int
+		want   string
+	}{
+		{3, "PCL"},
+		{6, "PostScript"},
+		{42, "PostScript"},
+		{47, "PCL XL"},
+		{54, "PDF"},
+		{39, "LIPS"},
+		{65, "C4"},
+		{2, "Language 2"},
+		{99, "Language 99"},
+	}
+	for _, test := range tests {
+		if got := languageName(test.family); got != test.want {
+			t.Errorf("languageName(%d) = %q, want %q", test.family, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"lynk/agent/internal/snmp"
+)
+
+// DefaultMaxSamples keeps 35 days of history at a 5 minute polling interval,
+// enough for monthly usage reports
+const DefaultMaxSamples = 10080
+
+// Store keeps the poll history of every printer in memory
+type Store struct {
+	maxSamples int
+	mu         sync.RWMutex
+	history    map[string][]*snmp.PrinterStatus
+}
+
+// New creates a new store that keeps at most maxSamples snapshots per printer
+func New(maxSamples int) *Store {
+	if maxSamples <= 0 {
+		maxSamples = DefaultMaxSamples
+	}
+	return &Store{
+		maxSamples: maxSamples,
+		history:    make(map[string][]*snmp.PrinterStatus),
+	}
+}
+
+// Add records a new snapshot for the snapshot's host
+func (s *Store) Add(status *snmp.PrinterStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	samples := append(s.history[status.Host], status)
+	if len(samples) > s.maxSamples {
+		// Drop the oldest samples once the history is full
+		samples = append([]*snmp.PrinterStatus(nil), samples[len(samples)-s.maxSamples:]...)
+	}
+	s.history[status.Host] = samples
+}
+
+// Latest returns the most recent snapshot of a printer
+func (s *Store) Latest(host string) (*snmp.PrinterStatus, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	samples := s.history[host]
+	if len(samples) == 0 {
+		return nil, false
+	}
+	return samples[len(samples)-1], true
+}
+
+// LatestWhere returns the most recent snapshot of a printer that match accepts
+func (s *Store) LatestWhere(host string, match func(*snmp.PrinterStatus) bool) (*snmp.PrinterStatus, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	samples := s.history[host]
+	for i := len(samples) - 1; i >= 0; i-- {
+		if match(samples[i]) {
+			return samples[i], true
+		}
+	}
+	return nil, false
+}
+
+// LatestAll returns the most recent snapshot of every printer, sorted by host
+func (s *Store) LatestAll() []*snmp.PrinterStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	latest := make([]*snmp.PrinterStatus, 0, len(s.history))
+	for _, samples := range s.history {
+		if len(samples) > 0 {
+			latest = append(latest, samples[len(samples)-1])
+		}
+	}
+	sort.Slice(latest, func(i, j int) bool {
+		return latest[i].Host < latest[j].Host
+	})
+	return latest
+}
+
+// History returns all stored snapshots of a printer, oldest first
+func (s *Store) History(host string) []*snmp.PrinterStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]*snmp.PrinterStatus(nil), s.history[host]...)
+}
+
+// Hosts returns the hosts that have at least one snapshot, sorted
+func (s *Store) Hosts() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hosts := make([]string, 0, len(s.history))
+	for host := range s.history {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// Save writes the history to a JSON file. The file is replaced atomically so a
+// crash while saving never leaves a truncated history behind.
+func (s *Store) Save(path string) error {
+	s.mu.RLock()
+	data, err := json.Marshal(s.history)
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode history: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	return nil
+}
+
+// Load reads a history file written by Save. A missing file is not an error.
+func (s *Store) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+
+	history := make(map[string][]*snmp.PrinterStatus)
+	if err := json.Unmarshal(data, &history); err != nil {
+		return fmt.Errorf("failed to decode history %s: %w", path, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for host, samples := range history {
+		if len(samples) > s.maxSamples {
+			samples = samples[len(samples)-s.maxSamples:]
+		}
+		s.history[host] = samples
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"lynk/agent/internal/snmp"
+)
+
+func poll(host string, pages int) *snmp.PrinterStatus {
+	return &snmp.PrinterStatus{Host: host, TotalPages: pages, LastSeen: time.Date(2026, 3, 1, 12, pages, 0, 0, time.UTC)}
+}
+
+func pages(history []*snmp.PrinterStatus) []int {
+	var counts []int
+	for _, status := range history {
+		counts = append(counts, status.TotalPages)
+	}
+	return counts
+}
+
+func TestStore(t *testing.T) {
+	s := New(3)
+	for i := 1; i <= 4; i++ {
+		s.Add(poll("b", i))
+	}
+	s.Add(poll("a", 10))
+
+	// The oldest sample was dropped
+	if got := pages(s.History("b")); !reflect.DeepEqual(got, []int{2, 3, 4}) {
+		t.Errorf("history = %v", got)
+	}
+	if latest, ok := s.Latest("b"); !ok || latest.TotalPages != 4 {
+		t.Errorf("latest = %+v, %t", latest, ok)
+	}
+	if _, ok := s.Latest("c"); ok {
+		t.Error("latest of an unknown host")
+	}
+	if got := pages(s.LatestAll()); !reflect.DeepEqual(got, []int{10, 4}) {
+		t.Errorf("latest of all = %v", got)
+	}
+	if got := s.Hosts(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("hosts = %q", got)
+	}
+
+	// The latest sample that matches
+	even := func(status *snmp.PrinterStatus) bool { return status.TotalPages%2 == 0 }
+	if latest, ok := s.LatestWhere("b", even); !ok || latest.TotalPages != 4 {
+		t.Errorf("latest even = %+v, %t", latest, ok)
+	}
+	odd := func(status *snmp.PrinterStatus) bool { return status.TotalPages%2 == 1 }
+	if latest, ok := s.LatestWhere("b", odd); !ok || latest.TotalPages != 3 {
+		t.Errorf("latest odd = %+v, %t", latest, ok)
+	}
+	if _, ok := s.LatestWhere("a", odd); ok {
+		t.Error("latest odd of a host without one")
+	}
+
+	// History returns a copy
+	history := s.History("b")
+	history[0] = poll("b", 99)
+	if got := pages(s.History("b")); got[0] != 2 {
+		t.Errorf("history changed through a copy: %v", got)
+	}
+
+	if New(0).maxSamples != DefaultMaxSamples {
+		t.Error("a store without a limit isn't bounded")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history.json")
+
+	s := New(10)
+	for i := 1; i <= 5; i++ {
+		s.Add(poll("a", i))
+	}
+	s.Add(poll("b", 7))
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// A smaller store keeps the most recent samples
+	loaded := New(2)
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := pages(loaded.History("a")); !reflect.DeepEqual(got, []int{4, 5}) {
+		t.Errorf("loaded history = %v", got)
+	}
+	if latest, _ := loaded.Latest("b"); latest == nil || !latest.LastSeen.Equal(poll("b", 7).LastSeen) {
+		t.Errorf("loaded latest = %+v", latest)
+	}
+
+	// No temporary files are left behind
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("files after saving = %v", entries)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := New(0).Load(filepath.Join(dir, "missing.json")); err != nil {
+		t.Errorf("Load of a missing file: %v", err)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	os.WriteFile(corrupt, []byte(`{"a": [`), 0o644)
+	if err := New(0).Load(corrupt); err == nil {
+		t.Error("Load of a truncated file succeeded")
+	}
+
+	if err := New(0).Save(filepath.Join(dir, "missing", "history.json")); err == nil {
+		t.Error("Save into a missing directory succeeded")
+	}
+}
@@ -0,0 +1,128 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"lynk/agent/internal/forecast"
+	"lynk/agent/internal/health"
+	"lynk/agent/internal/snmp"
+)
+
+// labelEscaper escapes label values as the Prometheus text format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// handleMetrics exposes the latest poll results in the Prometheus text format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	latest := s.store.LatestAll()
+
+	writeHelp(w, "lynk_printer_up", "gauge", "Whether the printer answered its last poll")
+	for _, p := range latest {
+		up := 1
+		if s.tracker != nil {
+			if target, ok := s.tracker.Get(p.Host); ok && target.ConsecutiveFailures > 0 {
+				up = 0
+			}
+		}
+		writeSample(w, "lynk_printer_up", labels("host", p.Host), float64(up))
+	}
+	if s.tracker != nil {
+		for _, target := range s.tracker.All() {
+			if _, ok := s.store.Latest(target.Host); !ok {
+				writeSample(w, "lynk_printer_up", labels("host", target.Host), 0)
+			}
+		}
+	}
+
+	writeHelp(w, "lynk_printer_pages_total", "counter", "Pages printed, corrected for counter resets, wraps and device swaps")
+	for _, p := range latest {
+		pages := p.MonotonicPages
+		if pages == 0 {
+			pages = int64(p.TotalPages)
+		}
+		writeSample(w, "lynk_printer_pages_total", labels("host", p.Host), float64(pages))
+	}
+
+	writeHelp(w, "lynk_printer_last_seen_timestamp_seconds", "gauge", "When the printer was last polled successfully")
+	for _, p := range latest {
+		writeSample(w, "lynk_printer_last_seen_timestamp_seconds", labels("host", p.Host), float64(p.LastSeen.Unix()))
+	}
+
+	var forecasts []forecast.Forecast
+	for _, p := range latest {
+		forecasts = append(forecasts, forecast.Supplies(s.store.History(p.Host), s.window)...)
+	}
+
+	writeHelp(w, "lynk_supply_level_percent", "gauge", "Supply level as a percentage of its capacity")
+	for _, f := range forecasts {
+		if f.Percent >= 0 {
+			writeSample(w, "lynk_supply_level_percent", supplyLabels(f), float64(f.Percent))
+		}
+	}
+
+	writeHelp(w, "lynk_supply_days_remaining", "gauge", "Forecast days until the supply runs out")
+	for _, f := range forecasts {
+		if f.DaysRemaining != nil {
+			writeSample(w, "lynk_supply_days_remaining", supplyLabels(f), *f.DaysRemaining)
+		}
+	}
+
+	writeHelp(w, "lynk_supply_pages_remaining", "gauge", "Forecast pages until the supply runs out")
+	for _, f := range forecasts {
+		if f.PagesRemaining != nil {
+			writeSample(w, "lynk_supply_pages_remaining", supplyLabels(f), float64(*f.PagesRemaining))
+		}
+	}
+
+	if s.alerts != nil {
+		writeHelp(w, "lynk_alert_firing", "gauge", "Alert rules currently firing")
+		for _, alert := range s.alerts.Active("") {
+			// Subjects may share a name, the ID keeps their series apart
+			writeSample(w, "lynk_alert_firing", labels("host", alert.Host, "rule", alert.Rule, "subject", alert.Subject, "subject_id", alert.ID, "severity", alert.Severity), 1)
+		}
+	}
+
+	if s.tracker != nil {
+		writeHelp(w, "lynk_printer_circuit_open", "gauge", "Whether the printer is treated as offline and probed with backoff")
+		for _, target := range s.tracker.All() {
+			open := 0
+			if target.State == health.StateOpen {
+				open = 1
+			}
+			writeSample(w, "lynk_printer_circuit_open", labels("host", target.Host), float64(open))
+		}
+	}
+}
+
+// supplyLabels identifies a supply in metric labels
+func supplyLabels(f forecast.Forecast) string {
+	return labels("host", f.Host, "supply", f.SupplyIndex, "description", f.Description, "type", snmp.SupplyTypeName(f.Type))
+}
+
+// labels formats name/value pairs as a Prometheus label set
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", pairs[i], labelEscaper.Replace(pairs[i+1]))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// writeHelp writes the HELP and TYPE lines of a metric
+func writeHelp(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// writeSample writes a single metric sample
+func writeSample(w io.Writer, name, labels string, value float64) {
+	fmt.Fprintf(w, "%s%s %g\n", name, labels, value)
+}
@@ -0,0 +1,40 @@
+package web
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	response := get(t, fleet(t), "/metrics")
+	if response.Code != 200 || !strings.HasPrefix(response.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("metrics = %d %v", response.Code, response.Header())
+	}
+	metrics := response.Body.String()
+
+	for _, want := range []string{
+		"# TYPE lynk_printer_up gauge\n",
+		`lynk_printer_up{host="10.0.0.5"} 1` + "\n",
+		`lynk_printer_up{host="10.0.0.7"} 0` + "\n",
+		`lynk_printer_pages_total{host="10.0.0.5"} 5200` + "\n",
+		`lynk_printer_pages_total{host="10.0.0.6"} 70` + "\n",
+		`lynk_supply_level_percent{host="10.0.0.5",supply="1.1",description="Black \"K\" Toner",type="toner"} 56` + "\n",
+		`lynk_supply_days_remaining{host="10.0.0.5",supply="1.1",description="Black \"K\" Toner",type="toner"} 28` + "\n",
+		`lynk_alert_firing{host="10.0.0.5",rule="output_full",subject="Mailbox",subject_id="2",severity="critical"} 1` + "\n",
+		`lynk_alert_firing{host="10.0.0.5",rule="output_full",subject="Mailbox",subject_id="3",severity="critical"} 1` + "\n",
+		`lynk_printer_circuit_open{host="10.0.0.7"} 1` + "\n",
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("metrics lack %q", want)
+		}
+	}
+}
+
+func TestLabels(t *testing.T) {
+	if got := labels("host", "a", "description", "Tray \"2\"\\\nlower"); got != `{host="a",description="Tray \"2\"\\\nlower"}` {
+		t.Errorf("labels = %s", got)
+	}
+	if got := labels(); got != "{}" {
+		t.Errorf("labels = %s", got)
+	}
+}
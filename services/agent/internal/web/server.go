@@ -0,0 +1,393 @@
+package web
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"lynk/agent/internal/alerts"
+	"lynk/agent/internal/audit"
+	"lynk/agent/internal/config"
+	"lynk/agent/internal/events"
+	"lynk/agent/internal/forecast"
+	"lynk/agent/internal/health"
+	"lynk/agent/internal/inventory"
+	"lynk/agent/internal/report"
+	"lynk/agent/internal/schema"
+	"lynk/agent/internal/snmp"
+	"lynk/agent/internal/store"
+)
+
+// The dashboard is served from the binary so it works on isolated networks
+//
+//go:embed static
+var staticFiles embed.FS
+
+// Options configures the dashboard server
+type Options struct {
+	Store      *store.Store    // Poll history, required
+	Events     *events.Log     // Reboots, counter resets and device swaps, optional
+	Audit      *audit.Log      // Inventory changes such as firmware upgrades, optional
+	Health     *health.Tracker // Reachability of each target, optional
+	StaleAfter time.Duration   // Printers not seen within this window are shown as offline
+	Alerts     *alerts.Engine  // Firing alert rules, optional
+	Window     time.Duration   // History used for supply forecasts, defaults to forecast.DefaultWindow
+	Config     *config.Config  // Site, department and tags used by reports, optional
+	Schema     int             // Printer JSON version served unless ?schema= asks otherwise, defaults to schema.Current
+}
+
+// Server serves the fleet dashboard and its JSON API
+type Server struct {
+	store      *store.Store
+	events     *events.Log
+	audit      *audit.Log
+	tracker    *health.Tracker
+	alerts     *alerts.Engine
+	staleAfter time.Duration
+	window     time.Duration
+	config     *config.Config
+	schema     int
+	mux        *http.ServeMux
+}
+
+// printerView is a printer snapshot together with its dashboard health
+type printerView struct {
+	*snmp.PrinterStatus
+	Health       snmp.Health    `json:"health"` // Overrides the snapshot's health with offline
+	Reachability *health.Target `json:"reachability,omitempty"`
+}
+
+// historyPoint is a single sample of the page count history charts
+type historyPoint struct {
+	Time       time.Time `json:"time"`
+	TotalPages int       `json:"total_pages"`
+	TonerLevel int       `json:"toner_level"`
+}
+
+// New creates a new dashboard server
+func New(opts Options) *Server {
+	srv := &Server{
+		store:      opts.Store,
+		events:     opts.Events,
+		audit:      opts.Audit,
+		tracker:    opts.Health,
+		alerts:     opts.Alerts,
+		staleAfter: opts.StaleAfter,
+		window:     opts.Window,
+		config:     opts.Config,
+		schema:     opts.Schema,
+		mux:        http.NewServeMux(),
+	}
+	if srv.config == nil {
+		srv.config = &config.Config{}
+	}
+	if srv.window <= 0 {
+		srv.window = forecast.DefaultWindow
+	}
+	if srv.schema == 0 {
+		srv.schema = schema.Current
+	}
+
+	static, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		panic(err) // The embedded directory is always present
+	}
+
+	srv.mux.HandleFunc("/api/printers", srv.handlePrinters)
+	srv.mux.HandleFunc("/api/printers/", srv.handlePrinter)
+	srv.mux.HandleFunc("/api/health", srv.handleHealth)
+	srv.mux.HandleFunc("/api/forecasts", srv.handleForecasts)
+	srv.mux.HandleFunc("/api/alerts", srv.handleAlerts)
+	srv.mux.HandleFunc("/api/events", srv.handleEvents)
+	srv.mux.HandleFunc("/api/audit", srv.handleAudit)
+	srv.mux.HandleFunc("/api/reports/usage", srv.handleUsageReport)
+	srv.mux.HandleFunc("/api/inventory", srv.handleInventory)
+	srv.mux.HandleFunc("/api/schema", srv.handleSchema)
+	srv.mux.HandleFunc("/metrics", srv.handleMetrics)
+	srv.mux.Handle("/", http.FileServer(http.FS(static)))
+	return srv
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handlePrinters lists the latest snapshot of every printer
+func (s *Server) handlePrinters(w http.ResponseWriter, r *http.Request) {
+	version, err := schema.ParseVersion(r.URL.Query().Get("schema"), s.schema)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	printers := []*snmp.PrinterStatus{}
+	seen := make(map[string]bool)
+	for _, p := range s.store.LatestAll() {
+		printers = append(printers, p)
+		seen[p.Host] = true
+	}
+
+	// Targets that have never answered still belong on the dashboard
+	if s.tracker != nil {
+		for _, target := range s.tracker.All() {
+			if !seen[target.Host] {
+				printers = append(printers, &snmp.PrinterStatus{Host: target.Host, Status: snmp.PrinterUnknown})
+			}
+		}
+	}
+
+	if version == schema.V1 {
+		views := []printerView{}
+		for _, p := range printers {
+			views = append(views, s.view(p, now))
+		}
+		writeJSON(w, views)
+		return
+	}
+	documents := []schema.Printer{}
+	for _, p := range printers {
+		documents = append(documents, s.document(p, now))
+	}
+	writeJSON(w, documents)
+}
+
+// handlePrinter serves /api/printers/{host} and its history and forecast
+func (s *Server) handlePrinter(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/printers/")
+	host, sub, _ := strings.Cut(path, "/")
+
+	p, ok := s.store.Latest(host)
+	if !ok && s.tracker != nil {
+		if _, tracked := s.tracker.Get(host); tracked {
+			p, ok = &snmp.PrinterStatus{Host: host, Status: snmp.PrinterUnknown}, true
+		}
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch sub {
+	case "":
+		version, err := schema.ParseVersion(r.URL.Query().Get("schema"), s.schema)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if version == schema.V1 {
+			writeJSON(w, s.view(p, time.Now()))
+		} else {
+			writeJSON(w, s.document(p, time.Now()))
+		}
+	case "history":
+		points := []historyPoint{}
+		for _, sample := range s.store.History(host) {
+			points = append(points, historyPoint{
+				Time:       sample.LastSeen,
+				TotalPages: sample.TotalPages,
+				TonerLevel: sample.TonerLevel,
+			})
+		}
+		writeJSON(w, points)
+	case "forecast":
+		writeJSON(w, forecast.Supplies(s.store.History(host), s.window))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleHealth lists the reachability of every polling target
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	targets := []health.Target{}
+	if s.tracker != nil {
+		targets = s.tracker.All()
+	}
+	writeJSON(w, targets)
+}
+
+// handleForecasts lists the supply forecasts of every printer
+func (s *Server) handleForecasts(w http.ResponseWriter, r *http.Request) {
+	forecasts := []forecast.Forecast{}
+	for _, host := range s.store.Hosts() {
+		forecasts = append(forecasts, forecast.Supplies(s.store.History(host), s.window)...)
+	}
+	writeJSON(w, forecasts)
+}
+
+// handleAlerts lists the firing alert rules, optionally for ?host=
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	firing := []alerts.Alert{}
+	if s.alerts != nil {
+		firing = s.alerts.Active(r.URL.Query().Get("host"))
+	}
+	writeJSON(w, firing)
+}
+
+// handleEvents lists device events, filtered by ?host=, ?type= and ?since= (RFC 3339)
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	since, ok := parseSince(w, query.Get("since"))
+	if !ok {
+		return
+	}
+
+	matched := []events.Event{}
+	if s.events != nil {
+		matched = s.events.Query(query.Get("host"), events.Type(query.Get("type")), since)
+	}
+	writeJSON(w, matched)
+}
+
+// handleAudit lists device changes, filtered by ?host=, ?kind= and ?since= (RFC 3339)
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	since, ok := parseSince(w, query.Get("since"))
+	if !ok {
+		return
+	}
+
+	changes := []audit.Change{}
+	if s.audit != nil {
+		changes = s.audit.Query(query.Get("host"), audit.Kind(query.Get("kind")), since)
+	}
+	writeJSON(w, changes)
+}
+
+// parseSince parses an optional RFC 3339 ?since= filter, replying with an error if it is invalid
+func parseSince(w http.ResponseWriter, value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, true
+	}
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		http.Error(w, "invalid since, expected RFC 3339", http.StatusBadRequest)
+		return time.Time{}, false
+	}
+	return since, true
+}
+
+// handleUsageReport serves the page usage report for ?month=YYYY-MM (default
+// the current month), grouped by ?by= and exported as ?format=json or csv
+func (s *Server) handleUsageReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	period := report.Month(time.Now())
+	if month := query.Get("month"); month != "" {
+		var err error
+		if period, err = report.ParseMonth(month); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	by := query.Get("by")
+	if by == "" {
+		by = report.ByDevice
+	}
+	format := query.Get("format")
+	if format == "" {
+		format = report.FormatJSON
+	}
+
+	usage, err := report.Usage(s.store, s.config, period, by)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch format {
+	case report.FormatCSV:
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="usage-%s-%s.csv"`, period.From.Format("2006-01"), by))
+	case report.FormatJSON:
+		w.Header().Set("Content-Type", "application/json")
+	default:
+		http.Error(w, fmt.Sprintf("unknown format %q, expected csv or json", format), http.StatusBadRequest)
+		return
+	}
+	if err := usage.Write(w, format); err != nil {
+		log.Printf("Error writing usage report: %v", err)
+	}
+}
+
+// handleInventory exports the fleet inventory with ?columns= (comma separated)
+// as ?format=json, csv or xlsx-csv
+func (s *Server) handleInventory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	inv, err := inventory.Build(s.store.LatestAll(), s.config, inventory.ParseColumns(query.Get("columns")))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := query.Get("format")
+	switch format {
+	case "", inventory.FormatJSON:
+		format = inventory.FormatJSON
+		w.Header().Set("Content-Type", "application/json")
+	case inventory.FormatCSV, inventory.FormatExcel:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="inventory.csv"`)
+	default:
+		http.Error(w, fmt.Sprintf("unknown format %q, expected csv, xlsx-csv or json", format), http.StatusBadRequest)
+		return
+	}
+	if err := inv.Write(w, format); err != nil {
+		log.Printf("Error writing inventory: %v", err)
+	}
+}
+
+// view pairs a snapshot with its health and reachability
+func (s *Server) view(p *snmp.PrinterStatus, now time.Time) printerView {
+	v := printerView{PrinterStatus: p}
+	if s.tracker != nil {
+		if target, ok := s.tracker.Get(p.Host); ok {
+			v.Reachability = &target
+		}
+	}
+	v.Health = s.health(p, v.Reachability, now)
+	return v
+}
+
+// document converts a snapshot to the v2 schema with its health and reachability
+func (s *Server) document(p *snmp.PrinterStatus, now time.Time) schema.Printer {
+	v := s.view(p, now)
+	return schema.FromStatus(p, v.Health, v.Reachability)
+}
+
+// handleSchema serves the JSON Schema of the v2 printer JSON
+func (s *Server) handleSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	if _, err := w.Write(schema.Document()); err != nil {
+		log.Printf("Error writing schema: %v", err)
+	}
+}
+
+// health is the printer's own health rollup, or offline when the printer is
+// backing off or missed its polls
+func (s *Server) health(p *snmp.PrinterStatus, reachability *health.Target, now time.Time) snmp.Health {
+	if reachability != nil && reachability.State == health.StateOpen {
+		return snmp.HealthOffline
+	}
+	if s.staleAfter > 0 && now.Sub(p.LastSeen) > s.staleAfter {
+		return snmp.HealthOffline
+	}
+	return p.Rollup()
+}
+
+// writeJSON encodes v as the response body
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lynk/agent/internal/alerts"
+	"lynk/agent/internal/audit"
+	"lynk/agent/internal/config"
+	"lynk/agent/internal/events"
+	"lynk/agent/internal/health"
+	"lynk/agent/internal/snmp"
+	"lynk/agent/internal/store"
+)
+
+// fleet serves a printer that is up, one that went quiet and one that never
+// answered
+func fleet(t *testing.T) *Server {
+	t.Helper()
+	now := time.Now()
+
+	history := store.New(0)
+	for i, level := range []int{60, 58, 56} {
+		history.Add(&snmp.PrinterStatus{
+			Host:           "10.0.0.5",
+			Status:         snmp.PrinterIdle,
+			DeviceState:    snmp.DeviceRunning,
+			TotalPages:     1000 + 100*i,
+			MonotonicPages: int64(5000 + 100*i),
+			TonerLevel:     level,
+			Supplies:       []snmp.Supply{{Index: "1.1", Type: 3, Description: `Black "K" Toner`, Percent: level}},
+			OutputBins: []snmp.OutputBin{
+				{Index: 2, Name: "Mailbox", Condition: "full"},
+				{Index: 3, Name: "Mailbox", Condition: "full"},
+			},
+			LastSeen: now.Add(time.Duration(i-2) * 24 * time.Hour),
+		})
+	}
+	history.Add(&snmp.PrinterStatus{Host: "10.0.0.6", Status: snmp.PrinterIdle, DeviceState: snmp.DeviceRunning, TotalPages: 70, LastSeen: now.Add(-2 * time.Hour)})
+
+	tracker := health.NewTracker(1, time.Minute, time.Hour)
+	tracker.RecordSuccess("10.0.0.5", now)
+	tracker.RecordFailure("10.0.0.7", errors.New("request timeout"), now)
+
+	eventLog := events.NewLog(0)
+	eventLog.Add(events.Event{Type: events.Reboot, Host: "10.0.0.5", Time: now})
+	auditLog := audit.NewLog()
+	auditLog.Add(audit.Change{Host: "10.0.0.5", Time: now, Kind: audit.FirmwareChanged, Field: "firmware_version", New: "2"})
+
+	rule := alerts.Rule{Name: "output_full", Scope: alerts.ScopeOutput, Condition: "full == true", Severity: "critical"}
+	if err := rule.Compile(); err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	engine := alerts.NewEngine([]alerts.Rule{rule})
+	latest, _ := history.Latest("10.0.0.5")
+	engine.Evaluate(latest.Host, alerts.OutputSubjects(latest), now)
+
+	return New(Options{
+		Store:      history,
+		Events:     eventLog,
+		Audit:      auditLog,
+		Health:     tracker,
+		Alerts:     engine,
+		StaleAfter: time.Hour,
+		Config:     &config.Config{Targets: []config.Target{{Host: "10.0.0.5", Name: "Reception", Department: "Finance"}}},
+	})
+}
+
+// get serves a request and returns the response
+func get(t *testing.T, srv *Server, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	srv.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+// decode decodes a JSON response into v
+func decode(t *testing.T, response *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if response.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", response.Code, response.Body)
+	}
+	if err := json.Unmarshal(response.Body.Bytes(), v); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+}
+
+func TestPrinters(t *testing.T) {
+	srv := fleet(t)
+
+	var documents []struct {
+		SchemaVersion int    `json:"schema_version"`
+		Host          string `json:"host"`
+		Status        struct {
+			Health snmp.Health `json:"health"`
+		} `json:"status"`
+	}
+	decode(t, get(t, srv, "/api/printers"), &documents)
+	want := map[string]snmp.Health{
+		"10.0.0.5": snmp.HealthCritical, // Output bins full
+		"10.0.0.6": snmp.HealthOffline,  // Not seen for two hours
+		"10.0.0.7": snmp.HealthOffline,  // Never answered
+	}
+	if len(documents) != len(want) {
+		t.Fatalf("printers = %+v", documents)
+	}
+	for _, d := range documents {
+		if d.SchemaVersion != 2 || d.Status.Health != want[d.Host] {
+			t.Errorf("%s: version %d, health %s, want %s", d.Host, d.SchemaVersion, d.Status.Health, want[d.Host])
+		}
+	}
+
+	var views []struct {
+		Host         string         `json:"host"`
+		Health       snmp.Health    `json:"health"`
+		Reachability *health.Target `json:"reachability"`
+	}
+	decode(t, get(t, srv, "/api/printers?schema=v1"), &views)
+	if len(views) != 3 || views[0].Reachability == nil || views[2].Health != snmp.HealthOffline || views[2].Reachability.State != health.StateOpen {
+		t.Errorf("v1 printers = %+v", views)
+	}
+
+	if response := get(t, srv, "/api/printers?schema=3"); response.Code != http.StatusBadRequest {
+		t.Errorf("unknown schema status = %d", response.Code)
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	srv := fleet(t)
+
+	var document struct {
+		Host string `json:"host"`
+	}
+	decode(t, get(t, srv, "/api/printers/10.0.0.5"), &document)
+	if document.Host != "10.0.0.5" {
+		t.Errorf("printer = %+v", document)
+	}
+
+	var points []historyPoint
+	decode(t, get(t, srv, "/api/printers/10.0.0.5/history"), &points)
+	if len(points) != 3 || points[2].TotalPages != 1200 || points[2].TonerLevel != 56 {
+		t.Errorf("history = %+v", points)
+	}
+
+	var forecasts []struct {
+		PercentPerDay float64 `json:"percent_per_day"`
+	}
+	decode(t, get(t, srv, "/api/printers/10.0.0.5/forecast"), &forecasts)
+	if len(forecasts) != 1 || forecasts[0].PercentPerDay != 2 {
+		t.Errorf("forecast = %+v", forecasts)
+	}
+
+	// A target that never answered is known, an unknown host isn't
+	if response := get(t, srv, "/api/printers/10.0.0.7"); response.Code != http.StatusOK {
+		t.Errorf("tracked printer status = %d", response.Code)
+	}
+	for _, path := range []string{"/api/printers/10.0.0.9", "/api/printers/10.0.0.5/jobs"} {
+		if response := get(t, srv, path); response.Code != http.StatusNotFound {
+			t.Errorf("%s status = %d", path, response.Code)
+		}
+	}
+}
+
+func TestLogs(t *testing.T) {
+	srv := fleet(t)
+
+	var reboots []events.Event
+	decode(t, get(t, srv, "/api/events?host=10.0.0.5&type=reboot"), &reboots)
+	if len(reboots) != 1 {
+		t.Errorf("events = %+v", reboots)
+	}
+	var changes []audit.Change
+	decode(t, get(t, srv, "/api/audit?kind=tray_added"), &changes)
+	if changes == nil || len(changes) != 0 {
+		t.Errorf("audit = %+v", changes)
+	}
+	var targets []health.Target
+	decode(t, get(t, srv, "/api/health"), &targets)
+	if len(targets) != 2 || targets[1].LastError != "request timeout" {
+		t.Errorf("health = %+v", targets)
+	}
+	var firing []alerts.Alert
+	decode(t, get(t, srv, "/api/alerts?host=10.0.0.5"), &firing)
+	if len(firing) != 2 {
+		t.Errorf("alerts = %+v", firing)
+	}
+
+	for _, path := range []string{"/api/events?since=yesterday", "/api/audit?since=2026-03-01"} {
+		if response := get(t, srv, path); response.Code != http.StatusBadRequest {
+			t.Errorf("%s status = %d", path, response.Code)
+		}
+	}
+
+	// Without the optional logs the lists are empty, not null
+	bare := New(Options{Store: store.New(0)})
+	for _, path := range []string{"/api/events", "/api/audit", "/api/health", "/api/alerts", "/api/forecasts", "/api/printers"} {
+		if body := strings.TrimSpace(get(t, bare, path).Body.String()); body != "[]" {
+			t.Errorf("%s = %s", path, body)
+		}
+	}
+}
+
+func TestExports(t *testing.T) {
+	srv := fleet(t)
+	month := time.Now().Format("2006-01")
+
+	response := get(t, srv, "/api/reports/usage?format=csv&by=department&month="+month)
+	if response.Code != http.StatusOK || response.Header().Get("Content-Type") != "text/csv" ||
+		!strings.Contains(response.Header().Get("Content-Disposition"), "usage-"+month+"-department.csv") {
+		t.Errorf("usage CSV = %d %v", response.Code, response.Header())
+	}
+	var usage struct {
+		Devices []struct {
+			Host string `json:"host"`
+		} `json:"devices"`
+	}
+	decode(t, get(t, srv, "/api/reports/usage?month="+month), &usage)
+	if len(usage.Devices) == 0 {
+		t.Errorf("usage = %+v", usage)
+	}
+
+	response = get(t, srv, "/api/inventory?format=xlsx-csv&columns=host,name")
+	if response.Code != http.StatusOK || !strings.Contains(response.Body.String(), "10.0.0.5,Reception\r\n") {
+		t.Errorf("inventory = %d %q", response.Code, response.Body)
+	}
+
+	for _, path := range []string{
+		"/api/reports/usage?month=March",
+		"/api/reports/usage?by=building",
+		"/api/reports/usage?format=xml",
+		"/api/inventory?columns=host,colour",
+		"/api/inventory?format=xml",
+	} {
+		if response := get(t, srv, path); response.Code != http.StatusBadRequest {
+			t.Errorf("%s status = %d", path, response.Code)
+		}
+	}
+}
+
+func TestStatic(t *testing.T) {
+	srv := fleet(t)
+
+	if response := get(t, srv, "/"); response.Code != http.StatusOK || !strings.Contains(response.Body.String(), "<html") {
+		t.Errorf("dashboard = %d", response.Code)
+	}
+	response := get(t, srv, "/api/schema")
+	if response.Code != http.StatusOK || response.Header().Get("Content-Type") != "application/schema+json" || !json.Valid(response.Body.Bytes()) {
+		t.Errorf("schema = %d %v", response.Code, response.Header())
+	}
+}
@@ -0,0 +1,399 @@
+// Lynk dashboard. Plain JavaScript with no external dependencies so it works on isolated networks.
+var lynk = (function () {
+  "use strict";
+
+  var REFRESH_MS = 30000;
+
+  // Supply types from prtMarkerSuppliesType
+  var SUPPLY_TONER = 3;
+  var SUPPLY_DRUM = 9;
+
+  // Tray conditions, matching PaperTray.Condition
+  var TRAY_CONDITION = { ok: "OK", empty: "Empty", warning: "Warning", error: "Error", offline: "Offline", unknown: "Unknown" };
+
+  function el(tag, attrs, children) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (key) {
+      node.setAttribute(key, attrs[key]);
+    });
+    (children || []).forEach(function (child) {
+      node.appendChild(typeof child === "string" ? document.createTextNode(child) : child);
+    });
+    return node;
+  }
+
+  function clear(node) {
+    while (node.firstChild) {
+      node.removeChild(node.firstChild);
+    }
+    return node;
+  }
+
+  function getJSON(url) {
+    return fetch(url).then(function (resp) {
+      if (!resp.ok) {
+        throw new Error(url + ": " + resp.status);
+      }
+      return resp.json();
+    });
+  }
+
+  function stamp() {
+    document.getElementById("updated").textContent = "Updated " + new Date().toLocaleTimeString();
+  }
+
+  // levelBar renders a labelled bar for a percentage, or a hatched bar if the level is unknown
+  function levelBar(label, percent) {
+    var known = typeof percent === "number" && percent >= 0;
+    var fill = el("span");
+    if (known) {
+      fill.style.width = Math.min(percent, 100) + "%";
+      if (percent <= 5) {
+        fill.className = "empty";
+      } else if (percent <= 15) {
+        fill.className = "low";
+      }
+    } else {
+      fill.style.width = "100%";
+      fill.className = "unknown";
+    }
+    return el("div", {}, [
+      el("div", { class: "label" }, [el("span", {}, [label]), el("span", {}, [known ? percent + "%" : "unknown"])]),
+      el("div", { class: "bar" }, [fill])
+    ]);
+  }
+
+  // supplyBars renders toner and drum bars, falling back to the flat toner level for older agents
+  function supplyBars(p, all) {
+    var bars = [];
+    (p.supplies || []).forEach(function (s) {
+      if (all || s.type === SUPPLY_TONER || s.type === SUPPLY_DRUM) {
+        var name = s.description || (s.type === SUPPLY_DRUM ? "Drum" : "Toner");
+        bars.push(levelBar(name, s.percent));
+      }
+    });
+    if (bars.length === 0) {
+      bars.push(levelBar("Toner", p.toner_level > 0 ? p.toner_level : -1));
+    }
+    return bars;
+  }
+
+  // mediaSize formats a media size in millimetres
+  function mediaSize(size) {
+    return size.width_mm + " x " + size.length_mm + " mm";
+  }
+
+  function trayList(p) {
+    var trays = p.paper_trays || [];
+    if (trays.length === 0) {
+      return el("p", { class: "muted" }, ["No trays reported"]);
+    }
+    return el("ul", { class: "trays" }, trays.map(function (t) {
+      var text = TRAY_CONDITION[t.condition] || "Unknown";
+      if (t.percent >= 0) {
+        text += ", " + t.percent + "% full";
+      }
+      if (t.media_size) {
+        text += ", " + mediaSize(t.media_size);
+      }
+      var bad = t.condition === "empty" || t.condition === "error";
+      return el("li", { class: bad ? "tray-empty" : "" }, [t.name + ": " + text]);
+    }));
+  }
+
+  function outputList(p) {
+    var bins = p.output_bins || [];
+    if (bins.length === 0) {
+      return el("p", { class: "muted" }, ["No output bins reported"]);
+    }
+    return el("ul", { class: "trays" }, bins.map(function (b) {
+      var text = b.condition === "full" ? "Full" : TRAY_CONDITION[b.condition] || "Unknown";
+      if (b.percent_full >= 0) {
+        text += ", " + b.percent_full + "% full";
+      }
+      var bad = b.condition === "full" || b.condition === "error";
+      return el("li", { class: bad ? "tray-empty" : "" }, [b.name + ": " + text]);
+    }));
+  }
+
+  // bytes formats a byte count in binary units
+  function bytes(n) {
+    var units = ["B", "KB", "MB", "GB", "TB"], i = 0;
+    while (n >= 1024 && i < units.length - 1) {
+      n /= 1024;
+      i++;
+    }
+    return (i === 0 || n >= 10 ? Math.round(n) : n.toFixed(1)) + " " + units[i];
+  }
+
+  // storageList shows the memory and disk usage and any disk that is failing
+  function storageList(p) {
+    var storage = p.storage || [];
+    var disks = (p.devices || []).filter(function (d) { return d.type === "disk_storage"; });
+    if (storage.length === 0 && disks.length === 0 && !p.memory_size) {
+      return el("p", { class: "muted" }, ["No storage reported"]);
+    }
+
+    var items = [];
+    if (p.memory_size) {
+      items.push(el("li", {}, ["Memory: " + p.memory_size]));
+    }
+    storage.forEach(function (s) {
+      var text = s.percent >= 0 ? bytes(s.used_bytes) + " of " + bytes(s.size_bytes) + " used (" + s.percent + "%)" : "Size unknown";
+      var bad = s.condition === "full" || s.condition === "warning";
+      items.push(el("li", { class: bad ? "tray-empty" : "" }, [s.description + ": " + text]));
+    });
+    disks.forEach(function (d) {
+      var bad = d.state === "down" || d.state === "warning";
+      items.push(el("li", { class: bad ? "tray-empty" : "" }, [d.description + ": " + d.state]));
+    });
+    return el("ul", { class: "trays" }, items);
+  }
+
+  // Colors of prtConsoleColor, as shown on the dashboard
+  var LIGHT_COLOR = {
+    white: "#d0d7de", red: "#cf222e", green: "#2da44e", blue: "#0969da",
+    cyan: "#1b9aaa", magenta: "#bf3989", yellow: "#d4a72c", orange: "#e16f24"
+  };
+
+  // consolePanel shows the front panel display text and indicator lights
+  function consolePanel(p) {
+    var lines = p.display_lines || [];
+    var lights = p.lights || [];
+    if (lines.length === 0 && lights.length === 0) {
+      return el("p", { class: "muted" }, ["No front panel reported"]);
+    }
+
+    var panel = el("div", {}, []);
+    if (lines.length > 0) {
+      panel.appendChild(el("pre", { class: "display" }, [lines.join("\n")]));
+    }
+    if (lights.length > 0) {
+      panel.appendChild(el("ul", { class: "trays" }, lights.map(function (l) {
+        var color = l.state === "off" ? "#eaeef2" : LIGHT_COLOR[l.color] || "#8c959f";
+        var dot = el("span", { class: "dot" + (l.state === "blink" ? " blink" : ""), style: "background: " + color }, []);
+        return el("li", {}, [dot, " " + l.description + ": " + l.state]);
+      })));
+    }
+    return panel;
+  }
+
+  function alertList(p) {
+    var alerts = p.active_alerts || [];
+    if (alerts.length === 0) {
+      return el("p", { class: "muted" }, ["No active alerts"]);
+    }
+    return el("ul", { class: "alerts" }, alerts.map(function (a) {
+      return el("li", {}, [a]);
+    }));
+  }
+
+  // forecastTable shows when each supply is expected to run out
+  function forecastTable(forecasts) {
+    if (forecasts.length === 0) {
+      return el("p", { class: "muted" }, ["No supplies reported"]);
+    }
+    return el("table", {}, forecasts.map(function (f) {
+      var text = "Not enough usage yet";
+      if (typeof f.days_remaining === "number") {
+        text = Math.floor(f.days_remaining) + " days";
+        if (typeof f.pages_remaining === "number") {
+          text += " / " + f.pages_remaining + " pages";
+        }
+        text += " (" + new Date(f.depletion_date).toLocaleDateString() + ")";
+      } else if (f.percent < 0) {
+        text = "Level unknown";
+      }
+      return el("tr", { class: f.days_remaining < 7 ? "tray-empty" : "" }, [
+        el("td", {}, [f.description || "Supply " + f.supply_index]),
+        el("td", {}, [text])
+      ]);
+    }));
+  }
+
+  function badge(health) {
+    return el("span", { class: "badge " + health }, [health]);
+  }
+
+  // capabilityRows describes duplex, media size and language support for the identity table
+  function capabilityRows(c) {
+    var rows = [];
+    if ((c.media_paths || []).length > 0) {
+      rows.push(["Duplex", c.duplex ? "Yes" : "No"]);
+    }
+    if (c.max_media_size) {
+      rows.push(["Largest media", mediaSize(c.max_media_size)]);
+    }
+    rows.push(["Languages", (c.languages || []).join(", ")]);
+    return rows;
+  }
+
+  // addresses lists the IP addresses of every interface
+  function addresses(p) {
+    return (p.interfaces || []).reduce(function (all, iface) {
+      return all.concat(iface.ip_addresses || []);
+    }, []).join(", ");
+  }
+
+  // coverNotes names every open cover or door
+  function coverNotes(p) {
+    return (p.covers || []).filter(function (c) { return c.open; }).map(function (c) {
+      return el("p", { class: "tray-empty" }, [c.description + " is open"]);
+    });
+  }
+
+  // offlineNote explains since when an unreachable printer has been offline
+  function offlineNote(p) {
+    var r = p.reachability;
+    if (!r || r.state !== "open") {
+      return null;
+    }
+    var text = "Offline since " + new Date(r.offline_since).toLocaleString();
+    if (r.next_probe) {
+      text += ", next check " + new Date(r.next_probe).toLocaleTimeString();
+    }
+    return el("p", { class: "offline-note" }, [text]);
+  }
+
+  function printerCard(p) {
+    var link = el("a", { href: "printer.html?host=" + encodeURIComponent(p.host) }, [p.printer_name || p.device_name || p.host]);
+    var children = [
+      el("h3", {}, [link]),
+      el("div", { class: "muted" }, [p.host + (p.model ? " - " + p.model : "")]),
+      el("p", {}, [badge(p.health), " " + p.status])
+    ];
+    if (offlineNote(p)) {
+      children.push(offlineNote(p));
+    }
+    children = children.concat(coverNotes(p));
+    children = children.concat(supplyBars(p, false));
+    children.push(trayList(p));
+    if ((p.active_alerts || []).length > 0) {
+      children.push(el("p", {}, [p.active_alerts.length + " active alert(s)"]));
+    }
+    return el("div", { class: "card " + p.health }, children);
+  }
+
+  function fleetPage() {
+    var fleet = document.getElementById("fleet");
+
+    function refresh() {
+      getJSON("api/printers?schema=1").then(function (printers) {
+        clear(fleet);
+        if (printers.length === 0) {
+          fleet.appendChild(el("p", { class: "muted" }, ["No printers have been polled yet"]));
+        }
+        printers.forEach(function (p) {
+          fleet.appendChild(printerCard(p));
+        });
+        stamp();
+      }).catch(function (err) {
+        document.getElementById("updated").textContent = "Update failed: " + err.message;
+      });
+    }
+
+    refresh();
+    setInterval(refresh, REFRESH_MS);
+  }
+
+  // pageChart draws the page count history as an SVG line chart
+  function pageChart(points) {
+    var NS = "http://www.w3.org/2000/svg";
+    var width = 800, height = 240, pad = 50;
+
+    if (points.length < 2) {
+      return el("p", { class: "muted" }, ["Not enough history yet"]);
+    }
+
+    function svg(tag, attrs, text) {
+      var node = document.createElementNS(NS, tag);
+      Object.keys(attrs).forEach(function (key) {
+        node.setAttribute(key, attrs[key]);
+      });
+      if (text !== undefined) {
+        node.textContent = text;
+      }
+      return node;
+    }
+
+    var times = points.map(function (pt) { return new Date(pt.time).getTime(); });
+    var pages = points.map(function (pt) { return pt.total_pages; });
+    var minT = Math.min.apply(null, times), maxT = Math.max.apply(null, times);
+    var minP = Math.min.apply(null, pages), maxP = Math.max.apply(null, pages);
+    if (maxT === minT) { maxT = minT + 1; }
+    if (maxP === minP) { maxP = minP + 1; }
+
+    function x(t) { return pad + (t - minT) / (maxT - minT) * (width - 2 * pad); }
+    function y(v) { return height - pad + (minP - v) / (maxP - minP) * (height - 2 * pad); }
+
+    var root = svg("svg", { viewBox: "0 0 " + width + " " + height, width: "100%" });
+    root.appendChild(svg("line", { class: "axis", x1: pad, y1: height - pad, x2: width - pad, y2: height - pad }));
+    root.appendChild(svg("line", { class: "axis", x1: pad, y1: pad, x2: pad, y2: height - pad }));
+    root.appendChild(svg("text", { x: 4, y: pad }, String(maxP)));
+    root.appendChild(svg("text", { x: 4, y: height - pad }, String(minP)));
+    root.appendChild(svg("text", { x: pad, y: height - pad + 20 }, new Date(minT).toLocaleString()));
+    root.appendChild(svg("text", { x: width - pad, y: height - pad + 20, "text-anchor": "end" }, new Date(maxT).toLocaleString()));
+
+    var path = points.map(function (pt, i) {
+      return (i === 0 ? "M" : "L") + x(times[i]).toFixed(1) + " " + y(pt.total_pages).toFixed(1);
+    }).join(" ");
+    root.appendChild(svg("path", { class: "line", d: path }));
+
+    var printed = maxP - minP;
+    return el("div", {}, [root, el("p", { class: "muted" }, [printed + " pages printed in this period"])]);
+  }
+
+  function printerPage() {
+    var host = new URLSearchParams(window.location.search).get("host");
+    var base = "api/printers/" + encodeURIComponent(host);
+    document.getElementById("title").textContent = host;
+
+    function refresh() {
+      Promise.all([getJSON(base + "?schema=1"), getJSON(base + "/history"), getJSON(base + "/forecast")]).then(function (results) {
+        var p = results[0], history = results[1], forecasts = results[2];
+        document.title = "Lynk - " + (p.printer_name || p.host);
+
+        clear(document.getElementById("status")).appendChild(el("p", {}, [badge(p.health), " " + p.status]));
+        if (offlineNote(p)) {
+          document.getElementById("status").appendChild(offlineNote(p));
+        }
+        coverNotes(p).forEach(function (note) { document.getElementById("status").appendChild(note); });
+        document.getElementById("status").appendChild(el("p", {}, ["Total pages: " + p.total_pages]));
+        document.getElementById("status").appendChild(el("p", { class: "muted" }, ["Last seen " + new Date(p.last_seen).toLocaleString()]));
+
+        clear(document.getElementById("console")).appendChild(consolePanel(p));
+
+        var supplies = clear(document.getElementById("supplies"));
+        supplyBars(p, true).forEach(function (bar) { supplies.appendChild(bar); });
+
+        clear(document.getElementById("forecast")).appendChild(forecastTable(forecasts));
+        clear(document.getElementById("trays")).appendChild(trayList(p));
+        clear(document.getElementById("outputs")).appendChild(outputList(p));
+        clear(document.getElementById("storage")).appendChild(storageList(p));
+        clear(document.getElementById("alerts")).appendChild(alertList(p));
+        clear(document.getElementById("chart")).appendChild(pageChart(history));
+
+        var identity = clear(document.getElementById("identity"));
+        [
+          ["Model", p.model], ["Serial number", p.serial_number], ["Firmware", p.firmware_version],
+          ["Device name", p.device_name], ["Printer name", p.printer_name], ["Description", p.system_description],
+          ["Location", p.location], ["Contact", p.contact], ["Vendor", p.vendor], ["Object ID", p.object_id],
+          ["MAC address", p.mac_address], ["IP addresses", addresses(p)], ["UUID", p.uuid],
+          ["Polled over", (p.protocol || "").toUpperCase()]
+        ].concat(capabilityRows(p.capabilities || {})).forEach(function (row) {
+          if (row[1]) {
+            identity.appendChild(el("tr", {}, [el("td", {}, [row[0]]), el("td", {}, [row[1]])]));
+          }
+        });
+        stamp();
+      }).catch(function (err) {
+        document.getElementById("updated").textContent = "Update failed: " + err.message;
+      });
+    }
+
+    refresh();
+    setInterval(refresh, REFRESH_MS);
+  }
+
+  return { fleetPage: fleetPage, printerPage: printerPage };
+})();